@@ -37,6 +37,9 @@ func (c *Client) SetBucketEncryption(ctx context.Context, bucketName string, con
 	if config == nil {
 		return errInvalidArgument("configuration cannot be empty")
 	}
+	if err := config.Validate(); err != nil {
+		return errInvalidArgument(err.Error())
+	}
 
 	buf, err := xml.Marshal(config)
 	if err != nil {
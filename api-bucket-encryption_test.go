@@ -0,0 +1,109 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/sse"
+)
+
+func TestBucketEncryption(t *testing.T) {
+	var stored []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["encryption"]; !ok || r.URL.Path != "/bucket/" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			stored, _ = io.ReadAll(r.Body)
+		case http.MethodGet:
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				io.WriteString(w, `<Error><Code>ServerSideEncryptionConfigurationNotFoundError</Code><Message>The server side encryption configuration was not found</Message></Error>`)
+				return
+			}
+			w.Write(stored)
+		case http.MethodDelete:
+			stored = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1", MaxRetries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	config := sse.NewConfigurationSSEKMS("my-key")
+	config.Rules[0].BucketKeyEnabled = true
+	if err = clnt.SetBucketEncryption(ctx, "bucket", config); err != nil {
+		t.Fatal(err)
+	}
+	got, err := clnt.GetBucketEncryption(ctx, "bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Rules, config.Rules) {
+		t.Fatalf("Expected rules %+v, got %+v", config.Rules, got.Rules)
+	}
+
+	if err = clnt.RemoveBucketEncryption(ctx, "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = clnt.GetBucketEncryption(ctx, "bucket"); ToErrorResponse(err).Code != "ServerSideEncryptionConfigurationNotFoundError" {
+		t.Fatalf("Expected missing encryption configuration, got %v", err)
+	}
+
+	invalid := []*sse.Configuration{
+		{},
+		{Rules: []sse.Rule{{Apply: sse.ApplySSEByDefault{SSEAlgorithm: "DES"}}}},
+		{Rules: []sse.Rule{{Apply: sse.ApplySSEByDefault{SSEAlgorithm: sse.AlgorithmAES256, KmsMasterKeyID: "my-key"}}}},
+		{Rules: []sse.Rule{{Apply: sse.ApplySSEByDefault{SSEAlgorithm: sse.AlgorithmAES256}, BucketKeyEnabled: true}}},
+		{Rules: []sse.Rule{{Apply: sse.ApplySSEByDefault{SSEAlgorithm: sse.AlgorithmKMSDSSE, KmsMasterKeyID: "my-key"}, BucketKeyEnabled: true}}},
+	}
+	for i, config := range invalid {
+		if err = clnt.SetBucketEncryption(ctx, "bucket", config); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Fatalf("Test %d: expected InvalidArgument, got %v", i+1, err)
+		}
+	}
+	if stored != nil {
+		t.Fatal("Expected invalid configurations not to be sent")
+	}
+
+	// KMS rules without a key ID use the AWS managed or server default key.
+	for _, algorithm := range []string{sse.AlgorithmKMS, sse.AlgorithmKMSDSSE} {
+		config := &sse.Configuration{Rules: []sse.Rule{{Apply: sse.ApplySSEByDefault{SSEAlgorithm: algorithm}}}}
+		if err = clnt.SetBucketEncryption(ctx, "bucket", config); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dsse := &sse.Configuration{Rules: []sse.Rule{{Apply: sse.ApplySSEByDefault{SSEAlgorithm: sse.AlgorithmKMSDSSE, KmsMasterKeyID: "my-key"}}}}
+	if err = clnt.SetBucketEncryption(ctx, "bucket", dsse); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -81,6 +81,16 @@ func (c *Client) putBucketLifecycle(ctx context.Context, bucketName string, buf
 	return nil
 }
 
+// RemoveBucketLifecycle removes the lifecycle configuration of a bucket,
+// this is the same as calling SetBucketLifecycle with an empty configuration.
+func (c *Client) RemoveBucketLifecycle(ctx context.Context, bucketName string) error {
+	// Input validation.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		return err
+	}
+	return c.removeBucketLifecycle(ctx, bucketName)
+}
+
 // Remove lifecycle from a bucket.
 func (c *Client) removeBucketLifecycle(ctx context.Context, bucketName string) error {
 	// Get resources properly escaped and lined up before
@@ -98,6 +108,11 @@ func (c *Client) removeBucketLifecycle(ctx context.Context, bucketName string) e
 	if err != nil {
 		return err
 	}
+	if resp != nil {
+		if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+			return httpRespToErrorResponse(resp, bucketName, "")
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,141 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
+func TestBucketLifecycle(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		config []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["lifecycle"]; !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			config, _ = io.ReadAll(r.Body)
+		case http.MethodGet:
+			if config == nil {
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusNotFound)
+				io.WriteString(w, `<Error><Code>NoSuchLifecycleConfiguration</Code><Message>The lifecycle configuration does not exist</Message></Error>`)
+				return
+			}
+			w.Write(config)
+		case http.MethodDelete:
+			config = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date := lifecycle.ExpirationDate{Time: time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	want := &lifecycle.Configuration{
+		Rules: []lifecycle.Rule{
+			{
+				ID:     "expire-logs",
+				Status: "Enabled",
+				RuleFilter: lifecycle.Filter{
+					Prefix: "logs/",
+				},
+				Expiration: lifecycle.Expiration{Days: 30},
+				AbortIncompleteMultipartUpload: lifecycle.AbortIncompleteMultipartUpload{
+					DaysAfterInitiation: 7,
+				},
+			},
+			{
+				ID:     "archive-tagged",
+				Status: "Enabled",
+				RuleFilter: lifecycle.Filter{
+					And: lifecycle.And{
+						Prefix: "data/",
+						Tags:   []lifecycle.Tag{{Key: "class", Value: "archive"}, {Key: "team", Value: "storage"}},
+					},
+				},
+				Transition: lifecycle.Transition{Days: 90, StorageClass: "GLACIER"},
+				NoncurrentVersionExpiration: lifecycle.NoncurrentVersionExpiration{
+					NoncurrentDays: 60,
+				},
+			},
+			{
+				ID:     "expire-on-date",
+				Status: "Disabled",
+				RuleFilter: lifecycle.Filter{
+					Tag: lifecycle.Tag{Key: "temporary", Value: "true"},
+				},
+				Expiration: lifecycle.Expiration{Date: date},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	if err = clnt.SetBucketLifecycle(ctx, "bucket", want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := clnt.GetBucketLifecycle(ctx, "bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantXML, err := xml.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotXML, err := xml.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(gotXML) != string(wantXML) {
+		t.Fatalf("Expected lifecycle %s, got %s", wantXML, gotXML)
+	}
+	if len(got.Rules) != 3 || len(got.Rules[1].RuleFilter.And.Tags) != 2 || got.Rules[2].RuleFilter.Tag.Key != "temporary" {
+		t.Fatalf("Unexpected lifecycle rules %+v", got.Rules)
+	}
+	if !got.Rules[2].Expiration.Date.Equal(date.Time) {
+		t.Fatalf("Expected expiration date %v, got %v", date.Time, got.Rules[2].Expiration.Date)
+	}
+
+	if err = clnt.RemoveBucketLifecycle(ctx, "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = clnt.GetBucketLifecycle(ctx, "bucket"); ToErrorResponse(err).Code != "NoSuchLifecycleConfiguration" {
+		t.Fatalf("Expected NoSuchLifecycleConfiguration, got %v", err)
+	}
+}
@@ -37,6 +37,9 @@ func (c *Client) SetBucketNotification(ctx context.Context, bucketName string, c
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
 		return err
 	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
 
 	// Get resources properly escaped and lined up before
 	// using them in http request.
@@ -119,12 +122,38 @@ func processBucketNotificationResponse(bucketName string, resp *http.Response) (
 	return bucketNotification, nil
 }
 
+// ParseNotificationEvent parses a bucket notification payload, such as the
+// message body delivered to an SQS queue or the request body sent to a
+// webhook target, into notification.Info. Payloads without any records,
+// like the Amazon S3 test event, are returned with empty Records.
+func ParseNotificationEvent(data []byte) (notification.Info, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return notification.Info{}, errInvalidArgument("Notification event payload cannot be empty.")
+	}
+	var payload struct {
+		Records []notification.Event
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return notification.Info{}, err
+	}
+	return notification.Info{Records: payload.Records}, nil
+}
+
 // ListenNotification listen for all events, this is a MinIO specific API
 func (c *Client) ListenNotification(ctx context.Context, prefix, suffix string, events []string) <-chan notification.Info {
 	return c.ListenBucketNotification(ctx, "", prefix, suffix, events)
 }
 
 // ListenBucketNotification listen for bucket events, this is a MinIO specific API
+//
+// Events are streamed over a long-lived GET request as JSON lines and sent
+// on the returned channel, each notification.Info carries the S3 records of
+// one event. The connection is re-established with backoff whenever the
+// server closes it or the network fails, until ctx is cancelled. Errors
+// returned by the server, such as an invalid bucket or access denied, are
+// sent on the channel and end the listener, the channel is closed when the
+// listener exits.
 func (c *Client) ListenBucketNotification(ctx context.Context, bucketName, prefix, suffix string, events []string) <-chan notification.Info {
 	notificationInfoCh := make(chan notification.Info, 1)
 	const notificationCapacity = 4 * 1024 * 1024
@@ -172,7 +201,14 @@ func (c *Client) ListenBucketNotification(ctx context.Context, bucketName, prefi
 		urlValues["events"] = events
 
 		// Wait on the jitter retry loop.
-		for range c.newRetryTimerContinous(time.Second, time.Second*30, MaxJitter, retryDoneCh) {
+		retryCh := c.newRetryTimerContinous(time.Second, time.Second*30, MaxJitter, retryDoneCh)
+		for {
+			select {
+			case <-retryCh:
+			case <-ctx.Done():
+				return
+			}
+
 			// Execute GET on bucket to list objects.
 			resp, err := c.executeMethod(ctx, http.MethodGet, requestMetadata{
 				bucketName:       bucketName,
@@ -180,11 +216,19 @@ func (c *Client) ListenBucketNotification(ctx context.Context, bucketName, prefi
 				contentSHA256Hex: emptySHA256Hex,
 			})
 			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
 				select {
 				case notificationInfoCh <- notification.Info{
 					Err: err,
 				}:
 				case <-ctx.Done():
+					return
+				}
+				// Network errors are transient, reconnect after backoff.
+				if _, ok := err.(ErrorResponse); !ok {
+					continue
 				}
 				return
 			}
@@ -219,9 +263,9 @@ func (c *Client) ListenBucketNotification(ctx context.Context, bucketName, prefi
 						Err: err,
 					}:
 					case <-ctx.Done():
+						closeResponse(resp)
 						return
 					}
-					closeResponse(resp)
 					continue
 				}
 
@@ -245,6 +289,7 @@ func (c *Client) ListenBucketNotification(ctx context.Context, bucketName, prefi
 					Err: err,
 				}:
 				case <-ctx.Done():
+					closeResponse(resp)
 					return
 				}
 			}
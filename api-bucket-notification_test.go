@@ -0,0 +1,206 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/notification"
+)
+
+// Sample payload delivered by Amazon S3 to an SQS queue.
+const sqsNotificationPayload = `{
+  "Records": [
+    {
+      "eventVersion": "2.1",
+      "eventSource": "aws:s3",
+      "awsRegion": "us-west-2",
+      "eventTime": "2024-01-02T03:04:05.000Z",
+      "eventName": "ObjectCreated:Put",
+      "userIdentity": {"principalId": "AWS:AIDAEXAMPLE"},
+      "requestParameters": {"sourceIPAddress": "127.0.0.1"},
+      "responseElements": {"x-amz-request-id": "C3D13FE58DE4C810"},
+      "s3": {
+        "s3SchemaVersion": "1.0",
+        "configurationId": "testConfigRule",
+        "bucket": {
+          "name": "mybucket",
+          "ownerIdentity": {"principalId": "A3NL1KOZZKExample"},
+          "arn": "arn:aws:s3:::mybucket"
+        },
+        "object": {
+          "key": "HappyFace.jpg",
+          "size": 1024,
+          "eTag": "d41d8cd98f00b204e9800998ecf8427e",
+          "versionId": "096fKKXTRTtl3on89fVO.nfljtsv6qko",
+          "sequencer": "0055AED6DCD90281E5"
+        }
+      }
+    }
+  ]
+}`
+
+// Sample payload delivered by MinIO to a webhook target.
+const webhookNotificationPayload = `{
+  "EventName": "s3:ObjectRemoved:Delete",
+  "Key": "mybucket/photos/cat.png",
+  "Records": [
+    {
+      "eventVersion": "2.0",
+      "eventSource": "minio:s3",
+      "awsRegion": "",
+      "eventTime": "2024-01-02T03:04:05.000Z",
+      "eventName": "s3:ObjectRemoved:Delete",
+      "userIdentity": {"principalId": "minioadmin"},
+      "requestParameters": {"principalId": "minioadmin", "region": "", "sourceIPAddress": "127.0.0.1"},
+      "responseElements": {"x-amz-request-id": "17A1B2C3D4E5F607", "x-minio-origin-endpoint": "http://127.0.0.1:9000"},
+      "s3": {
+        "s3SchemaVersion": "1.0",
+        "configurationId": "Config",
+        "bucket": {
+          "name": "mybucket",
+          "ownerIdentity": {"principalId": "minioadmin"},
+          "arn": "arn:aws:s3:::mybucket"
+        },
+        "object": {
+          "key": "photos%2Fcat.png",
+          "sequencer": "17A1B2C3D4E5F608"
+        }
+      },
+      "source": {"host": "127.0.0.1", "port": "", "userAgent": "MinIO (linux; amd64) minio-go/v7.0.85"}
+    }
+  ]
+}`
+
+func TestParseNotificationEvent(t *testing.T) {
+	info, err := ParseNotificationEvent([]byte(sqsNotificationPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(info.Records))
+	}
+	record := info.Records[0]
+	if record.Type() != notification.ObjectCreatedPut {
+		t.Fatalf("Expected %s, got %s", notification.ObjectCreatedPut, record.Type())
+	}
+	if record.S3.Bucket.Name != "mybucket" || record.S3.Object.Key != "HappyFace.jpg" {
+		t.Fatalf("Unexpected bucket/object %s/%s", record.S3.Bucket.Name, record.S3.Object.Key)
+	}
+	if record.S3.Object.Size != 1024 || record.S3.Object.VersionID != "096fKKXTRTtl3on89fVO.nfljtsv6qko" {
+		t.Fatalf("Unexpected object metadata %+v", record.S3.Object)
+	}
+
+	info, err = ParseNotificationEvent([]byte(webhookNotificationPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(info.Records))
+	}
+	record = info.Records[0]
+	if record.Type() != notification.ObjectRemovedDelete {
+		t.Fatalf("Expected %s, got %s", notification.ObjectRemovedDelete, record.Type())
+	}
+	if record.Source.Host != "127.0.0.1" {
+		t.Fatalf("Expected source host 127.0.0.1, got %s", record.Source.Host)
+	}
+
+	// Amazon S3 test events carry no records.
+	info, err = ParseNotificationEvent([]byte(`{"Service":"Amazon S3","Event":"s3:TestEvent","Bucket":"mybucket"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Records) != 0 {
+		t.Fatalf("Expected no records, got %d", len(info.Records))
+	}
+
+	for _, payload := range []string{"", "   ", "not-json", `{"Records": {}}`} {
+		if _, err = ParseNotificationEvent([]byte(payload)); err == nil {
+			t.Fatalf("Expected error for payload %q", payload)
+		}
+	}
+}
+
+func TestListenBucketNotification(t *testing.T) {
+	var connections int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/bucket/" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("prefix") != "photos/" || q.Get("suffix") != ".jpg" || len(q["events"]) != 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// Each connection emits a ping and an event frame, then closes.
+		n := atomic.AddInt32(&connections, 1)
+		fmt.Fprintln(w, `{"Records":null}`)
+		fmt.Fprintf(w, `{"Records":[{"eventName":"s3:ObjectCreated:Put","s3":{"bucket":{"name":"bucket"},"object":{"key":"photos/%d.jpg","size":%d}}}]}`+"\n", n, n)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Creds:  credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	events := []string{"s3:ObjectCreated:*", "s3:ObjectRemoved:*"}
+	ch := clnt.ListenBucketNotification(ctx, "bucket", "photos/", ".jpg", events)
+	for i := 1; i <= 2; i++ {
+		info, ok := <-ch
+		if !ok {
+			t.Fatal("Expected notification channel to stay open")
+		}
+		if info.Err != nil {
+			t.Fatal(info.Err)
+		}
+		if len(info.Records) != 1 {
+			t.Fatalf("Expected 1 record, got %d", len(info.Records))
+		}
+		record := info.Records[0]
+		if record.EventName != "s3:ObjectCreated:Put" {
+			t.Fatalf("Expected event s3:ObjectCreated:Put, got %s", record.EventName)
+		}
+		// The second event is only sent after reconnecting.
+		if key := fmt.Sprintf("photos/%d.jpg", i); record.S3.Object.Key != key || record.S3.Object.Size != int64(i) {
+			t.Fatalf("Expected object %s of size %d, got %s of size %d", key, i, record.S3.Object.Key, record.S3.Object.Size)
+		}
+	}
+
+	cancel()
+	for info := range ch {
+		if info.Err != nil {
+			t.Fatalf("Unexpected error after cancel: %v", info.Err)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package minio
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/url"
@@ -26,7 +27,9 @@ import (
 	"github.com/minio/minio-go/v7/pkg/s3utils"
 )
 
-// SetBucketPolicy sets the access permissions on an existing bucket.
+// SetBucketPolicy sets the access permissions on an existing bucket, policy
+// is the raw JSON policy document which is sent as is. An empty policy
+// removes the bucket policy.
 func (c *Client) SetBucketPolicy(ctx context.Context, bucketName, policy string) error {
 	// Input validation.
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
@@ -38,6 +41,10 @@ func (c *Client) SetBucketPolicy(ctx context.Context, bucketName, policy string)
 		return c.removeBucketPolicy(ctx, bucketName)
 	}
 
+	if !json.Valid([]byte(policy)) {
+		return errInvalidArgument("Bucket policy is not a well-formed JSON document.")
+	}
+
 	// Save the updated policies.
 	return c.putBucketPolicy(ctx, bucketName, policy)
 }
@@ -70,6 +77,15 @@ func (c *Client) putBucketPolicy(ctx context.Context, bucketName, policy string)
 	return nil
 }
 
+// RemoveBucketPolicy removes the policy of an existing bucket.
+func (c *Client) RemoveBucketPolicy(ctx context.Context, bucketName string) error {
+	// Input validation.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		return err
+	}
+	return c.removeBucketPolicy(ctx, bucketName)
+}
+
 // Removes all policies on a bucket.
 func (c *Client) removeBucketPolicy(ctx context.Context, bucketName string) error {
 	// Get resources properly escaped and lined up before
@@ -88,14 +104,16 @@ func (c *Client) removeBucketPolicy(ctx context.Context, bucketName string) erro
 		return err
 	}
 
-	if resp.StatusCode != http.StatusNoContent {
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
 		return httpRespToErrorResponse(resp, bucketName, "")
 	}
 
 	return nil
 }
 
-// GetBucketPolicy returns the current policy
+// GetBucketPolicy returns the current raw JSON policy document of the
+// bucket as stored by the server, an empty string is returned when the
+// bucket has no policy.
 func (c *Client) GetBucketPolicy(ctx context.Context, bucketName string) (string, error) {
 	// Input validation.
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
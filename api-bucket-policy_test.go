@@ -0,0 +1,107 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+func TestBucketPolicyRawJSON(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		stored string
+		puts   int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bucket/" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, ok := r.URL.Query()["policy"]; !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			puts++
+			stored = string(body)
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			if stored == "" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`<Error><Code>NoSuchBucketPolicy</Code><Message>The bucket policy does not exist</Message></Error>`))
+				return
+			}
+			w.Write([]byte(stored))
+		case http.MethodDelete:
+			stored = ""
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Creds:  credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::bucket/public/*"],"Condition":{"IpAddress":{"aws:SourceIp":"10.0.0.0/8"}}}]}`
+	if err = clnt.SetBucketPolicy(context.Background(), "bucket", policy); err != nil {
+		t.Fatal(err)
+	}
+	got, err := clnt.GetBucketPolicy(context.Background(), "bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != policy {
+		t.Fatalf("Expected policy %s, got %s", policy, got)
+	}
+
+	if err = clnt.SetBucketPolicy(context.Background(), "bucket", `{"Version":"2012-10-17",`); err == nil {
+		t.Fatal("Expected malformed policy to fail")
+	}
+	if puts != 1 {
+		t.Fatalf("Expected malformed policy not to be sent, got %d PUT requests", puts)
+	}
+
+	if err = clnt.RemoveBucketPolicy(context.Background(), "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = clnt.GetBucketPolicy(context.Background(), "bucket"); err != nil || got != "" {
+		t.Fatalf("Expected no policy, got %q, %v", got, err)
+	}
+}
@@ -32,6 +32,9 @@ func (c *Client) SetBucketVersioning(ctx context.Context, bucketName string, con
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
 		return err
 	}
+	if err := config.validate(); err != nil {
+		return err
+	}
 
 	buf, err := xml.Marshal(config)
 	if err != nil {
@@ -109,6 +112,29 @@ func (b BucketVersioningConfiguration) Suspended() bool {
 	return b.Status == Suspended
 }
 
+// validate verifies the versioning configuration before it is sent.
+func (b BucketVersioningConfiguration) validate() error {
+	switch b.Status {
+	case Enabled, Suspended:
+	default:
+		return errInvalidArgument("Versioning status must be either " + Enabled + " or " + Suspended)
+	}
+	switch b.MFADelete {
+	case "", "Enabled", "Disabled":
+	default:
+		return errInvalidArgument("MFADelete must be either Enabled or Disabled")
+	}
+	if (len(b.ExcludedPrefixes) > 0 || b.ExcludeFolders) && !b.Enabled() {
+		return errInvalidArgument("Excluded prefixes and folders require versioning to be " + Enabled)
+	}
+	for _, p := range b.ExcludedPrefixes {
+		if p.Prefix == "" {
+			return errInvalidArgument("Excluded prefix cannot be empty")
+		}
+	}
+	return nil
+}
+
 // GetBucketVersioning gets the versioning configuration on
 // an existing bucket with a context to control cancellations and timeouts.
 func (c *Client) GetBucketVersioning(ctx context.Context, bucketName string) (BucketVersioningConfiguration, error) {
@@ -0,0 +1,107 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestBucketVersioning(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		config []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["versioning"]; !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			config, _ = io.ReadAll(r.Body)
+		case http.MethodGet:
+			w.Write(config)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	err = clnt.SetBucketVersioning(ctx, "bucket", BucketVersioningConfiguration{
+		Status:           Enabled,
+		ExcludedPrefixes: []ExcludedPrefix{{Prefix: "tmp/"}},
+		ExcludeFolders:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := clnt.GetBucketVersioning(ctx, "bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Enabled() || len(cfg.ExcludedPrefixes) != 1 || cfg.ExcludedPrefixes[0].Prefix != "tmp/" || !cfg.ExcludeFolders {
+		t.Fatalf("Unexpected versioning configuration %+v", cfg)
+	}
+
+	if err = clnt.SuspendVersioning(ctx, "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = clnt.GetBucketVersioning(ctx, "bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Suspended() {
+		t.Fatalf("Expected versioning to be suspended, got %+v", cfg)
+	}
+}
+
+func TestBucketVersioningConfigurationValidate(t *testing.T) {
+	testCases := []struct {
+		config  BucketVersioningConfiguration
+		wantErr bool
+	}{
+		{BucketVersioningConfiguration{Status: Enabled}, false},
+		{BucketVersioningConfiguration{Status: Suspended}, false},
+		{BucketVersioningConfiguration{Status: Enabled, MFADelete: "Disabled"}, false},
+		{BucketVersioningConfiguration{Status: ""}, true},
+		{BucketVersioningConfiguration{Status: "enabled"}, true},
+		{BucketVersioningConfiguration{Status: Enabled, MFADelete: "On"}, true},
+		{BucketVersioningConfiguration{Status: Suspended, ExcludedPrefixes: []ExcludedPrefix{{Prefix: "tmp/"}}}, true},
+		{BucketVersioningConfiguration{Status: Suspended, ExcludeFolders: true}, true},
+		{BucketVersioningConfiguration{Status: Enabled, ExcludedPrefixes: []ExcludedPrefix{{}}}, true},
+	}
+	for i, testCase := range testCases {
+		err := testCase.config.validate()
+		if (err != nil) != testCase.wantErr {
+			t.Errorf("Test %d: want error %v, got %v", i+1, testCase.wantErr, err)
+		}
+	}
+}
@@ -30,6 +30,19 @@ import (
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7/pkg/encrypt"
 	"github.com/minio/minio-go/v7/pkg/s3utils"
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+// Directive tells a copy whether the metadata or tags of the destination
+// are copied from the source or replaced by the ones of the request.
+type Directive string
+
+const (
+	// CopyDirective copies the metadata or tags of the source object.
+	CopyDirective Directive = "COPY"
+	// ReplaceDirective replaces the metadata or tags of the source object
+	// with the ones provided in the request.
+	ReplaceDirective Directive = "REPLACE"
 )
 
 // CopyDestOptions represents options specified by user for CopyObject/ComposeObject APIs
@@ -46,6 +59,9 @@ type CopyDestOptions struct {
 	// if needed. If nil is passed, and if only a single source (of any
 	// size) is provided in the ComposeObject call, then metadata from the
 	// source is copied to the destination.
+	// Keys naming the same header, e.g. "color" and "X-Amz-Meta-Color",
+	// are rejected with an invalid argument error. Previously all but
+	// one of them were silently dropped.
 	// if no user-metadata is provided, it is copied from source
 	// (when there is only once source object in the compose
 	// request)
@@ -57,12 +73,24 @@ type CopyDestOptions struct {
 	// set.
 	ReplaceMetadata bool
 
+	// ContentType of the destination, only used when ReplaceMetadata is
+	// true. If empty the Content-Type of the source object is preserved.
+	ContentType string
+
 	// `userTags` is the user defined object tags to be set on destination.
 	// This will be set only if the `replaceTags` field is set to true.
 	// Otherwise this field is ignored
 	UserTags    map[string]string
 	ReplaceTags bool
 
+	// MetadataDirective and TaggingDirective explicitly select whether
+	// the metadata (UserMetadata and ContentType) and tags (UserTags) of
+	// the destination are copied from the source or replaced, REPLACE is
+	// the same as setting ReplaceMetadata or ReplaceTags. Left empty the
+	// Replace fields decide, setting COPY along with them is an error.
+	MetadataDirective Directive
+	TaggingDirective  Directive
+
 	// Specifies whether you want to apply a Legal Hold to the copied object.
 	LegalHold LegalHoldStatus
 
@@ -75,32 +103,16 @@ type CopyDestOptions struct {
 	Progress io.Reader
 }
 
-// Process custom-metadata to remove a `x-amz-meta-` prefix if
-// present and validate that keys are distinct (after this
-// prefix removal).
-func filterCustomMeta(userMeta map[string]string) map[string]string {
-	m := make(map[string]string)
-	for k, v := range userMeta {
-		if strings.HasPrefix(strings.ToLower(k), "x-amz-meta-") {
-			k = k[len("x-amz-meta-"):]
-		}
-		if _, ok := m[k]; ok {
-			continue
-		}
-		m[k] = v
-	}
-	return m
-}
-
 // Marshal converts all the CopyDestOptions into their
 // equivalent HTTP header representation
 func (opts CopyDestOptions) Marshal(header http.Header) {
-	const replaceDirective = "REPLACE"
-	if opts.ReplaceTags {
-		header.Set(amzTaggingHeaderDirective, replaceDirective)
+	if opts.replaceTags() {
+		header.Set(amzTaggingHeaderDirective, string(ReplaceDirective))
 		if tags := s3utils.TagEncode(opts.UserTags); tags != "" {
 			header.Set(amzTaggingHeader, tags)
 		}
+	} else if opts.TaggingDirective == CopyDirective {
+		header.Set(amzTaggingHeaderDirective, string(CopyDirective))
 	}
 
 	if opts.LegalHold != LegalHoldStatus("") {
@@ -116,18 +128,45 @@ func (opts CopyDestOptions) Marshal(header http.Header) {
 		opts.Encryption.Marshal(header)
 	}
 
-	if opts.ReplaceMetadata {
-		header.Set("x-amz-metadata-directive", replaceDirective)
-		for k, v := range filterCustomMeta(opts.UserMetadata) {
-			if isAmzHeader(k) || isStandardHeader(k) || isStorageClassHeader(k) || isMinioHeader(k) {
-				header.Set(k, v)
-			} else {
-				header.Set("x-amz-meta-"+k, v)
-			}
+	if opts.replaceMetadata() {
+		header.Set("x-amz-metadata-directive", string(ReplaceDirective))
+		for k, v := range opts.UserMetadata {
+			header.Set(userMetadataHeaderKey(k), v)
+		}
+		if opts.ContentType != "" {
+			header.Set("Content-Type", opts.ContentType)
 		}
+	} else if opts.MetadataDirective == CopyDirective {
+		header.Set("x-amz-metadata-directive", string(CopyDirective))
 	}
 }
 
+// replaceMetadata returns true if the metadata of the destination
+// replaces the metadata of the source.
+func (opts CopyDestOptions) replaceMetadata() bool {
+	return opts.ReplaceMetadata || opts.MetadataDirective == ReplaceDirective
+}
+
+// replaceTags returns true if the tags of the destination replace
+// the tags of the source.
+func (opts CopyDestOptions) replaceTags() bool {
+	return opts.ReplaceTags || opts.TaggingDirective == ReplaceDirective
+}
+
+// hasContentType returns true if the destination sets its Content-Type
+// either through ContentType or the user metadata.
+func (opts CopyDestOptions) hasContentType() bool {
+	if opts.ContentType != "" {
+		return true
+	}
+	for k := range opts.UserMetadata {
+		if strings.EqualFold(k, "Content-Type") {
+			return true
+		}
+	}
+	return false
+}
+
 // toDestinationInfo returns a validated copyOptions object.
 func (opts CopyDestOptions) validate() (err error) {
 	// Input validation.
@@ -140,11 +179,38 @@ func (opts CopyDestOptions) validate() (err error) {
 	if opts.Progress != nil && opts.Size < 0 {
 		return errInvalidArgument("For progress bar effective size needs to be specified")
 	}
+	for _, directive := range []Directive{opts.MetadataDirective, opts.TaggingDirective} {
+		if directive != "" && directive != CopyDirective && directive != ReplaceDirective {
+			return errInvalidArgument("Unsupported directive " + string(directive))
+		}
+	}
+	if opts.ReplaceMetadata && opts.MetadataDirective == CopyDirective {
+		return errInvalidArgument("ReplaceMetadata cannot be used with the COPY metadata directive")
+	}
+	if opts.ReplaceTags && opts.TaggingDirective == CopyDirective {
+		return errInvalidArgument("ReplaceTags cannot be used with the COPY tagging directive")
+	}
+	if opts.replaceMetadata() {
+		if err = validateUserMetadata(opts.UserMetadata); err != nil {
+			return err
+		}
+	}
+	if len(opts.UserTags) != 0 {
+		if _, err = tags.MapToObjectTags(opts.UserTags); err != nil {
+			return errInvalidArgument(err.Error())
+		}
+	}
 	return nil
 }
 
 // CopySrcOptions represents a source object to be copied, using
-// server-side copying APIs.
+// server-side copying APIs. Set VersionID to copy a specific version
+// of the source object, the server returns an error if the version
+// does not exist.
+//
+// MatchETag, NoMatchETag, MatchModifiedSince and MatchUnmodifiedSince
+// are sent as the x-amz-copy-source-if-* conditions, the copy fails
+// with an error matching ErrPreconditionFailed when they are not met.
 type CopySrcOptions struct {
 	Bucket, Object       string
 	VersionID            string
@@ -174,10 +240,10 @@ func (opts CopySrcOptions) Marshal(header http.Header) {
 	}
 
 	if !opts.MatchModifiedSince.IsZero() {
-		header.Set("x-amz-copy-source-if-modified-since", opts.MatchModifiedSince.Format(http.TimeFormat))
+		header.Set("x-amz-copy-source-if-modified-since", opts.MatchModifiedSince.UTC().Format(http.TimeFormat))
 	}
 	if !opts.MatchUnmodifiedSince.IsZero() {
-		header.Set("x-amz-copy-source-if-unmodified-since", opts.MatchUnmodifiedSince.Format(http.TimeFormat))
+		header.Set("x-amz-copy-source-if-unmodified-since", opts.MatchUnmodifiedSince.UTC().Format(http.TimeFormat))
 	}
 
 	if opts.Encryption != nil {
@@ -289,6 +355,26 @@ func (c *Client) copyObjectDo(ctx context.Context, srcBucket, srcObject, destBuc
 func (c *Client) copyObjectPartDo(ctx context.Context, srcBucket, srcObject, destBucket, destObject, uploadID string,
 	partID int, startOffset, length int64, metadata map[string]string,
 ) (p CompletePart, err error) {
+	// Input validation.
+	if err = s3utils.CheckValidBucketName(srcBucket); err != nil {
+		return p, err
+	}
+	if err = s3utils.CheckValidObjectName(srcObject); err != nil {
+		return p, err
+	}
+	if err = s3utils.CheckValidBucketName(destBucket); err != nil {
+		return p, err
+	}
+	if err = s3utils.CheckValidObjectName(destObject); err != nil {
+		return p, err
+	}
+	if uploadID == "" {
+		return p, errInvalidArgument("uploadID cannot be empty.")
+	}
+	if partID < 1 || partID > maxPartsCount {
+		return p, errInvalidArgument(fmt.Sprintf("Part number %d must be between 1 and %d.", partID, maxPartsCount))
+	}
+
 	headers := make(http.Header)
 
 	// Set source
@@ -297,6 +383,9 @@ func (c *Client) copyObjectPartDo(ctx context.Context, srcBucket, srcObject, des
 	if startOffset < 0 {
 		return p, errInvalidArgument("startOffset must be non-negative")
 	}
+	if length == 0 {
+		return p, errInvalidArgument("length cannot be zero, use a negative length to copy the whole object")
+	}
 
 	if length >= 0 {
 		headers.Set("x-amz-copy-source-range", fmt.Sprintf("bytes=%d-%d", startOffset, startOffset+length-1))
@@ -379,6 +468,10 @@ func (c *Client) uploadPartCopy(ctx context.Context, bucket, object, uploadID st
 // and concatenates them into a new object using only server-side copying
 // operations. Optionally takes progress reader hook for applications to
 // look at current progress.
+//
+// A single source of up to 5GiB is copied with CopyObject, otherwise the
+// sources are split into evenly sized parts of at least 5MiB which are
+// copied with UploadPartCopy, each pinned to the ETag of its source.
 func (c *Client) ComposeObject(ctx context.Context, dst CopyDestOptions, srcs ...CopySrcOptions) (UploadInfo, error) {
 	if len(srcs) < 1 || len(srcs) > maxPartsCount {
 		return UploadInfo{}, errInvalidArgument("There must be as least one and up to 10000 source objects.")
@@ -400,7 +493,7 @@ func (c *Client) ComposeObject(ctx context.Context, dst CopyDestOptions, srcs ..
 	var err error
 	for i, src := range srcs {
 		opts := StatObjectOptions{ServerSideEncryption: encrypt.SSE(src.Encryption), VersionID: src.VersionID}
-		srcObjectInfos[i], err = c.StatObject(context.Background(), src.Bucket, src.Object, opts)
+		srcObjectInfos[i], err = c.StatObject(ctx, src.Bucket, src.Object, opts)
 		if err != nil {
 			return UploadInfo{}, err
 		}
@@ -455,8 +548,9 @@ func (c *Client) ComposeObject(ctx context.Context, dst CopyDestOptions, srcs ..
 
 	// 1. Ensure that the object has not been changed while
 	//    we are copying data.
-	for i, src := range srcs {
-		src.MatchETag = srcObjectInfos[i].ETag
+	srcs = append([]CopySrcOptions(nil), srcs...)
+	for i := range srcs {
+		srcs[i].MatchETag = srcObjectInfos[i].ETag
 	}
 
 	// 2. Initiate a new multipart upload.
@@ -465,21 +559,28 @@ func (c *Client) ComposeObject(ctx context.Context, dst CopyDestOptions, srcs ..
 	// user-metadata is specified, and there is only one source,
 	// (only) then metadata from source is copied.
 	var userMeta map[string]string
-	if dst.ReplaceMetadata {
+	if dst.replaceMetadata() {
 		userMeta = dst.UserMetadata
 	} else {
 		userMeta = srcObjectInfos[0].UserMetadata
 	}
 
 	var userTags map[string]string
-	if dst.ReplaceTags {
+	if dst.replaceTags() {
 		userTags = dst.UserTags
 	} else {
 		userTags = srcObjectInfos[0].UserTags
 	}
 
+	// Preserve the Content-Type of the source unless set by the caller.
+	contentType := srcObjectInfos[0].ContentType
+	if dst.replaceMetadata() && dst.ContentType != "" {
+		contentType = dst.ContentType
+	}
+
 	uploadID, err := c.newUploadID(ctx, dst.Bucket, dst.Object, PutObjectOptions{
 		ServerSideEncryption: dst.Encryption,
+		ContentType:          contentType,
 		UserMetadata:         userMeta,
 		UserTags:             userTags,
 		Mode:                 dst.Mode,
@@ -515,6 +616,7 @@ func (c *Client) ComposeObject(ctx context.Context, dst CopyDestOptions, srcs ..
 			complPart, err := c.uploadPartCopy(ctx, dst.Bucket,
 				dst.Object, uploadID, partIndex, h)
 			if err != nil {
+				c.abortMultipartUpload(ctx, dst.Bucket, dst.Object, uploadID)
 				return UploadInfo{}, err
 			}
 			if dst.Progress != nil {
@@ -529,6 +631,7 @@ func (c *Client) ComposeObject(ctx context.Context, dst CopyDestOptions, srcs ..
 	uploadInfo, err := c.completeMultipartUpload(ctx, dst.Bucket, dst.Object, uploadID,
 		completeMultipartUpload{Parts: objParts}, PutObjectOptions{ServerSideEncryption: dst.Encryption})
 	if err != nil {
+		c.abortMultipartUpload(ctx, dst.Bucket, dst.Object, uploadID)
 		return UploadInfo{}, err
 	}
 
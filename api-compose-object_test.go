@@ -17,9 +17,15 @@
 package minio
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -183,3 +189,129 @@ func TestDestOptions(t *testing.T) {
 		}
 	}
 }
+
+func TestComposeObjectMultipartCopy(t *testing.T) {
+	sizes := map[string]int64{
+		"part1": 3 * gb1,
+		"part2": 4 * gb1,
+		"tail":  1024,
+	}
+	var (
+		mu       sync.Mutex
+		ranges   = make(map[int]string)
+		sources  = make(map[int]string)
+		ifMatch  = make(map[int]string)
+		aborted  bool
+		failPart int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		q := r.URL.Query()
+		object := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		switch {
+		case r.Method == http.MethodHead:
+			size, ok := sizes[object]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+			w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+			w.Header().Set("ETag", `"`+object+`-etag"`)
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>`+object+`</Key><UploadId>upload-id</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == http.MethodPut && q.Get("uploadId") == "upload-id":
+			partNumber, _ := strconv.Atoi(q.Get("partNumber"))
+			if partNumber == failPart {
+				w.WriteHeader(http.StatusInternalServerError)
+				io.WriteString(w, `<Error><Code>NotImplemented</Code><Message>copy failed</Message></Error>`)
+				return
+			}
+			ranges[partNumber] = r.Header.Get("x-amz-copy-source-range")
+			sources[partNumber] = r.Header.Get("x-amz-copy-source")
+			ifMatch[partNumber] = r.Header.Get("x-amz-copy-source-if-match")
+			io.WriteString(w, `<CopyPartResult><ETag>"part-etag"</ETag><LastModified>2024-01-01T00:00:00.000Z</LastModified></CopyPartResult>`)
+		case r.Method == http.MethodPost && q.Get("uploadId") == "upload-id":
+			io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>`+object+`</Key><ETag>"final-etag-3"</ETag></CompleteMultipartUploadResult>`)
+		case r.Method == http.MethodDelete && q.Get("uploadId") == "upload-id":
+			aborted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:     "us-east-1",
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srcs := []CopySrcOptions{
+		{Bucket: "bucket", Object: "part1"},
+		{Bucket: "bucket", Object: "part2"},
+		{Bucket: "bucket", Object: "tail"},
+	}
+	info, err := clnt.ComposeObject(context.Background(), CopyDestOptions{Bucket: "bucket", Object: "composed"}, srcs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	totalSize := sizes["part1"] + sizes["part2"] + sizes["tail"]
+	if info.Size != totalSize || info.ETag != "final-etag-3" {
+		t.Fatalf("Unexpected upload info %+v", info)
+	}
+	if srcs[0].MatchETag != "" {
+		t.Fatal("ComposeObject must not modify the sources of the caller")
+	}
+
+	// Parts must cover every source contiguously, only the last part
+	// of the composed object may be smaller than 5MiB.
+	wantParts := int(partsRequired(sizes["part1"]) + partsRequired(sizes["part2"]) + partsRequired(sizes["tail"]))
+	if len(ranges) != wantParts {
+		t.Fatalf("Expected %d parts, got %d", wantParts, len(ranges))
+	}
+	var (
+		copied     int64
+		lastSource string
+		nextStart  int64
+	)
+	for partNumber := 1; partNumber <= wantParts; partNumber++ {
+		var start, end int64
+		if _, err = fmt.Sscanf(ranges[partNumber], "bytes=%d-%d", &start, &end); err != nil {
+			t.Fatalf("Part %d: invalid range %q", partNumber, ranges[partNumber])
+		}
+		if sources[partNumber] != lastSource {
+			lastSource, nextStart = sources[partNumber], 0
+		}
+		object := strings.TrimPrefix(strings.TrimPrefix(lastSource, "/"), "bucket/")
+		if start != nextStart || end >= sizes[object] {
+			t.Fatalf("Part %d: range %q is not contiguous within %s", partNumber, ranges[partNumber], object)
+		}
+		if size := end - start + 1; size > maxPartSize || (size < absMinPartSize && partNumber < wantParts) {
+			t.Fatalf("Part %d: invalid part size %d", partNumber, size)
+		}
+		if ifMatch[partNumber] != object+"-etag" {
+			t.Fatalf("Part %d: expected copy to be pinned to the source ETag, got %q", partNumber, ifMatch[partNumber])
+		}
+		nextStart = end + 1
+		copied += end - start + 1
+	}
+	if copied != totalSize {
+		t.Fatalf("Expected %d bytes to be copied, got %d", totalSize, copied)
+	}
+	if aborted {
+		t.Fatal("Unexpected abort of a successful upload")
+	}
+
+	failPart = 2
+	if _, err = clnt.ComposeObject(context.Background(), CopyDestOptions{Bucket: "bucket", Object: "composed"}, srcs...); err == nil {
+		t.Fatal("Expected a failing part copy to fail the compose")
+	}
+	if !aborted {
+		t.Fatal("Expected the multipart upload to be aborted")
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+)
+
+// CopyFrom - copies an object stored behind another endpoint, such as a
+// different S3 service, into a new object of this client. The source is
+// downloaded with srcClient and streamed into a PutObject, large objects
+// are uploaded with multipart so at most a few parts are held in memory.
+//
+// The Content-Type, user metadata and tags of the source are preserved
+// unless dst replaces them, the same as for CopyObject. The source is
+// pinned to the ETag it had when the copy started, a source overwritten
+// in between fails the copy with ErrPreconditionFailed.
+func (c *Client) CopyFrom(ctx context.Context, srcClient *Client, dst CopyDestOptions, src CopySrcOptions) (UploadInfo, error) {
+	if srcClient == nil {
+		return UploadInfo{}, errInvalidArgument("Source client cannot be empty.")
+	}
+	if err := src.validate(); err != nil {
+		return UploadInfo{}, err
+	}
+	if err := dst.validate(); err != nil {
+		return UploadInfo{}, err
+	}
+
+	opts := GetObjectOptions{
+		ServerSideEncryption: encrypt.SSE(src.Encryption),
+		VersionID:            src.VersionID,
+	}
+	if src.MatchETag != "" {
+		opts.SetMatchETag(src.MatchETag)
+	}
+	if src.NoMatchETag != "" {
+		opts.SetMatchETagExcept(src.NoMatchETag)
+	}
+	if !src.MatchModifiedSince.IsZero() {
+		opts.SetModified(src.MatchModifiedSince)
+	}
+	if !src.MatchUnmodifiedSince.IsZero() {
+		opts.SetUnmodified(src.MatchUnmodifiedSince)
+	}
+	st, err := srcClient.StatObject(ctx, src.Bucket, src.Object, opts)
+	if err != nil {
+		return UploadInfo{}, err
+	}
+
+	size := st.Size
+	if src.MatchRange {
+		if src.End >= st.Size || src.Start < 0 {
+			return UploadInfo{}, errInvalidArgument("Source range is outside the object.")
+		}
+		size = src.End - src.Start + 1
+		if err = opts.SetRange(src.Start, src.End); err != nil {
+			return UploadInfo{}, err
+		}
+	}
+
+	putOpts := PutObjectOptions{
+		ServerSideEncryption: dst.Encryption,
+		ContentType:          st.ContentType,
+		UserMetadata:         st.UserMetadata,
+		Mode:                 dst.Mode,
+		RetainUntilDate:      dst.RetainUntilDate,
+		LegalHold:            dst.LegalHold,
+		Progress:             dst.Progress,
+	}
+	if dst.replaceMetadata() {
+		putOpts.UserMetadata = dst.UserMetadata
+		if dst.ContentType != "" {
+			putOpts.ContentType = dst.ContentType
+		}
+	}
+	if dst.replaceTags() {
+		putOpts.UserTags = dst.UserTags
+	} else if st.UserTagCount > 0 {
+		t, err := srcClient.GetObjectTagging(ctx, src.Bucket, src.Object, GetObjectTaggingOptions{VersionID: st.VersionID})
+		if err != nil {
+			return UploadInfo{}, err
+		}
+		putOpts.UserTags = t.ToMap()
+	}
+
+	// Read the same data which was stat'ed above.
+	if st.VersionID != "" {
+		opts.VersionID = st.VersionID
+	}
+	if err = opts.SetMatchETag(st.ETag); err != nil {
+		return UploadInfo{}, err
+	}
+	obj, err := srcClient.GetObject(ctx, src.Bucket, src.Object, opts)
+	if err != nil {
+		return UploadInfo{}, err
+	}
+	defer obj.Close()
+
+	// Hide io.ReaderAt and io.Seeker of the object, the source is
+	// downloaded once in a single stream.
+	return c.PutObject(ctx, dst.Bucket, dst.Object, struct{ io.Reader }{obj}, size, putOpts)
+}
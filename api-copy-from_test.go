@@ -0,0 +1,128 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCopyFrom(t *testing.T) {
+	const content = "hello, world"
+	src := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/src-bucket/object" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if _, ok := r.URL.Query()["tagging"]; ok {
+			io.WriteString(w, `<Tagging><TagSet><Tag><Key>env</Key><Value>prod</Value></Tag></TagSet></Tagging>`)
+			return
+		}
+		if match := r.Header.Get("If-Match"); match != "" && strings.Trim(match, `"`) != "source-etag" {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Amz-Meta-Color", "red")
+		w.Header().Set("X-Amz-Tagging-Count", "1")
+		w.Header().Set("ETag", `"source-etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", "12")
+		if r.Method == http.MethodGet {
+			io.WriteString(w, content)
+		}
+	}))
+	defer src.Close()
+
+	var (
+		gotHeader http.Header
+		gotBody   string
+	)
+	dst := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/dst-bucket/copy" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		gotHeader, gotBody = r.Header.Clone(), string(body)
+		w.Header().Set("ETag", `"dest-etag"`)
+	}))
+	defer dst.Close()
+
+	srcClnt, err := New(src.Listener.Addr().String(), &Options{Region: "us-east-1", Secure: true, Transport: src.Client().Transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dstClnt, err := New(dst.Listener.Addr().String(), &Options{Region: "us-east-1", Secure: true, Transport: dst.Client().Transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := dstClnt.CopyFrom(context.Background(), srcClnt,
+		CopyDestOptions{Bucket: "dst-bucket", Object: "copy"},
+		CopySrcOptions{Bucket: "src-bucket", Object: "object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ETag != "dest-etag" || info.Size != int64(len(content)) {
+		t.Fatalf("Unexpected upload info %+v", info)
+	}
+	if gotBody != content {
+		t.Fatalf("Expected body %q, got %q", content, gotBody)
+	}
+	for k, v := range map[string]string{"Content-Type": "text/plain", "X-Amz-Meta-Color": "red", "X-Amz-Tagging": "env=prod"} {
+		if got := gotHeader.Get(k); got != v {
+			t.Fatalf("Expected %s: %s, got %q", k, v, got)
+		}
+	}
+
+	// Replaced metadata and tags are not taken from the source.
+	if _, err = dstClnt.CopyFrom(context.Background(), srcClnt,
+		CopyDestOptions{
+			Bucket: "dst-bucket", Object: "copy",
+			ReplaceMetadata: true, UserMetadata: map[string]string{"Color": "blue"},
+			ReplaceTags: true, UserTags: map[string]string{"env": "dev"},
+		},
+		CopySrcOptions{Bucket: "src-bucket", Object: "object"}); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range map[string]string{"Content-Type": "text/plain", "X-Amz-Meta-Color": "blue", "X-Amz-Tagging": "env=dev"} {
+		if got := gotHeader.Get(k); got != v {
+			t.Fatalf("Expected %s: %s, got %q", k, v, got)
+		}
+	}
+
+	if _, err = dstClnt.CopyFrom(context.Background(), srcClnt,
+		CopyDestOptions{Bucket: "dst-bucket", Object: "copy"},
+		CopySrcOptions{Bucket: "src-bucket", Object: "object", MatchETag: "other-etag"}); ToErrorResponse(err).Code != "PreconditionFailed" {
+		t.Fatalf("Expected PreconditionFailed, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = dstClnt.CopyFrom(ctx, srcClnt,
+		CopyDestOptions{Bucket: "dst-bucket", Object: "copy"},
+		CopySrcOptions{Bucket: "src-bucket", Object: "object"}); err == nil {
+		t.Fatal("Expected a cancelled copy to fail")
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"context"
 	"io"
 	"net/http"
+
+	"github.com/minio/minio-go/v7/pkg/encrypt"
 )
 
 // CopyObject - copy a source object into a new object
@@ -33,6 +35,19 @@ func (c *Client) CopyObject(ctx context.Context, dst CopyDestOptions, src CopySr
 		return UploadInfo{}, err
 	}
 
+	// Replacing the metadata replaces the Content-Type as well, preserve
+	// the Content-Type of the source unless set by the caller.
+	if dst.replaceMetadata() && !dst.hasContentType() {
+		st, err := c.StatObject(ctx, src.Bucket, src.Object, StatObjectOptions{
+			ServerSideEncryption: encrypt.SSE(src.Encryption),
+			VersionID:            src.VersionID,
+		})
+		if err != nil {
+			return UploadInfo{}, err
+		}
+		dst.ContentType = st.ContentType
+	}
+
 	header := make(http.Header)
 	dst.Marshal(header)
 	src.Marshal(header)
@@ -74,3 +89,47 @@ func (c *Client) CopyObject(ctx context.Context, dst CopyDestOptions, src CopySr
 		ExpirationRuleID: ruleID,
 	}, nil
 }
+
+// Copy - copies a whole source object into a new object. Sources up to
+// 5GiB are copied with a single server side copy, larger sources with a
+// multipart upload copying the object in parts, which lifts the 5GiB
+// limit of CopyObject. The metadata and tagging directives of dst apply
+// the same way to both cases, a range of the source is copied with
+// ComposeObject.
+func (c *Client) Copy(ctx context.Context, dst CopyDestOptions, src CopySrcOptions) (UploadInfo, error) {
+	if err := src.validate(); err != nil {
+		return UploadInfo{}, err
+	}
+	if err := dst.validate(); err != nil {
+		return UploadInfo{}, err
+	}
+	if src.MatchRange {
+		return c.ComposeObject(ctx, dst, src)
+	}
+
+	st, err := c.StatObject(ctx, src.Bucket, src.Object, StatObjectOptions{
+		ServerSideEncryption: encrypt.SSE(src.Encryption),
+		VersionID:            src.VersionID,
+	})
+	if err != nil {
+		return UploadInfo{}, err
+	}
+	if st.Size > maxPartSize {
+		return c.ComposeObject(ctx, dst, src)
+	}
+
+	// Copy the source as it was stat'ed, the Content-Type is known
+	// already when the metadata is replaced.
+	if src.MatchETag == "" {
+		src.MatchETag = st.ETag
+	}
+	if dst.replaceMetadata() && !dst.hasContentType() {
+		dst.ContentType = st.ContentType
+	}
+	uploadInfo, err := c.CopyObject(ctx, dst, src)
+	if err != nil {
+		return UploadInfo{}, err
+	}
+	uploadInfo.Size = st.Size
+	return uploadInfo, nil
+}
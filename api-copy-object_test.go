@@ -0,0 +1,488 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCopyObjectSourceVersion(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		versions = make(map[string][]string) // object => contents, version id is the index
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		object := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		switch r.Method {
+		case http.MethodPut:
+			var content string
+			if source := r.Header.Get("x-amz-copy-source"); source != "" {
+				u, err := url.Parse("/" + strings.TrimPrefix(source, "/"))
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				srcVersions := versions[strings.TrimPrefix(u.Path, "/bucket/")]
+				var idx int
+				if _, err = fmt.Sscanf(u.Query().Get("versionId"), "v%d", &idx); err != nil || idx >= len(srcVersions) {
+					w.WriteHeader(http.StatusNotFound)
+					io.WriteString(w, `<Error><Code>NoSuchVersion</Code><Message>The specified version does not exist.</Message></Error>`)
+					return
+				}
+				content = srcVersions[idx]
+			} else {
+				b, _ := io.ReadAll(r.Body)
+				content = string(b)
+			}
+			versions[object] = append(versions[object], content)
+			w.Header().Set(amzVersionID, fmt.Sprintf("v%d", len(versions[object])-1))
+			if r.Header.Get("x-amz-copy-source") != "" {
+				io.WriteString(w, `<CopyObjectResult><ETag>"etag"</ETag><LastModified>2024-01-01T00:00:00.000Z</LastModified></CopyObjectResult>`)
+			}
+		case http.MethodGet:
+			objVersions := versions[object]
+			if len(objVersions) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+			w.Header().Set("ETag", `"etag"`)
+			io.WriteString(w, objVersions[len(objVersions)-1])
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, content := range []string{"first", "second", "third"} {
+		if _, err = clnt.PutObject(ctx, "bucket", "source", strings.NewReader(content), int64(len(content)), PutObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	info, err := clnt.CopyObject(ctx, CopyDestOptions{Bucket: "bucket", Object: "dest"}, CopySrcOptions{Bucket: "bucket", Object: "source", VersionID: "v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.VersionID != "v0" {
+		t.Fatalf("Expected destination version v0, got %q", info.VersionID)
+	}
+	obj, err := clnt.GetObject(ctx, "bucket", "dest", GetObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer obj.Close()
+	b, err := io.ReadAll(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, []byte("second")) {
+		t.Fatalf("Expected content of the copied version, got %q", b)
+	}
+
+	_, err = clnt.CopyObject(ctx, CopyDestOptions{Bucket: "bucket", Object: "dest"}, CopySrcOptions{Bucket: "bucket", Object: "source", VersionID: "v9"})
+	if ToErrorResponse(err).Code != "NoSuchVersion" {
+		t.Fatalf("Expected NoSuchVersion, got %v", err)
+	}
+}
+
+func TestCopyObjectReplaceMetadataContentType(t *testing.T) {
+	var (
+		mu           sync.Mutex
+		contentTypes = make(map[string]string)
+		userMeta     = make(map[string]string)
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		object := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		switch r.Method {
+		case http.MethodPut:
+			source := r.Header.Get("x-amz-copy-source")
+			if source == "" {
+				contentTypes[object] = r.Header.Get("Content-Type")
+				userMeta[object] = r.Header.Get("X-Amz-Meta-Color")
+				return
+			}
+			srcObject := strings.TrimPrefix(strings.TrimPrefix(source, "/"), "bucket/")
+			if r.Header.Get("x-amz-metadata-directive") == "REPLACE" {
+				contentType := r.Header.Get("Content-Type")
+				if contentType == "" {
+					contentType = "binary/octet-stream"
+				}
+				contentTypes[object] = contentType
+				userMeta[object] = r.Header.Get("X-Amz-Meta-Color")
+			} else {
+				contentTypes[object] = contentTypes[srcObject]
+				userMeta[object] = userMeta[srcObject]
+			}
+			io.WriteString(w, `<CopyObjectResult><ETag>"etag"</ETag><LastModified>2024-01-01T00:00:00.000Z</LastModified></CopyObjectResult>`)
+		case http.MethodHead:
+			contentType, ok := contentTypes[object]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Content-Length", "0")
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err = clnt.PutObject(ctx, "bucket", "source", strings.NewReader("hello"), 5, PutObjectOptions{
+		ContentType:  "text/plain",
+		UserMetadata: map[string]string{"Color": "red"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		dst         CopyDestOptions
+		contentType string
+	}{
+		{CopyDestOptions{Object: "kept", ReplaceMetadata: true, UserMetadata: map[string]string{"Color": "blue"}}, "text/plain"},
+		{CopyDestOptions{Object: "explicit", ReplaceMetadata: true, ContentType: "application/json"}, "application/json"},
+		{CopyDestOptions{Object: "metadata", ReplaceMetadata: true, UserMetadata: map[string]string{"Content-Type": "text/csv"}}, "text/csv"},
+		{CopyDestOptions{Object: "copied"}, "text/plain"},
+	}
+	for _, testCase := range testCases {
+		testCase.dst.Bucket = "bucket"
+		if _, err = clnt.CopyObject(ctx, testCase.dst, CopySrcOptions{Bucket: "bucket", Object: "source"}); err != nil {
+			t.Fatalf("%s: %v", testCase.dst.Object, err)
+		}
+		st, err := clnt.StatObject(ctx, "bucket", testCase.dst.Object, StatObjectOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.dst.Object, err)
+		}
+		if st.ContentType != testCase.contentType {
+			t.Errorf("%s: expected Content-Type %s, got %s", testCase.dst.Object, testCase.contentType, st.ContentType)
+		}
+	}
+	if userMeta["kept"] != "blue" {
+		t.Fatalf("Expected replaced user metadata, got %q", userMeta["kept"])
+	}
+}
+
+func TestCopyObjectDirectives(t *testing.T) {
+	type object struct {
+		contentType, color, tags string
+	}
+	var (
+		mu      sync.Mutex
+		objects = make(map[string]object)
+		headers = make(map[string]http.Header)
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		name := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		source := r.Header.Get("x-amz-copy-source")
+		if source == "" {
+			objects[name] = object{r.Header.Get("Content-Type"), r.Header.Get("X-Amz-Meta-Color"), r.Header.Get("X-Amz-Tagging")}
+			return
+		}
+		headers[name] = r.Header.Clone()
+		src := objects[strings.TrimPrefix(strings.TrimPrefix(source, "/"), "bucket/")]
+		dst := src
+		if r.Header.Get("x-amz-metadata-directive") == "REPLACE" {
+			dst.contentType, dst.color = r.Header.Get("Content-Type"), r.Header.Get("X-Amz-Meta-Color")
+		}
+		if r.Header.Get("x-amz-tagging-directive") == "REPLACE" {
+			dst.tags = r.Header.Get("X-Amz-Tagging")
+		}
+		objects[name] = dst
+		io.WriteString(w, `<CopyObjectResult><ETag>"etag"</ETag><LastModified>2024-01-01T00:00:00.000Z</LastModified></CopyObjectResult>`)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err = clnt.PutObject(ctx, "bucket", "source", strings.NewReader("hello"), 5, PutObjectOptions{
+		ContentType:  "text/plain",
+		UserMetadata: map[string]string{"Color": "red"},
+		UserTags:     map[string]string{"env": "dev"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	replaced := CopyDestOptions{
+		MetadataDirective: ReplaceDirective,
+		TaggingDirective:  ReplaceDirective,
+		ContentType:       "application/json",
+		UserMetadata:      map[string]string{"Color": "blue"},
+		UserTags:          map[string]string{"env": "prod"},
+	}
+	copied := replaced
+	copied.MetadataDirective, copied.TaggingDirective = CopyDirective, CopyDirective
+
+	testCases := []struct {
+		name      string
+		dst       CopyDestOptions
+		directive string
+		expected  object
+	}{
+		{"replaced", replaced, "REPLACE", object{"application/json", "blue", "env=prod"}},
+		{"copied", copied, "COPY", object{"text/plain", "red", "env=dev"}},
+	}
+	for _, testCase := range testCases {
+		testCase.dst.Bucket, testCase.dst.Object = "bucket", testCase.name
+		if _, err = clnt.CopyObject(ctx, testCase.dst, CopySrcOptions{Bucket: "bucket", Object: "source"}); err != nil {
+			t.Fatalf("%s: %v", testCase.name, err)
+		}
+		h := headers[testCase.name]
+		if got := h.Get("x-amz-metadata-directive"); got != testCase.directive {
+			t.Fatalf("%s: expected metadata directive %s, got %q", testCase.name, testCase.directive, got)
+		}
+		if got := h.Get("x-amz-tagging-directive"); got != testCase.directive {
+			t.Fatalf("%s: expected tagging directive %s, got %q", testCase.name, testCase.directive, got)
+		}
+		if objects[testCase.name] != testCase.expected {
+			t.Fatalf("%s: expected %+v, got %+v", testCase.name, testCase.expected, objects[testCase.name])
+		}
+	}
+
+	invalid := []CopyDestOptions{
+		{Bucket: "bucket", Object: "invalid", MetadataDirective: "MERGE"},
+		{Bucket: "bucket", Object: "invalid", ReplaceMetadata: true, MetadataDirective: CopyDirective},
+		{Bucket: "bucket", Object: "invalid", ReplaceTags: true, TaggingDirective: CopyDirective},
+	}
+	for i, dst := range invalid {
+		if _, err = clnt.CopyObject(ctx, dst, CopySrcOptions{Bucket: "bucket", Object: "source"}); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Fatalf("Test %d: expected InvalidArgument, got %v", i+1, err)
+		}
+	}
+}
+
+func TestCopyObjectSourceConditions(t *testing.T) {
+	const etag = "9b2cf535f27731c974343645a3985328"
+	modTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var header http.Header
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		failed := false
+		if match := r.Header.Get("x-amz-copy-source-if-match"); match != "" && trimEtag(match) != etag {
+			failed = true
+		}
+		if noMatch := r.Header.Get("x-amz-copy-source-if-none-match"); noMatch != "" && trimEtag(noMatch) == etag {
+			failed = true
+		}
+		if since := r.Header.Get("x-amz-copy-source-if-modified-since"); since != "" {
+			if t, err := http.ParseTime(since); err != nil || !modTime.After(t) {
+				failed = true
+			}
+		}
+		if since := r.Header.Get("x-amz-copy-source-if-unmodified-since"); since != "" {
+			if t, err := http.ParseTime(since); err != nil || modTime.After(t) {
+				failed = true
+			}
+		}
+		if failed {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			io.WriteString(w, `<Error><Code>PreconditionFailed</Code><Message>At least one of the pre-conditions you specified did not hold</Message></Error>`)
+			return
+		}
+		io.WriteString(w, `<CopyObjectResult><ETag>"`+etag+`"</ETag><LastModified>2024-01-02T00:00:00.000Z</LastModified></CopyObjectResult>`)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:     "us-east-1",
+		Secure:     true,
+		Transport:  srv.Client().Transport,
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Times are sent in GMT whatever their location.
+	local := time.FixedZone("UTC+2", 2*60*60)
+	testCases := []struct {
+		src    CopySrcOptions
+		header string
+		value  string
+		failed bool
+	}{
+		{CopySrcOptions{MatchETag: etag}, "x-amz-copy-source-if-match", etag, false},
+		{CopySrcOptions{MatchETag: "0123456789abcdef"}, "x-amz-copy-source-if-match", "0123456789abcdef", true},
+		{CopySrcOptions{NoMatchETag: "0123456789abcdef"}, "x-amz-copy-source-if-none-match", "0123456789abcdef", false},
+		{CopySrcOptions{NoMatchETag: etag}, "x-amz-copy-source-if-none-match", etag, true},
+		{CopySrcOptions{MatchModifiedSince: modTime.Add(-time.Hour).In(local)}, "x-amz-copy-source-if-modified-since", "Sun, 31 Dec 2023 23:00:00 GMT", false},
+		{CopySrcOptions{MatchModifiedSince: modTime.Add(time.Hour)}, "x-amz-copy-source-if-modified-since", "Mon, 01 Jan 2024 01:00:00 GMT", true},
+		{CopySrcOptions{MatchUnmodifiedSince: modTime.Add(time.Hour).In(local)}, "x-amz-copy-source-if-unmodified-since", "Mon, 01 Jan 2024 01:00:00 GMT", false},
+		{CopySrcOptions{MatchUnmodifiedSince: modTime.Add(-time.Hour)}, "x-amz-copy-source-if-unmodified-since", "Sun, 31 Dec 2023 23:00:00 GMT", true},
+	}
+	for i, testCase := range testCases {
+		testCase.src.Bucket, testCase.src.Object = "bucket", "source"
+		_, err = clnt.CopyObject(context.Background(), CopyDestOptions{Bucket: "bucket", Object: "dest"}, testCase.src)
+		if got := header.Get(testCase.header); got != testCase.value {
+			t.Fatalf("Test %d: expected %s %q, got %q", i+1, testCase.header, testCase.value, got)
+		}
+		if !testCase.failed {
+			if err != nil {
+				t.Fatalf("Test %d: unexpected error %v", i+1, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrPreconditionFailed) {
+			t.Fatalf("Test %d: expected precondition failure, got %v", i+1, err)
+		}
+		if errResp := ToErrorResponse(err); errResp.StatusCode != http.StatusPreconditionFailed {
+			t.Fatalf("Test %d: expected status %d, got %d", i+1, http.StatusPreconditionFailed, errResp.StatusCode)
+		}
+	}
+}
+
+func TestCopySelectsMultipart(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		requests  []string
+		copyParts int
+		sizes     = map[string]int64{
+			"small": 1 << 30,
+			"large": 6 << 30,
+		}
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		q := r.URL.Query()
+		name := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		switch {
+		case r.Method == http.MethodHead:
+			size, ok := sizes[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Length", fmt.Sprint(size))
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("ETag", `"source-etag"`)
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			requests = append(requests, "new-multipart")
+			io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>`+name+`</Key><UploadId>upload-id</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == http.MethodPut && q.Get("uploadId") == "upload-id":
+			if r.Header.Get("x-amz-copy-source-range") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			copyParts++
+			io.WriteString(w, `<CopyPartResult><ETag>"etag-`+q.Get("partNumber")+`"</ETag></CopyPartResult>`)
+		case r.Method == http.MethodPost && q.Get("uploadId") == "upload-id":
+			requests = append(requests, "complete-multipart")
+			io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>`+name+`</Key><ETag>"multipart-etag"</ETag></CompleteMultipartUploadResult>`)
+		case r.Method == http.MethodPut && r.Header.Get("x-amz-copy-source") != "":
+			if r.Header.Get("x-amz-copy-source-if-match") != "source-etag" {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			requests = append(requests, "copy")
+			io.WriteString(w, `<CopyObjectResult><ETag>"copy-etag"</ETag><LastModified>2024-01-01T00:00:00.000Z</LastModified></CopyObjectResult>`)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		source   string
+		requests []string
+		etag     string
+	}{
+		{"small", []string{"copy"}, "copy-etag"},
+		{"large", []string{"new-multipart", "complete-multipart"}, "multipart-etag"},
+	}
+	for _, testCase := range testCases {
+		requests, copyParts = nil, 0
+		info, err := clnt.Copy(context.Background(),
+			CopyDestOptions{Bucket: "bucket", Object: "dest", ReplaceMetadata: true},
+			CopySrcOptions{Bucket: "bucket", Object: testCase.source})
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.source, err)
+		}
+		if fmt.Sprint(requests) != fmt.Sprint(testCase.requests) {
+			t.Fatalf("%s: expected requests %v, got %v", testCase.source, testCase.requests, requests)
+		}
+		if testCase.source == "large" && copyParts < 2 {
+			t.Fatalf("Expected the large object to be copied in parts, got %d parts", copyParts)
+		}
+		if info.ETag != testCase.etag || info.Size != sizes[testCase.source] {
+			t.Fatalf("%s: unexpected upload info %+v", testCase.source, info)
+		}
+	}
+}
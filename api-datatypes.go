@@ -32,11 +32,33 @@ type BucketInfo struct {
 	Name string `json:"name"`
 	// Date the bucket was created.
 	CreationDate time.Time `json:"creationDate"`
+	// Region of the bucket, only filled by ListBucketsWithOptions
+	// with WithRegion set or when the server includes it.
+	Region string `json:"region,omitempty" xml:"BucketRegion"`
 }
 
-// StringMap represents map with custom UnmarshalXML
+// StringMap represents map with custom UnmarshalXML. As the
+// UserMetadata of an ObjectInfo its keys have the `x-amz-meta-`
+// prefix stripped and are in canonical header form, "x-amz-meta-my-key"
+// and "MY-KEY" are both returned as "My-Key" whatever the casing used
+// when the object was written or reported by the server.
 type StringMap map[string]string
 
+// Get returns the value of key regardless of its casing and of an
+// `x-amz-meta-` prefix, an empty string when the key is not set.
+func (m StringMap) Get(key string) string {
+	key = userMetadataKey(key)
+	if v, ok := m[key]; ok {
+		return v
+	}
+	for k, v := range m {
+		if strings.EqualFold(userMetadataKey(k), key) {
+			return v
+		}
+	}
+	return ""
+}
+
 // UnmarshalXML unmarshals the XML into a map of string to strings,
 // creating a key in the map for each tag and setting it's value to the
 // tags contents.
@@ -60,7 +82,7 @@ func (m *StringMap) UnmarshalXML(d *xml.Decoder, _ xml.StartElement) error {
 		if err != nil {
 			return err
 		}
-		(*m)[e.XMLName.Local] = e.Value
+		(*m)[userMetadataKey(e.XMLName.Local)] = e.Value
 	}
 	return nil
 }
@@ -120,8 +142,8 @@ func stringsCut(s, sep string) (before, after string, found bool) {
 // Owner name.
 type Owner struct {
 	XMLName     xml.Name `xml:"Owner" json:"owner"`
-	DisplayName string   `xml:"ID" json:"name"`
-	ID          string   `xml:"DisplayName" json:"id"`
+	DisplayName string   `xml:"DisplayName" json:"name"`
+	ID          string   `xml:"ID" json:"id"`
 }
 
 // UploadInfo contains information about the
@@ -140,6 +162,10 @@ type UploadInfo struct {
 	Expiration       time.Time
 	ExpirationRuleID string
 
+	// RequestCharged is true when the requester was charged for the
+	// upload, as reported by x-amz-request-charged.
+	RequestCharged bool
+
 	// Verified checksum values, if any.
 	// Values are base64 (standard) encoded.
 	// For multipart objects this is a checksum of the checksum of each part.
@@ -153,9 +179,9 @@ type UploadInfo struct {
 // RestoreInfo contains information of the restore operation of an archived object
 type RestoreInfo struct {
 	// Is the restoring operation is still ongoing
-	OngoingRestore bool
+	OngoingRestore bool `xml:"IsRestoreInProgress"`
 	// When the restored copy of the archived object will be removed
-	ExpiryTime time.Time
+	ExpiryTime time.Time `xml:"RestoreExpiryDate"`
 }
 
 // ObjectInfo container for object metadata.
@@ -171,11 +197,20 @@ type ObjectInfo struct {
 	ContentType  string    `json:"contentType"`  // A standard MIME type describing the format of the object data.
 	Expires      time.Time `json:"expires"`      // The date and time at which the object is no longer able to be cached.
 
+	// ActualSize is the logical size in bytes of the object. It differs
+	// from Size for objects stored encrypted by a client, for example by
+	// the S3 encryption client which records the plaintext size in the
+	// x-amz-unencrypted-content-length metadata, or compressed by the
+	// server. StatObject and GetObject set it to Size when no logical
+	// size is reported, listings leave it 0, which means unknown.
+	ActualSize int64 `json:"actualSize,omitempty"`
+
 	// Collection of additional metadata on the object.
 	// eg: x-amz-meta-*, content-encoding etc.
 	Metadata http.Header `json:"metadata" xml:"-"`
 
-	// x-amz-meta-* headers stripped "x-amz-meta-" prefix containing the first value.
+	// x-amz-meta-* headers stripped "x-amz-meta-" prefix containing the first value,
+	// keys are in canonical header form, use UserMetadata.Get for lookups.
 	// Only returned by MinIO servers.
 	UserMetadata StringMap `json:"userMetadata,omitempty"`
 
@@ -183,9 +218,15 @@ type ObjectInfo struct {
 	// Only returned by MinIO servers.
 	UserTags URLMap `json:"userTags,omitempty" xml:"UserTags"`
 
-	// x-amz-tagging-count value
+	// x-amz-tagging-count value, the number of tags of the object
+	// returned by StatObject and GetObject. It is zero for objects
+	// without tags, there is no need to call GetObjectTagging then.
 	UserTagCount int
 
+	// IsPrefix is true for the common prefixes of a delimited
+	// listing, Key holds the prefix, they have no other fields set.
+	IsPrefix bool `json:"isPrefix,omitempty" xml:"-"`
+
 	// Owner name.
 	Owner Owner
 
@@ -206,14 +247,21 @@ type ObjectInfo struct {
 	// - FAILED
 	// - REPLICA (on the destination)
 	ReplicationStatus string `xml:"ReplicationStatus"`
-	// set to true if delete marker has backing object version on target, and eligible to replicate
+	// set to true if delete marker has backing object version on target, and eligible to replicate,
+	// or if the object is ready to be replicated as reported by x-minio-replication-ready
 	ReplicationReady bool
+	// RequestCharged is true when the requester was charged for the
+	// request, as reported by x-amz-request-charged.
+	RequestCharged bool `json:"requestCharged,omitempty"`
 	// Lifecycle expiry-date and ruleID associated with the expiry
 	// not to be confused with `Expires` HTTP header.
 	Expiration       time.Time
 	ExpirationRuleID string
 
-	Restore *RestoreInfo
+	// x-amz-restore value of an archived object, nil when the object
+	// was never restored. Listings only return it with
+	// ListObjectsOptions.WithRestoreStatus.
+	Restore *RestoreInfo `xml:"RestoreStatus"`
 
 	// Checksum values
 	ChecksumCRC32     string
@@ -222,6 +270,12 @@ type ObjectInfo struct {
 	ChecksumSHA256    string
 	ChecksumCRC64NVME string
 
+	// ServerSideEncryption is the server side encryption algorithm of
+	// the object, "AES256" for SSE-S3 and "aws:kms" for SSE-KMS, with
+	// the id of the KMS key in SSEKMSKeyID.
+	ServerSideEncryption string `json:"serverSideEncryption,omitempty"`
+	SSEKMSKeyID          string `json:"sseKMSKeyID,omitempty"`
+
 	Internal *struct {
 		K int // Data blocks
 		M int // Parity blocks
@@ -236,7 +290,7 @@ type ObjectMultipartInfo struct {
 	// Date and time at which the multipart upload was initiated.
 	Initiated time.Time `type:"timestamp" timestampFormat:"iso8601"`
 
-	Initiator initiator
+	Initiator Initiator
 	Owner     owner
 
 	// The type of storage to use for the object. Defaults to 'STANDARD'.
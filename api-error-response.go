@@ -20,10 +20,13 @@ package minio
 import (
 	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+
+	"github.com/minio/minio-go/v7/pkg/s3utils"
 )
 
 /* **** SAMPLE ERROR RESPONSE ****
@@ -62,8 +65,66 @@ type ErrorResponse struct {
 	StatusCode int `xml:"-" json:"-"`
 }
 
+// Sentinel errors matched by errors.Is against the ErrorResponse
+// returned by the server, based on its Code and not on its message.
+//
+// For example:
+//
+//	_, err := s3.StatObject(...)
+//	if errors.Is(err, minio.ErrObjectNotFound) {
+//	   ...
+//	}
+var (
+	ErrBucketNotFound      = errors.New("bucket not found")
+	ErrObjectNotFound      = errors.New("object not found")
+	ErrUploadNotFound      = errors.New("multipart upload not found")
+	ErrAccessDenied        = errors.New("access denied")
+	ErrBucketAlreadyExists = errors.New("bucket already exists")
+	ErrBucketNotEmpty      = errors.New("bucket not empty")
+	ErrPreconditionFailed  = errors.New("precondition failed")
+	ErrNotImplemented      = errors.New("not implemented")
+	ErrRestoreInProgress   = errors.New("object restore already in progress")
+
+	// ErrNotModified is not a failure, the object was not modified
+	// according to the If-None-Match or If-Modified-Since conditions
+	// of a GetObject or StatObject request, a cached copy is current.
+	ErrNotModified = errors.New("not modified")
+
+	// Client side validation errors match these as well.
+	ErrInvalidBucketName = s3utils.ErrInvalidBucketName
+	ErrInvalidObjectName = s3utils.ErrInvalidObjectName
+)
+
+// Error codes mapped to the sentinel error they match.
+var errorCodeSentinels = map[string]error{
+	"NoSuchBucket":             ErrBucketNotFound,
+	"NoSuchKey":                ErrObjectNotFound,
+	"NoSuchVersion":            ErrObjectNotFound,
+	"NoSuchUpload":             ErrUploadNotFound,
+	"AccessDenied":             ErrAccessDenied,
+	"BucketAlreadyExists":      ErrBucketAlreadyExists,
+	"BucketAlreadyOwnedByYou":  ErrBucketAlreadyExists,
+	"BucketNotEmpty":           ErrBucketNotEmpty,
+	"PreconditionFailed":       ErrPreconditionFailed,
+	"NotModified":              ErrNotModified,
+	"NotImplemented":           ErrNotImplemented,
+	"APINotSupported":          ErrNotImplemented,
+	"RestoreAlreadyInProgress": ErrRestoreInProgress,
+	"InvalidBucketName":        ErrInvalidBucketName,
+	"XMinioInvalidObjectName":  ErrInvalidObjectName,
+}
+
+// Is - Reports whether the error matches target, a sentinel error
+// such as ErrObjectNotFound, used by errors.Is.
+func (e ErrorResponse) Is(target error) bool {
+	sentinel, ok := errorCodeSentinels[e.Code]
+	return ok && sentinel == target
+}
+
 // ToErrorResponse - Returns parsed ErrorResponse struct from body and
-// http headers.
+// http headers. Wrapped errors are unwrapped and StatusCode is always
+// set, errors which were not returned with their own HTTP response get
+// the status code S3 uses for their Code.
 //
 // For example:
 //
@@ -76,12 +137,18 @@ type ErrorResponse struct {
 //	}
 //	...
 func ToErrorResponse(err error) ErrorResponse {
-	switch err := err.(type) {
-	case ErrorResponse:
-		return err
-	default:
+	var errResp ErrorResponse
+	if !errors.As(err, &errResp) {
 		return ErrorResponse{}
 	}
+	if errResp.StatusCode == 0 {
+		if statusCode, ok := s3ErrorResponseStatusMap[errResp.Code]; ok {
+			errResp.StatusCode = statusCode
+		} else if errResp.Code != "" {
+			errResp.StatusCode = http.StatusInternalServerError
+		}
+	}
+	return errResp
 }
 
 // Error - Returns S3 error string.
@@ -164,6 +231,14 @@ func httpRespToErrorResponse(resp *http.Response, bucketName, objectName string)
 				Message:    "Bucket not empty.",
 				BucketName: bucketName,
 			}
+		case http.StatusNotModified:
+			errResp = ErrorResponse{
+				StatusCode: resp.StatusCode,
+				Code:       "NotModified",
+				Message:    s3ErrorResponseMap["NotModified"],
+				BucketName: bucketName,
+				Key:        objectName,
+			}
 		case http.StatusPreconditionFailed:
 			errResp = ErrorResponse{
 				StatusCode: resp.StatusCode,
@@ -262,6 +337,45 @@ func errUnexpectedEOF(totalRead, totalSize int64, bucketName, objectName string)
 	}
 }
 
+// errTruncatedRead - Server closed the response body before sending all
+// the bytes advertised in Content-Length. The returned error wraps
+// io.ErrUnexpectedEOF.
+func errTruncatedRead(totalRead, totalSize int64, bucketName, objectName string) error {
+	return fmt.Errorf("Object '%s/%s' response ended after ‘%d’ bytes, expected ‘%d’: %w", bucketName, objectName, totalRead, totalSize, io.ErrUnexpectedEOF)
+}
+
+// toObjectLockError - Converts the error returned by object lock
+// operations on a bucket created without object lock into a typed
+// ObjectLockConfigurationNotFoundError, other errors are returned as is.
+func toObjectLockError(err error) error {
+	errResp, ok := err.(ErrorResponse)
+	if !ok {
+		return err
+	}
+	// AWS S3 and MinIO spell the configuration name differently.
+	msg := strings.ToLower(strings.ReplaceAll(errResp.Message, " ", ""))
+	switch {
+	case errResp.Code == "InvalidRequest" && strings.Contains(msg, "missingobjectlockconfiguration"):
+	case errResp.Code == "InvalidBucketState" && strings.Contains(msg, "objectlockconfigurationcannotbeenabled"):
+	default:
+		return err
+	}
+	errResp.Code = "ObjectLockConfigurationNotFoundError"
+	return errResp
+}
+
+// errInsecureSSEC - SSE-C keys must not be sent over plain HTTP.
+func errInsecureSSEC(bucketName, objectName string) error {
+	return ErrorResponse{
+		StatusCode: http.StatusBadRequest,
+		Code:       "InsecureSSECustomerRequest",
+		Message:    "Requests specifying Server Side Encryption with Customer provided keys must be made over a secure connection.",
+		BucketName: bucketName,
+		Key:        objectName,
+		RequestID:  "minio",
+	}
+}
+
 // errInvalidArgument - Invalid argument response.
 func errInvalidArgument(message string) error {
 	return ErrorResponse{
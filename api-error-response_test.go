@@ -19,13 +19,19 @@ package minio
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
 )
 
 // Tests validate the Error generator function for http response with error.
@@ -286,3 +292,125 @@ func TestErrorResponseComparable(t *testing.T) {
 		t.Fatalf("ErrorResponse should be comparable")
 	}
 }
+
+func TestErrorResponseStatusCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statusCode, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/bucket/"))
+		w.Header().Set("x-amz-request-id", "REQUESTID")
+		w.WriteHeader(statusCode)
+		if r.Method != http.MethodHead {
+			w.Write([]byte(`<Error><Code>SlowDown</Code><Message>Please reduce your request rate.</Message></Error>`))
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Creds:      credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region:     "us-east-1",
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		statusCode int
+		code       string
+	}{
+		{http.StatusNotFound, "NoSuchKey"},
+		{http.StatusForbidden, "AccessDenied"},
+		{http.StatusConflict, "Conflict"},
+		{http.StatusServiceUnavailable, "503 Service Unavailable"},
+	}
+	for _, testCase := range testCases {
+		objectName := strconv.Itoa(testCase.statusCode)
+		// HEAD responses have no body.
+		_, err = clnt.StatObject(context.Background(), "bucket", objectName, StatObjectOptions{})
+		errResp := ToErrorResponse(err)
+		if errResp.StatusCode != testCase.statusCode || errResp.Code != testCase.code {
+			t.Fatalf("HEAD %d: expected status %d and code %s, got %d and %s", testCase.statusCode, testCase.statusCode, testCase.code, errResp.StatusCode, errResp.Code)
+		}
+		if errResp.RequestID != "REQUESTID" {
+			t.Fatalf("HEAD %d: expected request id REQUESTID, got %s", testCase.statusCode, errResp.RequestID)
+		}
+
+		_, err = clnt.GetObjectAttributes(context.Background(), "bucket", objectName, ObjectAttributesOptions{})
+		errResp = ToErrorResponse(err)
+		if errResp.StatusCode != testCase.statusCode || errResp.Code != "SlowDown" {
+			t.Fatalf("GET %d: expected status %d and code SlowDown, got %d and %s", testCase.statusCode, testCase.statusCode, errResp.StatusCode, errResp.Code)
+		}
+	}
+
+	// Errors without their own HTTP response get the status of their code.
+	errResp := ToErrorResponse(fmt.Errorf("remove failed: %w", ErrorResponse{Code: "AccessDenied"}))
+	if errResp.StatusCode != http.StatusForbidden {
+		t.Fatalf("Expected status %d, got %d", http.StatusForbidden, errResp.StatusCode)
+	}
+	if errResp = ToErrorResponse(ErrorResponse{Code: "UnknownCode"}); errResp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("Expected status %d, got %d", http.StatusInternalServerError, errResp.StatusCode)
+	}
+	if errResp = ToErrorResponse(io.EOF); errResp.StatusCode != 0 {
+		t.Fatalf("Expected no status for non S3 error, got %d", errResp.StatusCode)
+	}
+}
+
+func TestErrorResponseIs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing-bucket/":
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method != http.MethodHead {
+				w.Write([]byte(`<Error><Code>NoSuchBucket</Code><Message>Bucket is gone</Message></Error>`))
+			}
+		case "/bucket/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/bucket/denied":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Nope</Message></Error>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<Error><Code>NoSuchKey</Code><Message>Some other wording</Message></Error>`))
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Creds:      credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region:     "us-east-1",
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	_, err = clnt.GetObjectACL(ctx, "bucket", "object")
+	if !errors.Is(err, ErrObjectNotFound) || errors.Is(err, ErrBucketNotFound) {
+		t.Fatalf("Expected ErrObjectNotFound, got %v", err)
+	}
+	// HEAD responses without body.
+	if _, err = clnt.StatObject(ctx, "bucket", "missing", StatObjectOptions{}); !errors.Is(err, ErrObjectNotFound) {
+		t.Fatalf("Expected ErrObjectNotFound, got %v", err)
+	}
+	if _, err = clnt.GetBucketPolicy(ctx, "missing-bucket"); !errors.Is(err, ErrBucketNotFound) {
+		t.Fatalf("Expected ErrBucketNotFound, got %v", err)
+	}
+	if _, err = clnt.GetObjectACL(ctx, "bucket", "denied"); !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("Expected ErrAccessDenied, got %v", err)
+	}
+	// Wrapped errors match as well.
+	if err = fmt.Errorf("stat failed: %w", err); !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("Expected wrapped ErrAccessDenied, got %v", err)
+	}
+
+	// Client side validation errors.
+	if _, err = clnt.StatObject(ctx, "b", "object", StatObjectOptions{}); !errors.Is(err, ErrInvalidBucketName) {
+		t.Fatalf("Expected ErrInvalidBucketName, got %v", err)
+	}
+	if _, err = clnt.GetObjectACL(ctx, "b", "object"); !errors.Is(err, ErrInvalidBucketName) {
+		t.Fatalf("Expected ErrInvalidBucketName, got %v", err)
+	}
+	if _, err = clnt.StatObject(ctx, "bucket", "", StatObjectOptions{}); !errors.Is(err, ErrInvalidObjectName) {
+		t.Fatalf("Expected ErrInvalidObjectName, got %v", err)
+	}
+}
@@ -22,6 +22,8 @@ import (
 	"encoding/xml"
 	"net/http"
 	"net/url"
+
+	"github.com/minio/minio-go/v7/pkg/s3utils"
 )
 
 // Grantee represents the person being granted permissions.
@@ -46,35 +48,61 @@ type AccessControlList struct {
 	Permission string `xml:"Permission"`
 }
 
-type accessControlPolicy struct {
+// AccessControlPolicy is the owner and the grants of an object ACL.
+type AccessControlPolicy struct {
 	XMLName           xml.Name `xml:"AccessControlPolicy"`
 	Owner             Owner
 	AccessControlList AccessControlList
 }
 
-// GetObjectACL get object ACLs
-func (c *Client) GetObjectACL(ctx context.Context, bucketName, objectName string) (*ObjectInfo, error) {
+// CannedACL returns the canned ACL matching the grants of the policy,
+// such as "private" or "public-read", or "" for other grants.
+func (p *AccessControlPolicy) CannedACL() string {
+	return getCannedACL(p)
+}
+
+// GetObjectACLPolicy - Returns the owner and the grants of the ACL of
+// an object. Backends without object ACLs return an error matching
+// ErrNotImplemented.
+func (c *Client) GetObjectACLPolicy(ctx context.Context, bucketName, objectName string) (*AccessControlPolicy, error) {
+	// Input validation.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		return nil, err
+	}
+	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+		return nil, err
+	}
+
 	resp, err := c.executeMethod(ctx, http.MethodGet, requestMetadata{
 		bucketName: bucketName,
 		objectName: objectName,
 		queryValues: url.Values{
 			"acl": []string{""},
 		},
+		contentSHA256Hex: emptySHA256Hex,
 	})
+	defer closeResponse(resp)
 	if err != nil {
 		return nil, err
 	}
-	defer closeResponse(resp)
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, httpRespToErrorResponse(resp, bucketName, objectName)
 	}
 
-	res := &accessControlPolicy{}
-
+	res := &AccessControlPolicy{}
 	if err := xmlDecoder(resp.Body, res); err != nil {
 		return nil, err
 	}
+	return res, nil
+}
+
+// GetObjectACL get object ACLs
+func (c *Client) GetObjectACL(ctx context.Context, bucketName, objectName string) (*ObjectInfo, error) {
+	res, err := c.GetObjectACLPolicy(ctx, bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
 
 	objInfo, err := c.StatObject(ctx, bucketName, objectName, StatObjectOptions{})
 	if err != nil {
@@ -100,7 +128,7 @@ func (c *Client) GetObjectACL(ctx context.Context, bucketName, objectName string
 	return &objInfo, nil
 }
 
-func getCannedACL(aCPolicy *accessControlPolicy) string {
+func getCannedACL(aCPolicy *AccessControlPolicy) string {
 	grants := aCPolicy.AccessControlList.Grant
 
 	switch {
@@ -130,7 +158,7 @@ func getCannedACL(aCPolicy *accessControlPolicy) string {
 	return ""
 }
 
-func getAmzGrantACL(aCPolicy *accessControlPolicy) map[string][]string {
+func getAmzGrantACL(aCPolicy *AccessControlPolicy) map[string][]string {
 	grants := aCPolicy.AccessControlList.Grant
 	res := map[string][]string{}
 
@@ -19,10 +19,10 @@ package minio
 import (
 	"context"
 	"encoding/xml"
-	"errors"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/minio/minio-go/v7/pkg/encrypt"
@@ -42,11 +42,25 @@ import (
 //
 // - ServerSideEncryption
 // The server-side encryption algorithm used when storing this object in Minio
+//
+// - Attributes
+// The attributes to return, any of ETag, Checksum, StorageClass, ObjectSize
+// and ObjectParts (default: all of them)
 type ObjectAttributesOptions struct {
 	MaxParts             int
 	VersionID            string
 	PartNumberMarker     int
 	ServerSideEncryption encrypt.ServerSide
+	Attributes           []string
+}
+
+// objectAttributeNames are the attributes GetObjectAttributes can return.
+var objectAttributeNames = map[string]struct{}{
+	"ETag":         {},
+	"Checksum":     {},
+	"StorageClass": {},
+	"ObjectSize":   {},
+	"ObjectParts":  {},
 }
 
 // ObjectAttributes is the response object returned by the GetObjectAttributes API
@@ -147,8 +161,18 @@ func (c *Client) GetObjectAttributes(ctx context.Context, bucketName, objectName
 		urlValues.Add("versionId", opts.VersionID)
 	}
 
+	attributes := GetObjectAttributesTags
+	if len(opts.Attributes) > 0 {
+		for _, attribute := range opts.Attributes {
+			if _, ok := objectAttributeNames[attribute]; !ok {
+				return nil, errInvalidArgument("Unsupported object attribute " + attribute)
+			}
+		}
+		attributes = strings.Join(opts.Attributes, ",")
+	}
+
 	headers := make(http.Header)
-	headers.Set(amzObjectAttributes, GetObjectAttributesTags)
+	headers.Set(amzObjectAttributes, attributes)
 
 	if opts.PartNumberMarker > 0 {
 		headers.Set(amzPartNumberMarker, strconv.Itoa(opts.PartNumberMarker))
@@ -177,18 +201,21 @@ func (c *Client) GetObjectAttributes(ctx context.Context, bucketName, objectName
 
 	defer closeResponse(resp)
 
-	hasEtag := resp.Header.Get(ETag)
-	if hasEtag != "" {
-		return nil, errors.New("getObjectAttributes is not supported by the current endpoint version")
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp, bucketName, objectName)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		ER := new(ErrorResponse)
-		if err := xml.NewDecoder(resp.Body).Decode(ER); err != nil {
-			return nil, err
+	// Servers which do not know the API ignore the attributes query
+	// and return the object itself, along with its ETag header.
+	if resp.Header.Get(ETag) != "" {
+		return nil, ErrorResponse{
+			StatusCode: http.StatusNotImplemented,
+			Code:       "NotImplemented",
+			Message:    "GetObjectAttributes is not supported by the current endpoint version",
+			BucketName: bucketName,
+			Key:        objectName,
+			RequestID:  resp.Header.Get("x-amz-request-id"),
 		}
-
-		return nil, *ER
 	}
 
 	OA := new(ObjectAttributes)
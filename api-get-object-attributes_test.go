@@ -0,0 +1,119 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Response body recorded from Amazon S3 for a two part object.
+const objectAttributesResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<GetObjectAttributesResponse xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><ETag>e3f1b4e2c5a0d7f9c5b1a2d3e4f5a6b7-2</ETag><Checksum><ChecksumCRC32C>7EJfxQ==-2</ChecksumCRC32C></Checksum><ObjectParts><PartNumberMarker>0</PartNumberMarker><NextPartNumberMarker>1</NextPartNumberMarker><MaxParts>1</MaxParts><IsTruncated>true</IsTruncated><PartsCount>2</PartsCount><Part><ChecksumCRC32C>ahTuxw==</ChecksumCRC32C><PartNumber>1</PartNumber><Size>5242880</Size></Part></ObjectParts><StorageClass>STANDARD</StorageClass><ObjectSize>6291456</ObjectSize></GetObjectAttributesResponse>`
+
+func TestGetObjectAttributes(t *testing.T) {
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["attributes"]; !ok || r.URL.Query().Get("versionId") != "v1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		header = r.Header
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.Header().Set(amzVersionID, "v1")
+		io.WriteString(w, objectAttributesResponse)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attrs, err := clnt.GetObjectAttributes(context.Background(), "bucket", "object", ObjectAttributesOptions{
+		VersionID: "v1",
+		MaxParts:  1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Get(amzObjectAttributes) != GetObjectAttributesTags || header.Get(amzMaxParts) != "1" {
+		t.Fatalf("Unexpected request headers %v", header)
+	}
+	if attrs.VersionID != "v1" || attrs.LastModified.IsZero() {
+		t.Fatalf("Unexpected version %q and modification time %v", attrs.VersionID, attrs.LastModified)
+	}
+	if attrs.ETag != "e3f1b4e2c5a0d7f9c5b1a2d3e4f5a6b7-2" || attrs.StorageClass != "STANDARD" || attrs.ObjectSize != 6291456 {
+		t.Fatalf("Unexpected attributes %+v", attrs.ObjectAttributesResponse)
+	}
+	if attrs.Checksum.ChecksumCRC32C != "7EJfxQ==-2" {
+		t.Fatalf("Unexpected checksum %+v", attrs.Checksum)
+	}
+	parts := attrs.ObjectParts
+	if parts.PartsCount != 2 || !parts.IsTruncated || parts.NextPartNumberMarker != 1 || parts.MaxParts != 1 {
+		t.Fatalf("Unexpected parts pagination %+v", parts)
+	}
+	if len(parts.Parts) != 1 || parts.Parts[0].PartNumber != 1 || parts.Parts[0].Size != 5242880 || parts.Parts[0].ChecksumCRC32C != "ahTuxw==" {
+		t.Fatalf("Unexpected parts %+v", parts.Parts)
+	}
+
+	if _, err = clnt.GetObjectAttributes(context.Background(), "bucket", "object", ObjectAttributesOptions{
+		VersionID:  "v1",
+		Attributes: []string{"ETag", "ObjectSize"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if header.Get(amzObjectAttributes) != "ETag,ObjectSize" {
+		t.Fatalf("Expected only ETag and ObjectSize to be requested, got %s", header.Get(amzObjectAttributes))
+	}
+	if _, err = clnt.GetObjectAttributes(context.Background(), "bucket", "object", ObjectAttributesOptions{
+		Attributes: []string{"Size"},
+	}); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Fatalf("Expected InvalidArgument for an unknown attribute, got %v", err)
+	}
+}
+
+func TestGetObjectAttributesNotImplemented(t *testing.T) {
+	// The server ignores the attributes query and returns the object.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.Header().Set("ETag", `"abc"`)
+		io.WriteString(w, "object data")
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = clnt.GetObjectAttributes(context.Background(), "bucket", "object", ObjectAttributesOptions{})
+	if !errors.Is(err, ErrNotImplemented) {
+		t.Fatalf("Expected ErrNotImplemented, got %v", err)
+	}
+	if errResp := ToErrorResponse(err); errResp.StatusCode != http.StatusNotImplemented || errResp.Key != "object" {
+		t.Fatalf("Unexpected error response %+v", errResp)
+	}
+}
@@ -28,6 +28,12 @@ import (
 
 // FGetObject - download contents of an object to a local file.
 // The options can be used to specify the GET request further.
+//
+// The object is downloaded to a temporary file next to filePath which
+// is renamed to filePath once complete, readers never observe a partial
+// file. Missing parent directories are created, the modification time
+// of the file is set to the object's Last-Modified time when
+// opts.PreserveModTime is set.
 func (c *Client) FGetObject(ctx context.Context, bucketName, objectName, filePath string, opts GetObjectOptions) error {
 	// Input validation.
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
@@ -79,7 +85,9 @@ func (c *Client) FGetObject(ctx context.Context, bucketName, objectName, filePat
 
 	// If we return early with an error, be sure to close and delete
 	// filePart.  If we have an error along the way there is a chance
-	// that filePart is somehow damaged, and we should discard it.
+	// that filePart is somehow damaged, and we should discard it. The
+	// destination is only replaced by the final rename, an interrupted
+	// download leaves it untouched.
 	closeAndRemove := true
 	defer func() {
 		if closeAndRemove {
@@ -105,22 +113,30 @@ func (c *Client) FGetObject(ctx context.Context, bucketName, objectName, filePat
 	if err != nil {
 		return err
 	}
+	defer objectReader.Close()
 
-	// Write to the part file.
-	if _, err = io.CopyN(filePart, objectReader, objectStat.Size); err != nil {
+	// Write to the part file, objectStat.Size is the full object size
+	// even when resuming from an offset.
+	if _, err = io.CopyN(filePart, objectReader, objectStat.Size-st.Size()); err != nil {
 		return err
 	}
 
 	// Close the file before rename, this is specifically needed for Windows users.
-	closeAndRemove = false
 	if err = filePart.Close(); err != nil {
 		return err
 	}
 
+	if opts.PreserveModTime && !objectStat.LastModified.IsZero() {
+		if err = os.Chtimes(filePartPath, objectStat.LastModified, objectStat.LastModified); err != nil {
+			return err
+		}
+	}
+
 	// Safely completed. Now commit by renaming to actual filename.
 	if err = os.Rename(filePartPath, filePath); err != nil {
 		return err
 	}
+	closeAndRemove = false
 
 	// Return.
 	return nil
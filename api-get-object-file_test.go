@@ -0,0 +1,108 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFGetObjectInterrupted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Content-Length", "100")
+
+		// Write less bytes than the content length.
+		w.Write([]byte("12345"))
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:     "us-east-1",
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "object")
+	if err = os.WriteFile(filePath, []byte("previous"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err = clnt.FGetObject(context.Background(), "bucket", "object", filePath, GetObjectOptions{}); err == nil {
+		t.Fatal("Expected an error for a truncated download")
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "previous" {
+		t.Fatalf("Expected destination to be untouched, got %q", data)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected temporary file to be removed, found %d entries", len(entries))
+	}
+}
+
+func TestFGetObjectPreserveModTime(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Content-Length", "5")
+		w.Write([]byte("12345"))
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filePath := filepath.Join(t.TempDir(), "dir", "object")
+	if err = clnt.FGetObject(context.Background(), "bucket", "object", filePath, GetObjectOptions{PreserveModTime: true}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "12345" {
+		t.Fatalf("Expected object content, got %q", data)
+	}
+	st, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2015, time.October, 21, 7, 28, 0, 0, time.UTC); !st.ModTime().Equal(expected) {
+		t.Fatalf("Expected modification time %v, got %v", expected, st.ModTime())
+	}
+}
@@ -18,13 +18,18 @@
 package minio
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/minio/minio-go/v7/pkg/s3utils"
 )
 
@@ -59,6 +64,9 @@ func (c *Client) GetObject(ctx context.Context, bucketName, objectName string, o
 		httpReader io.ReadCloser
 		objectInfo ObjectInfo
 		totalRead  int
+		// Number of bytes expected in the current response body,
+		// objectInfo.Size is the full size for ranged requests.
+		respLength int64
 	)
 
 	// Create request channel.
@@ -81,7 +89,35 @@ func (c *Client) GetObject(ctx context.Context, bucketName, objectName string, o
 		// Used to verify if etag of object has changed since last read.
 		var etag string
 
-		for req := range reqCh {
+		// Following requests read the version of the first response.
+		pinVersion := func(objectInfo ObjectInfo) {
+			if opts.PinVersion && opts.VersionID == "" {
+				opts.VersionID = objectInfo.VersionID
+			}
+		}
+
+		// Replies to the caller, returns false if the context was
+		// cancelled and the caller stopped waiting for a reply.
+		sendResponse := func(res getResponse) bool {
+			select {
+			case resCh <- res:
+				return true
+			case <-gctx.Done():
+				return false
+			}
+		}
+
+		for {
+			var req getRequest
+			select {
+			case <-gctx.Done():
+				return
+			case r, ok := <-reqCh:
+				if !ok {
+					return
+				}
+				req = r
+			}
 			// If this is the first request we may not need to do a getObject request yet.
 			if req.isFirstReq {
 				// First request is a Read/ReadAt.
@@ -96,41 +132,46 @@ func (c *Client) GetObject(ctx context.Context, bucketName, objectName string, o
 					} else if req.Offset > 0 {
 						opts.SetRange(req.Offset, 0)
 					}
-					httpReader, objectInfo, _, err = c.getObject(gctx, bucketName, objectName, opts)
+					var header http.Header
+					httpReader, objectInfo, header, err = c.getObject(gctx, bucketName, objectName, opts)
 					if err != nil {
-						resCh <- getResponse{Error: err}
+						sendResponse(getResponse{Error: err})
 						return
 					}
+					respLength = responseLength(header)
 					etag = objectInfo.ETag
+					pinVersion(objectInfo)
 					// Read at least firstReq.Buffer bytes, if not we have
 					// reached our EOF.
 					size, err := readFull(httpReader, req.Buffer)
 					totalRead += size
 					if size > 0 && err == io.ErrUnexpectedEOF {
-						if int64(size) < objectInfo.Size {
+						if int64(size) < respLength {
 							// In situations when returned size
 							// is less than the expected content
 							// length set by the server, make sure
 							// we return io.ErrUnexpectedEOF
-							err = io.ErrUnexpectedEOF
+							err = errTruncatedRead(int64(totalRead), respLength, bucketName, objectName)
 						} else {
 							// If an EOF happens after reading some but not
 							// all the bytes ReadFull returns ErrUnexpectedEOF
 							err = io.EOF
 						}
-					} else if size == 0 && err == io.EOF && objectInfo.Size > 0 {
+					} else if size == 0 && err == io.EOF && int64(totalRead) < respLength {
 						// Special cases when server writes more data
 						// than the content-length, net/http response
 						// body returns an error, instead of converting
 						// it to io.EOF - return unexpected EOF.
-						err = io.ErrUnexpectedEOF
+						err = errTruncatedRead(int64(totalRead), respLength, bucketName, objectName)
 					}
 					// Send back the first response.
-					resCh <- getResponse{
+					if !sendResponse(getResponse{
 						objectInfo: objectInfo,
 						Size:       size,
 						Error:      err,
 						didRead:    true,
+					}) {
+						return
 					}
 				} else {
 					// First request is a Stat or Seek call.
@@ -140,16 +181,19 @@ func (c *Client) GetObject(ctx context.Context, bucketName, objectName string, o
 					delete(opts.headers, "Range")
 					objectInfo, err = c.StatObject(gctx, bucketName, objectName, StatObjectOptions(opts))
 					if err != nil {
-						resCh <- getResponse{
+						sendResponse(getResponse{
 							Error: err,
-						}
+						})
 						// Exit the go-routine.
 						return
 					}
 					etag = objectInfo.ETag
+					pinVersion(objectInfo)
 					// Send back the first response.
-					resCh <- getResponse{
+					if !sendResponse(getResponse{
 						objectInfo: objectInfo,
+					}) {
+						return
 					}
 				}
 			} else if req.settingObjectInfo { // Request is just to get objectInfo.
@@ -163,15 +207,17 @@ func (c *Client) GetObject(ctx context.Context, bucketName, objectName string, o
 				}
 				objectInfo, err := c.StatObject(gctx, bucketName, objectName, StatObjectOptions(opts))
 				if err != nil {
-					resCh <- getResponse{
+					sendResponse(getResponse{
 						Error: err,
-					}
+					})
 					// Exit the goroutine.
 					return
 				}
 				// Send back the objectInfo.
-				resCh <- getResponse{
+				if !sendResponse(getResponse{
 					objectInfo: objectInfo,
+				}) {
+					return
 				}
 			} else {
 				// Offset changes fetch the new object at an Offset.
@@ -201,13 +247,15 @@ func (c *Client) GetObject(ctx context.Context, bucketName, objectName string, o
 						// Remove range header if already set
 						delete(opts.headers, "Range")
 					}
-					httpReader, objectInfo, _, err = c.getObject(gctx, bucketName, objectName, opts)
+					var header http.Header
+					httpReader, objectInfo, header, err = c.getObject(gctx, bucketName, objectName, opts)
 					if err != nil {
-						resCh <- getResponse{
+						sendResponse(getResponse{
 							Error: err,
-						}
+						})
 						return
 					}
+					respLength = responseLength(header)
 					totalRead = 0
 				}
 
@@ -216,38 +264,42 @@ func (c *Client) GetObject(ctx context.Context, bucketName, objectName string, o
 				size, err := readFull(httpReader, req.Buffer)
 				totalRead += size
 				if size > 0 && err == io.ErrUnexpectedEOF {
-					if int64(totalRead) < objectInfo.Size {
+					if int64(totalRead) < respLength {
 						// In situations when returned size
 						// is less than the expected content
 						// length set by the server, make sure
 						// we return io.ErrUnexpectedEOF
-						err = io.ErrUnexpectedEOF
+						err = errTruncatedRead(int64(totalRead), respLength, bucketName, objectName)
 					} else {
 						// If an EOF happens after reading some but not
 						// all the bytes ReadFull returns ErrUnexpectedEOF
 						err = io.EOF
 					}
-				} else if size == 0 && err == io.EOF && objectInfo.Size > 0 {
+				} else if size == 0 && err == io.EOF && int64(totalRead) < respLength {
 					// Special cases when server writes more data
 					// than the content-length, net/http response
 					// body returns an error, instead of converting
 					// it to io.EOF - return unexpected EOF.
-					err = io.ErrUnexpectedEOF
+					err = errTruncatedRead(int64(totalRead), respLength, bucketName, objectName)
 				}
 
 				// Reply back how much was read.
-				resCh <- getResponse{
+				if !sendResponse(getResponse{
 					Size:       size,
 					Error:      err,
 					didRead:    true,
 					objectInfo: objectInfo,
+				}) {
+					return
 				}
 			}
 		}
 	}()
 
 	// Create a newObject through the information sent back by reqCh.
-	return newObject(gctx, cancel, reqCh, resCh), nil
+	obj := newObject(ctx, gctx, cancel, reqCh, resCh)
+	obj.autoDecompress = opts.AutoDecompress
+	return obj, nil
 }
 
 // get request message container to communicate with internal
@@ -283,6 +335,11 @@ type Object struct {
 	ctx        context.Context
 	cancel     context.CancelFunc
 	currOffset int64
+
+	// Context of the caller, unlike ctx it is not cancelled when the
+	// routine fetching the object stops.
+	callerCtx context.Context
+
 	objectInfo ObjectInfo
 
 	// Ask lower level to initiate data fetching based on currOffset
@@ -302,6 +359,13 @@ type Object struct {
 
 	// Keeps track of if objectInfo has been set yet.
 	objectInfoSet bool
+
+	// Decompress the content according to its Content-Encoding, the
+	// decoder is created by the first Read.
+	autoDecompress bool
+	decodeMu       sync.Mutex
+	decoder        io.Reader
+	closeDecoder   func()
 }
 
 // doGetRequest - sends and blocks on the firstReqCh and reqCh of an object.
@@ -311,14 +375,23 @@ type Object struct {
 func (o *Object) doGetRequest(request getRequest) (getResponse, error) {
 	select {
 	case <-o.ctx.Done():
-		return getResponse{}, o.ctx.Err()
+		return getResponse{}, o.stoppedErr()
 	case o.reqCh <- request:
 	}
 
-	response := <-o.resCh
+	// Cancelling the context aborts the request in flight, wait for
+	// the routine to stop using the request buffer before returning.
+	response, ok := <-o.resCh
+	if !ok && o.ctx.Err() != nil {
+		return getResponse{}, o.stoppedErr()
+	}
 
 	// Return any error to the top level.
 	if response.Error != nil && response.Error != io.EOF {
+		// Report a cancelled context instead of the aborted request.
+		if err := o.callerCtx.Err(); err != nil {
+			return response, err
+		}
 		return response, response.Error
 	}
 
@@ -343,6 +416,20 @@ func (o *Object) doGetRequest(request getRequest) (getResponse, error) {
 	return response, response.Error
 }
 
+// stoppedErr - returns the error for a request the routine fetching
+// the object can no longer serve. Only a cancellation of the caller's
+// context is reported as such, the routine also stops on its own after
+// sending back an error.
+func (o *Object) stoppedErr() error {
+	if err := o.callerCtx.Err(); err != nil {
+		return err
+	}
+	if o.prevErr != nil {
+		return o.prevErr
+	}
+	return o.ctx.Err()
+}
+
 // setOffset - handles the setting of offsets for
 // Read/ReadAt/Seek requests.
 func (o *Object) setOffset(bytesRead int64) error {
@@ -359,6 +446,14 @@ func (o *Object) setOffset(bytesRead int64) error {
 // bytes read (0 <= n <= len(b)) and any error encountered. Returns
 // io.EOF upon end of file.
 func (o *Object) Read(b []byte) (n int, err error) {
+	if o != nil && o.autoDecompress {
+		return o.readDecompressed(b)
+	}
+	return o.readRaw(b)
+}
+
+// readRaw reads the content of the object as stored.
+func (o *Object) readRaw(b []byte) (n int, err error) {
 	if o == nil {
 		return 0, errInvalidArgument("Object is nil")
 	}
@@ -448,6 +543,9 @@ func (o *Object) ReadAt(b []byte, offset int64) (n int, err error) {
 	if o == nil {
 		return 0, errInvalidArgument("Object is nil")
 	}
+	if o.autoDecompress {
+		return 0, errInvalidArgument("ReadAt is not supported with AutoDecompress, the object can only be read in sequence.")
+	}
 
 	// Locking.
 	o.mutex.Lock()
@@ -525,6 +623,9 @@ func (o *Object) Seek(offset int64, whence int) (n int64, err error) {
 	if o == nil {
 		return 0, errInvalidArgument("Object is nil")
 	}
+	if o.autoDecompress {
+		return 0, errInvalidArgument("Seek is not supported with AutoDecompress, the object can only be read in sequence.")
+	}
 
 	// Locking.
 	o.mutex.Lock()
@@ -612,6 +713,9 @@ func (o *Object) Close() (err error) {
 		return errInvalidArgument("Object is nil")
 	}
 
+	// Release the decoder once a pending Read returned.
+	defer o.releaseDecoder()
+
 	// Locking.
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
@@ -637,14 +741,25 @@ func (o *Object) Close() (err error) {
 
 // newObject instantiates a new *minio.Object*
 // ObjectInfo will be set by setObjectInfo
-func newObject(ctx context.Context, cancel context.CancelFunc, reqCh chan<- getRequest, resCh <-chan getResponse) *Object {
+func newObject(callerCtx, ctx context.Context, cancel context.CancelFunc, reqCh chan<- getRequest, resCh <-chan getResponse) *Object {
 	return &Object{
-		ctx:    ctx,
-		cancel: cancel,
-		mutex:  &sync.Mutex{},
-		reqCh:  reqCh,
-		resCh:  resCh,
+		ctx:       ctx,
+		cancel:    cancel,
+		callerCtx: callerCtx,
+		mutex:     &sync.Mutex{},
+		reqCh:     reqCh,
+		resCh:     resCh,
+	}
+}
+
+// responseLength returns the Content-Length of a GET response, -1
+// when it is not known.
+func responseLength(h http.Header) int64 {
+	length, err := strconv.ParseInt(h.Get("Content-Length"), 10, 64)
+	if err != nil {
+		return -1
 	}
+	return length
 }
 
 // getObject - retrieve object from Object Storage.
@@ -697,3 +812,71 @@ func (c *Client) getObject(ctx context.Context, bucketName, objectName string, o
 	// do not close body here, caller will close
 	return resp.Body, objectStat, resp.Header, nil
 }
+
+// readDecompressed reads the content of the object decoded according to
+// its Content-Encoding.
+func (o *Object) readDecompressed(b []byte) (int, error) {
+	o.decodeMu.Lock()
+	defer o.decodeMu.Unlock()
+	if o.decoder == nil {
+		if err := o.newDecoder(); err != nil {
+			return 0, err
+		}
+	}
+	return o.decoder.Read(b)
+}
+
+// newDecoder reads the start of the object to learn its Content-Encoding
+// and sets the decoder reading the object.
+func (o *Object) newDecoder() error {
+	head := make([]byte, 32*1024)
+	n, err := o.readRaw(head)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	var raw io.Reader = bytes.NewReader(head[:n])
+	if err == nil {
+		raw = io.MultiReader(raw, readerFunc(o.readRaw))
+	}
+
+	o.mutex.Lock()
+	encoding := strings.ToLower(strings.TrimSpace(o.objectInfo.Metadata.Get("Content-Encoding")))
+	o.mutex.Unlock()
+
+	switch {
+	case encoding == "" || encoding == "identity" || n == 0:
+		o.decoder = raw
+	case encoding == "gzip" || encoding == "x-gzip":
+		zr, err := gzip.NewReader(raw)
+		if err != nil {
+			return err
+		}
+		o.decoder = zr
+	case encoding == "zstd":
+		zr, err := zstd.NewReader(raw, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return err
+		}
+		o.decoder, o.closeDecoder = zr, zr.Close
+	default:
+		return errInvalidArgument("Content-Encoding " + encoding + " is not supported by AutoDecompress.")
+	}
+	return nil
+}
+
+// releaseDecoder releases the resources of the decoder.
+func (o *Object) releaseDecoder() {
+	o.decodeMu.Lock()
+	defer o.decodeMu.Unlock()
+	if o.closeDecoder != nil {
+		o.closeDecoder()
+		o.closeDecoder = nil
+	}
+}
+
+// readerFunc implements io.Reader with a function.
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) {
+	return f(p)
+}
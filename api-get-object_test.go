@@ -18,12 +18,25 @@
 package minio
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/md5"
 	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
 )
 
 func TestGetObjectReturnSuccess(t *testing.T) {
@@ -60,6 +73,42 @@ func TestGetObjectReturnSuccess(t *testing.T) {
 	}
 }
 
+func TestGetObjectErrorResponseCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj, err := clnt.GetObject(context.Background(), "bucket", "object", GetObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer obj.Close()
+
+	if _, err = obj.Stat(); ToErrorResponse(err).Code != "NoSuchKey" {
+		t.Fatalf("Expected NoSuchKey from Stat, got %v", err)
+	}
+	// Later calls must keep reporting the S3 error, not the
+	// cancellation of the stopped routine.
+	buf := make([]byte, 5)
+	if _, err = obj.Read(buf); ToErrorResponse(err).Code != "NoSuchKey" {
+		t.Fatalf("Expected NoSuchKey from Read, got %v", err)
+	}
+	if _, err = obj.ReadAt(buf, 0); ToErrorResponse(err).Code != "NoSuchKey" {
+		t.Fatalf("Expected NoSuchKey from ReadAt, got %v", err)
+	}
+	if errors.Is(err, context.Canceled) {
+		t.Fatalf("Unexpected context.Canceled, got %v", err)
+	}
+}
+
 func TestGetObjectReturnErrorIfServerTruncatesResponse(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
@@ -84,7 +133,7 @@ func TestGetObjectReturnErrorIfServerTruncatesResponse(t *testing.T) {
 	}
 
 	// We expect an error when reading back.
-	if _, err = io.ReadAll(obj); err != io.ErrUnexpectedEOF {
+	if _, err = io.ReadAll(obj); !errors.Is(err, io.ErrUnexpectedEOF) {
 		t.Fatalf("Expected %v, got %v", io.ErrUnexpectedEOF, err)
 	}
 }
@@ -113,7 +162,7 @@ func TestGetObjectReturnErrorIfServerTruncatesResponseDouble(t *testing.T) {
 	}
 
 	// We expect an error when reading back.
-	if _, err = io.ReadAll(obj); err != io.ErrUnexpectedEOF {
+	if _, err = io.ReadAll(obj); !errors.Is(err, io.ErrUnexpectedEOF) {
 		t.Fatalf("Expected %v, got %v", io.ErrUnexpectedEOF, err)
 	}
 }
@@ -142,7 +191,552 @@ func TestGetObjectReturnErrorIfServerSendsMore(t *testing.T) {
 	}
 
 	// We expect an error when reading back.
-	if _, err = io.ReadAll(obj); err != io.ErrUnexpectedEOF {
+	if _, err = io.ReadAll(obj); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected %v, got %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+func TestGetObjectTruncatedResponseErrorContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.Header().Set("Content-Length", "10")
+
+		// Write less bytes than the content length.
+		w.Write([]byte("1234"))
+	}))
+	defer srv.Close()
+
+	// New - instantiate minio client with options
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj, err := clnt.GetObject(context.Background(), "bucketName", "objectName", GetObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := io.ReadAll(obj)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
 		t.Fatalf("Expected %v, got %v", io.ErrUnexpectedEOF, err)
 	}
+	if len(buf) != 4 {
+		t.Fatalf("Expected read bytes '4', got %v", len(buf))
+	}
+	if !strings.Contains(err.Error(), "bucketName/objectName") || !strings.Contains(err.Error(), "‘4’") {
+		t.Fatalf("Expected error to carry object context, got %v", err)
+	}
+
+	// Subsequent reads keep returning the same error.
+	if _, err = obj.Read(make([]byte, 1)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected %v, got %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+func TestGetObjectSSEC(t *testing.T) {
+	var (
+		data   []byte
+		keyMD5 string
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, err := base64.StdEncoding.DecodeString(r.Header.Get(encrypt.SseCustomerKey))
+		sum := md5.Sum(key)
+		if err != nil || r.Header.Get(encrypt.SseCustomerAlgorithm) != "AES256" ||
+			r.Header.Get(encrypt.SseCustomerKeyMD5) != base64.StdEncoding.EncodeToString(sum[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			if r.Method != http.MethodHead {
+				io.WriteString(w, `<Error><Code>InvalidRequest</Code><Message>The object was stored using a form of Server Side Encryption. The correct parameters must be provided to retrieve the object.</Message></Error>`)
+			}
+			return
+		}
+		if r.Method == http.MethodPut {
+			data, _ = io.ReadAll(r.Body)
+			keyMD5 = r.Header.Get(encrypt.SseCustomerKeyMD5)
+			w.Header().Set("ETag", `"etag"`)
+			return
+		}
+		if r.Header.Get(encrypt.SseCustomerKeyMD5) != keyMD5 {
+			w.WriteHeader(http.StatusForbidden)
+			if r.Method != http.MethodHead {
+				io.WriteString(w, `<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`)
+			}
+			return
+		}
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set(encrypt.SseCustomerAlgorithm, "AES256")
+		w.Header().Set(encrypt.SseCustomerKeyMD5, keyMD5)
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sse, err := encrypt.NewSSEC(bytes.Repeat([]byte{'k'}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSSE, err := encrypt.NewSSEC(bytes.Repeat([]byte{'o'}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err = clnt.PutObject(ctx, "bucket", "object", strings.NewReader("secret data"), 11, PutObjectOptions{ServerSideEncryption: sse}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A copy source key is accepted as well.
+	for _, key := range []encrypt.ServerSide{sse, encrypt.SSECopy(sse)} {
+		objInfo, err := clnt.StatObject(ctx, "bucket", "object", StatObjectOptions{ServerSideEncryption: key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if objInfo.Size != 11 {
+			t.Fatalf("Expected size 11, got %d", objInfo.Size)
+		}
+		obj, err := clnt.GetObject(ctx, "bucket", "object", GetObjectOptions{ServerSideEncryption: key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(obj)
+		obj.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != "secret data" {
+			t.Fatalf("Unexpected content %q", b)
+		}
+	}
+
+	if _, err = clnt.StatObject(ctx, "bucket", "object", StatObjectOptions{ServerSideEncryption: otherSSE}); ToErrorResponse(err).StatusCode != http.StatusForbidden {
+		t.Fatalf("Expected access to be denied with another key, got %v", err)
+	}
+	if _, err = clnt.StatObject(ctx, "bucket", "object", StatObjectOptions{}); ToErrorResponse(err).StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected a bad request without key, got %v", err)
+	}
+}
+
+func TestGetObjectReadContextCancel(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Content-Length", "10")
+		w.Write([]byte("hello"))
+		w.(http.Flusher).Flush()
+		// Stall the rest of the body until the request is aborted.
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obj, err := clnt.GetObject(ctx, "bucket", "object", GetObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer obj.Close()
+
+	buf := make([]byte, 5)
+	if _, err = io.ReadFull(obj, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := obj.Read(buf)
+		errCh <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case err = <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Read did not return after the context was cancelled")
+	}
+	if _, err = obj.Read(buf); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if _, err = obj.ReadAt(buf, 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled from ReadAt, got %v", err)
+	}
+}
+
+func TestGetObjectRangeFullSize(t *testing.T) {
+	content := make([]byte, 1000)
+	for i := range content {
+		content[i] = byte(i)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"abc"`)
+		http.ServeContent(w, r, "objectName", time.Date(2015, time.October, 21, 7, 28, 0, 0, time.UTC), bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		start, end int64
+		expected   []byte
+	}{
+		{100, 199, content[100:200]},
+		{900, 0, content[900:]},
+		{0, -100, content[900:]},
+	}
+	for i, testCase := range testCases {
+		opts := GetObjectOptions{}
+		if err = opts.SetRange(testCase.start, testCase.end); err != nil {
+			t.Fatal(err)
+		}
+		obj, err := clnt.GetObject(context.Background(), "bucketName", "objectName", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf, err := io.ReadAll(obj)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if !bytes.Equal(buf, testCase.expected) {
+			t.Fatalf("Test %d: expected %d bytes of range content, got %d bytes", i+1, len(testCase.expected), len(buf))
+		}
+		st, err := obj.Stat()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if st.Size != int64(len(content)) {
+			t.Fatalf("Test %d: expected full size %d, got %d", i+1, len(content), st.Size)
+		}
+		obj.Close()
+
+		reader, st, _, err := Core{clnt}.GetObject(context.Background(), "bucketName", "objectName", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader.Close()
+		if st.Size != int64(len(content)) {
+			t.Fatalf("Test %d: expected full size %d from Core, got %d", i+1, len(content), st.Size)
+		}
+	}
+}
+
+func TestGetObjectAnonymous(t *testing.T) {
+	var authorized []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || r.URL.Query().Get("X-Amz-Signature") != "" {
+			authorized = append(authorized, r.Method+" "+r.URL.Path)
+		}
+		switch {
+		case r.URL.Path == "/public/":
+			io.WriteString(w, `<ListBucketResult><Name>public</Name><KeyCount>1</KeyCount><IsTruncated>false</IsTruncated>`+
+				`<Contents><Key>object</Key><ETag>"etag"</ETag><Size>5</Size></Contents></ListBucketResult>`)
+		case r.URL.Path == "/public/object":
+			w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Content-Length", "5")
+			io.WriteString(w, "hello")
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	for _, creds := range []*credentials.Credentials{nil, credentials.NewAnonymous()} {
+		clnt, err := New(srv.Listener.Addr().String(), &Options{
+			Creds:  creds,
+			Region: "us-east-1",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for object := range clnt.ListObjects(context.Background(), "public", ListObjectsOptions{}) {
+			if object.Err != nil {
+				t.Fatal(object.Err)
+			}
+		}
+		obj, err := clnt.GetObject(context.Background(), "public", "object", GetObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf, err := io.ReadAll(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(buf) != "hello" {
+			t.Fatalf("Expected 'hello', got %q", buf)
+		}
+		if len(authorized) != 0 {
+			t.Fatalf("Expected unsigned requests, got signed %v", authorized)
+		}
+
+		if _, err = clnt.PresignedGetObject(context.Background(), "public", "object", time.Hour, nil); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Fatalf("Expected presign with anonymous credentials to fail, got %v", err)
+		}
+	}
+}
+
+func TestGetObjectConditions(t *testing.T) {
+	const etag = "9b2cf535f27731c974343645a3985328"
+	modTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"`+etag+`"`)
+		http.ServeContent(w, r, "object", modTime, strings.NewReader("hello"))
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1", MaxRetries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		set func(opts *GetObjectOptions) error
+		err error
+	}{
+		{func(opts *GetObjectOptions) error { return opts.SetMatchETag(etag) }, nil},
+		{func(opts *GetObjectOptions) error { return opts.SetMatchETag("0123456789abcdef") }, ErrPreconditionFailed},
+		{func(opts *GetObjectOptions) error { return opts.SetMatchETagExcept("0123456789abcdef") }, nil},
+		{func(opts *GetObjectOptions) error { return opts.SetMatchETagExcept(etag) }, ErrNotModified},
+		{func(opts *GetObjectOptions) error { return opts.SetModified(modTime.Add(-time.Hour)) }, nil},
+		{func(opts *GetObjectOptions) error { return opts.SetModified(modTime) }, ErrNotModified},
+		{func(opts *GetObjectOptions) error { return opts.SetUnmodified(modTime) }, nil},
+		{func(opts *GetObjectOptions) error { return opts.SetUnmodified(modTime.Add(-time.Hour)) }, ErrPreconditionFailed},
+	}
+	for i, testCase := range testCases {
+		var opts GetObjectOptions
+		if err = testCase.set(&opts); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = clnt.StatObject(context.Background(), "bucket", "object", opts)
+		if !errors.Is(err, testCase.err) {
+			t.Fatalf("Test %d: expected stat error %v, got %v", i+1, testCase.err, err)
+		}
+
+		obj, err := clnt.GetObject(context.Background(), "bucket", "object", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf, err := io.ReadAll(obj)
+		obj.Close()
+		if !errors.Is(err, testCase.err) {
+			t.Fatalf("Test %d: expected read error %v, got %v", i+1, testCase.err, err)
+		}
+		if testCase.err == nil && string(buf) != "hello" {
+			t.Fatalf("Test %d: expected 'hello', got %q", i+1, buf)
+		}
+		if testCase.err == ErrNotModified && ToErrorResponse(err).StatusCode != http.StatusNotModified {
+			t.Fatalf("Test %d: expected status %d, got %d", i+1, http.StatusNotModified, ToErrorResponse(err).StatusCode)
+		}
+	}
+}
+
+func TestGetObjectPinVersion(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		versions = []string{"version one"}
+	)
+	modTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		id := len(versions)
+		if v := r.URL.Query().Get("versionId"); v != "" {
+			id, _ = strconv.Atoi(v)
+		}
+		if id < 1 || id > len(versions) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", `"etag-`+strconv.Itoa(id)+`"`)
+		w.Header().Set("X-Amz-Version-Id", strconv.Itoa(id))
+		http.ServeContent(w, r, "object", modTime, strings.NewReader(versions[id-1]))
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1", MaxRetries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reads the start of the object, overwrites it and reads the rest.
+	readOverwritten := func(opts GetObjectOptions) (string, error) {
+		mu.Lock()
+		versions = []string{"version one"}
+		mu.Unlock()
+
+		obj, err := clnt.GetObject(context.Background(), "bucket", "object", opts)
+		if err != nil {
+			return "", err
+		}
+		defer obj.Close()
+		buf := make([]byte, 8)
+		if _, err = io.ReadFull(obj, buf); err != nil {
+			return "", err
+		}
+
+		mu.Lock()
+		versions = append(versions, "version two")
+		mu.Unlock()
+
+		if _, err = obj.Seek(0, io.SeekStart); err != nil {
+			return "", err
+		}
+		data, err := io.ReadAll(obj)
+		if err != nil {
+			return "", err
+		}
+		if _, err = obj.ReadAt(buf[:3], 8); err != nil && err != io.EOF {
+			return "", err
+		}
+		return string(data) + "|" + string(buf[:3]), nil
+	}
+
+	data, err := readOverwritten(GetObjectOptions{PinVersion: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != "version one|one" {
+		t.Fatalf("Expected the first version, got %q", data)
+	}
+
+	data, err = readOverwritten(GetObjectOptions{VersionID: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != "version one|one" {
+		t.Fatalf("Expected the first version, got %q", data)
+	}
+
+	if _, err = readOverwritten(GetObjectOptions{}); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("Expected ErrPreconditionFailed without a pinned version, got %v", err)
+	}
+}
+
+func TestGetObjectAutoDecompress(t *testing.T) {
+	content := bytes.Repeat([]byte("hello, compressed world\n"), 10000)
+
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	gw.Write(content)
+	gw.Close()
+	zw, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zstded := zw.EncodeAll(content, nil)
+	zw.Close()
+
+	objects := map[string]struct {
+		encoding string
+		data     []byte
+	}{
+		"gzip":   {"gzip", gzipped.Bytes()},
+		"zstd":   {"zstd", zstded},
+		"plain":  {"", content},
+		"brotli": {"br", []byte("not really brotli")},
+		"empty":  {"gzip", nil},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		object, ok := objects[strings.TrimPrefix(r.URL.Path, "/bucket/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if object.encoding != "" {
+			w.Header().Set("Content-Encoding", object.encoding)
+		}
+		w.Header().Set("ETag", `"etag"`)
+		http.ServeContent(w, r, "object", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), bytes.NewReader(object.data))
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1", MaxRetries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"gzip", "zstd", "plain"} {
+		obj, err := clnt.GetObject(context.Background(), "bucket", name, GetObjectOptions{AutoDecompress: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(obj)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !bytes.Equal(data, content) {
+			t.Fatalf("%s: expected %d decompressed bytes, got %d", name, len(content), len(data))
+		}
+		if _, err = obj.Seek(0, io.SeekStart); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Fatalf("%s: expected Seek to fail with InvalidArgument, got %v", name, err)
+		}
+		if _, err = obj.ReadAt(make([]byte, 10), 0); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Fatalf("%s: expected ReadAt to fail with InvalidArgument, got %v", name, err)
+		}
+		if err = obj.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Without AutoDecompress the stored bytes are read.
+	obj, err := clnt.GetObject(context.Background(), "bucket", "zstd", GetObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(obj)
+	obj.Close()
+	if err != nil || !bytes.Equal(data, zstded) {
+		t.Fatalf("Expected the compressed bytes, got %d bytes, %v", len(data), err)
+	}
+
+	obj, err = clnt.GetObject(context.Background(), "bucket", "empty", GetObjectOptions{AutoDecompress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err = io.ReadAll(obj); err != nil || len(data) != 0 {
+		t.Fatalf("Expected an empty object, got %d bytes, %v", len(data), err)
+	}
+	obj.Close()
+
+	obj, err = clnt.GetObject(context.Background(), "bucket", "brotli", GetObjectOptions{AutoDecompress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer obj.Close()
+	if _, err = io.ReadAll(obj); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Fatalf("Expected unsupported encoding to fail with InvalidArgument, got %v", err)
+	}
 }
@@ -35,19 +35,46 @@ type AdvancedGetOptions struct {
 }
 
 // GetObjectOptions are used to specify additional headers or options
-// during GET requests.
+// during GET requests. Objects encrypted with SSE-C are read by setting
+// ServerSideEncryption to the SSE-C key (see encrypt.NewSSEC), the
+// customer algorithm, key and key MD5 headers are computed from it.
+// SSE-S3 and SSE-KMS objects are decrypted by the server without any
+// headers, other encryption types are ignored.
 type GetObjectOptions struct {
 	headers              map[string]string
 	reqParams            url.Values
 	ServerSideEncryption encrypt.ServerSide
-	VersionID            string
-	PartNumber           int
+	// VersionID reads a specific version of the object, it is sent
+	// with every request made by the reader of GetObject.
+	VersionID  string
+	PartNumber int
+
+	// RequestPayer sends x-amz-request-payer: requester to read from
+	// a requester pays bucket, see also Options.RequestPayer.
+	RequestPayer bool
+
+	// AutoDecompress decodes the content read from the Object returned
+	// by GetObject according to its Content-Encoding, gzip or zstd,
+	// other encodings fail the first Read. Seek and ReadAt are not
+	// supported then, Stat still reports the stored size.
+	AutoDecompress bool
+
+	// PinVersion pins the reader of GetObject to the version returned
+	// by its first request when VersionID is not set. Requests made
+	// for Seek and ReadAt then keep reading that version even if the
+	// object is overwritten meanwhile, instead of failing with
+	// ErrPreconditionFailed. Requires a versioned bucket.
+	PinVersion bool
 
 	// Include any checksums, if object was uploaded with checksum.
 	// For multipart objects this is a checksum of part checksums.
 	// https://docs.aws.amazon.com/AmazonS3/latest/userguide/checking-object-integrity.html
 	Checksum bool
 
+	// Set the modification time of the file written by FGetObject
+	// to the Last-Modified time of the object.
+	PreserveModTime bool
+
 	// To be not used by external applications
 	Internal AdvancedGetOptions
 }
@@ -63,7 +90,8 @@ func (o GetObjectOptions) Header() http.Header {
 		headers.Set(k, v)
 	}
 	if o.ServerSideEncryption != nil && o.ServerSideEncryption.Type() == encrypt.SSEC {
-		o.ServerSideEncryption.Marshal(headers)
+		// A copy source key is sent as the SSE-C key of the object.
+		encrypt.SSE(o.ServerSideEncryption).Marshal(headers)
 	}
 	// this header is set for active-active replication scenario where GET/HEAD
 	// to site A is proxy'd to site B if object/version missing on site A.
@@ -73,6 +101,9 @@ func (o GetObjectOptions) Header() http.Header {
 	if o.Checksum {
 		headers.Set("x-amz-checksum-mode", "ENABLED")
 	}
+	if o.RequestPayer {
+		headers.Set(amzRequestPayer, requester)
+	}
 	return headers
 }
 
@@ -114,7 +145,8 @@ func (o *GetObjectOptions) AddReqParam(key, value string) {
 	o.reqParams.Add(key, value)
 }
 
-// SetMatchETag - set match etag.
+// SetMatchETag - set match etag, the request fails with an error
+// matching ErrPreconditionFailed if the object ETag differs.
 func (o *GetObjectOptions) SetMatchETag(etag string) error {
 	if etag == "" {
 		return errInvalidArgument("ETag cannot be empty.")
@@ -123,7 +155,8 @@ func (o *GetObjectOptions) SetMatchETag(etag string) error {
 	return nil
 }
 
-// SetMatchETagExcept - set match etag except.
+// SetMatchETagExcept - set match etag except, the request fails with
+// an error matching ErrNotModified if the object ETag is the same.
 func (o *GetObjectOptions) SetMatchETagExcept(etag string) error {
 	if etag == "" {
 		return errInvalidArgument("ETag cannot be empty.")
@@ -132,25 +165,30 @@ func (o *GetObjectOptions) SetMatchETagExcept(etag string) error {
 	return nil
 }
 
-// SetUnmodified - set unmodified time since.
+// SetUnmodified - set unmodified time since, the request fails with an
+// error matching ErrPreconditionFailed if the object was modified after.
 func (o *GetObjectOptions) SetUnmodified(modTime time.Time) error {
 	if modTime.IsZero() {
 		return errInvalidArgument("Modified since cannot be empty.")
 	}
-	o.Set("If-Unmodified-Since", modTime.Format(http.TimeFormat))
+	o.Set("If-Unmodified-Since", modTime.UTC().Format(http.TimeFormat))
 	return nil
 }
 
-// SetModified - set modified time since.
+// SetModified - set modified time since, the request fails with an
+// error matching ErrNotModified if the object was not modified after.
 func (o *GetObjectOptions) SetModified(modTime time.Time) error {
 	if modTime.IsZero() {
 		return errInvalidArgument("Modified since cannot be empty.")
 	}
-	o.Set("If-Modified-Since", modTime.Format(http.TimeFormat))
+	o.Set("If-Modified-Since", modTime.UTC().Format(http.TimeFormat))
 	return nil
 }
 
 // SetRange - set the start and end offset of the object to be read.
+// SetRange(start, 0) reads from start to the end of the object and
+// SetRange(0, -n) reads the last n bytes. The ObjectInfo of a ranged
+// read reports the full object size, not the length of the range.
 // See https://tools.ietf.org/html/rfc7233#section-3.1 for reference.
 func (o *GetObjectOptions) SetRange(start, end int64) error {
 	switch {
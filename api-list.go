@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/minio/minio-go/v7/pkg/s3utils"
@@ -56,16 +57,73 @@ func (c *Client) ListBuckets(ctx context.Context) ([]BucketInfo, error) {
 	return listAllMyBucketsResult.Buckets.Bucket, nil
 }
 
+// ListBucketsOptions holds all options of a list buckets request.
+type ListBucketsOptions struct {
+	// Only return buckets whose name starts with this prefix.
+	Prefix string
+
+	// Fill the region of every bucket, regions are resolved
+	// concurrently and cached like for any other request.
+	WithRegion bool
+
+	// Number of regions resolved concurrently, defaults to 4.
+	Concurrency int
+}
+
+// ListBucketsWithOptions - list the buckets owned by the authenticated
+// user, optionally filtered by a name prefix and with their region.
+//
+//	api := client.New(....)
+//	buckets, err := api.ListBucketsWithOptions(context.Background(), minio.ListBucketsOptions{
+//	    Prefix:     "logs-",
+//	    WithRegion: true,
+//	})
+func (c *Client) ListBucketsWithOptions(ctx context.Context, opts ListBucketsOptions) ([]BucketInfo, error) {
+	allBuckets, err := c.ListBuckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	buckets := allBuckets[:0]
+	for _, bucket := range allBuckets {
+		if strings.HasPrefix(bucket.Name, opts.Prefix) {
+			buckets = append(buckets, bucket)
+		}
+	}
+	if !opts.WithRegion {
+		return buckets, nil
+	}
+
+	g, gctx := newWorkerGroup(ctx, opts.Concurrency)
+	for i := range buckets {
+		// Some servers return the region of every bucket already.
+		if buckets[i].Region != "" {
+			c.bucketLocCache.Set(buckets[i].Name, buckets[i].Region)
+			continue
+		}
+		g.Go(func() error {
+			region, err := c.getBucketLocation(gctx, buckets[i].Name)
+			if err != nil {
+				return err
+			}
+			buckets[i].Region = region
+			return nil
+		})
+	}
+	if err = g.Wait(); err != nil {
+		return nil, err
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
 // Bucket List Operations.
 func (c *Client) listObjectsV2(ctx context.Context, bucketName string, opts ListObjectsOptions) <-chan ObjectInfo {
 	// Allocate new list objects channel.
 	objectStatCh := make(chan ObjectInfo, 1)
 	// Default listing is delimited at "/"
-	delimiter := "/"
-	if opts.Recursive {
-		// If recursive we do not delimit.
-		delimiter = ""
-	}
+	delimiter := opts.delimiter()
 
 	// Return object owner information by default
 	fetchOwner := true
@@ -136,7 +194,7 @@ func (c *Client) listObjectsV2(ctx context.Context, bucketName string, opts List
 			for _, obj := range result.CommonPrefixes {
 				select {
 				// Send object prefixes.
-				case objectStatCh <- ObjectInfo{Key: obj.Prefix}:
+				case objectStatCh <- ObjectInfo{Key: obj.Prefix, IsPrefix: true}:
 				// If receives done from the caller, return here.
 				case <-ctx.Done():
 					return
@@ -252,8 +310,9 @@ func (c *Client) listObjectsV2Query(ctx context.Context, bucketName, objectPrefi
 	// sure proper responses are received.
 	if listBucketResult.IsTruncated && listBucketResult.NextContinuationToken == "" {
 		return listBucketResult, ErrorResponse{
-			Code:    "NotImplemented",
-			Message: "Truncated response should have continuation token set",
+			StatusCode: http.StatusNotImplemented,
+			Code:       "NotImplemented",
+			Message:    "Truncated response should have continuation token set",
 		}
 	}
 
@@ -280,11 +339,7 @@ func (c *Client) listObjects(ctx context.Context, bucketName string, opts ListOb
 	// Allocate new list objects channel.
 	objectStatCh := make(chan ObjectInfo, 1)
 	// Default listing is delimited at "/"
-	delimiter := "/"
-	if opts.Recursive {
-		// If recursive we do not delimit.
-		delimiter = ""
-	}
+	delimiter := opts.delimiter()
 
 	sendObjectInfo := func(info ObjectInfo) {
 		select {
@@ -351,7 +406,7 @@ func (c *Client) listObjects(ctx context.Context, bucketName string, opts ListOb
 			for _, obj := range result.CommonPrefixes {
 				select {
 				// Send object prefixes.
-				case objectStatCh <- ObjectInfo{Key: obj.Prefix}:
+				case objectStatCh <- ObjectInfo{Key: obj.Prefix, IsPrefix: true}:
 				// If receives done from the caller, return here.
 				case <-ctx.Done():
 					return
@@ -376,11 +431,7 @@ func (c *Client) listObjectVersions(ctx context.Context, bucketName string, opts
 	// Allocate new list objects channel.
 	resultCh := make(chan ObjectInfo, 1)
 	// Default listing is delimited at "/"
-	delimiter := "/"
-	if opts.Recursive {
-		// If recursive we do not delimit.
-		delimiter = ""
-	}
+	delimiter := opts.delimiter()
 
 	sendObjectInfo := func(info ObjectInfo) {
 		select {
@@ -463,7 +514,7 @@ func (c *Client) listObjectVersions(ctx context.Context, bucketName string, opts
 			for _, obj := range result.CommonPrefixes {
 				select {
 				// Send object prefixes.
-				case resultCh <- ObjectInfo{Key: obj.Prefix}:
+				case resultCh <- ObjectInfo{Key: obj.Prefix, IsPrefix: true}:
 				// If receives done from the caller, return here.
 				case <-ctx.Done():
 					return
@@ -689,8 +740,12 @@ type ListObjectsOptions struct {
 	WithMetadata bool
 	// Only list objects with the prefix
 	Prefix string
-	// Ignore '/' delimiter
+	// Ignore the delimiter, list all objects under the prefix
 	Recursive bool
+	// Delimiter groups the keys sharing the part of their name
+	// up to the delimiter after the prefix into a single entry
+	// with IsPrefix set, "/" by default. Ignored when Recursive.
+	Delimiter string
 	// The maximum number of objects requested per
 	// batch, advanced use-case not useful for most
 	// applications
@@ -703,9 +758,29 @@ type ListObjectsOptions struct {
 	// Use the deprecated list objects V1 API
 	UseV1 bool
 
+	// RequestPayer sends x-amz-request-payer: requester to list a
+	// requester pays bucket, see also Options.RequestPayer.
+	RequestPayer bool
+
+	// WithRestoreStatus fills ObjectInfo.Restore of archived objects,
+	// sends x-amz-optional-object-attributes: RestoreStatus.
+	WithRestoreStatus bool
+
 	headers http.Header
 }
 
+// delimiter returns the delimiter of the listing, empty
+// for a recursive listing.
+func (o ListObjectsOptions) delimiter() string {
+	if o.Recursive {
+		return ""
+	}
+	if o.Delimiter == "" {
+		return "/"
+	}
+	return o.Delimiter
+}
+
 // Set adds a key value pair to the options. The
 // key-value pair will be part of the HTTP GET request
 // headers.
@@ -727,6 +802,16 @@ func (o *ListObjectsOptions) Set(key, value string) {
 // caller must drain the channel entirely and wait until channel is closed before proceeding, without
 // waiting on the channel to be closed completely you might leak goroutines.
 func (c *Client) ListObjects(ctx context.Context, bucketName string, opts ListObjectsOptions) <-chan ObjectInfo {
+	if opts.RequestPayer || opts.WithRestoreStatus {
+		opts.headers = opts.headers.Clone()
+	}
+	if opts.RequestPayer {
+		opts.Set(amzRequestPayer, requester)
+	}
+	if opts.WithRestoreStatus {
+		opts.Set(amzOptionalObjectAttributes, "RestoreStatus")
+	}
+
 	if opts.WithVersions {
 		return c.listObjectVersions(ctx, bucketName, opts)
 	}
@@ -763,7 +848,57 @@ func (c *Client) ListObjects(ctx context.Context, bucketName string, opts ListOb
 //	    fmt.Println(message)
 //	}
 func (c *Client) ListIncompleteUploads(ctx context.Context, bucketName, objectPrefix string, recursive bool) <-chan ObjectMultipartInfo {
-	return c.listIncompleteUploads(ctx, bucketName, objectPrefix, recursive)
+	return c.listIncompleteUploads(ctx, bucketName, objectPrefix, recursive, false)
+}
+
+// ListAllIncompleteUploads - List every in-progress multipart upload in
+// the bucket, regardless of its key. Results are paginated using both
+// the key and the upload id markers, so several uploads on the same
+// key are all returned, each with its initiated time and owner.
+//
+//	api := client.New(....)
+//	for upload := range api.ListAllIncompleteUploads(context.Background(), "mytestbucket") {
+//	    if upload.Err != nil {
+//	        fmt.Println(upload.Err)
+//	        return
+//	    }
+//	    fmt.Println(upload.Key, upload.UploadID, upload.Initiated)
+//	}
+func (c *Client) ListAllIncompleteUploads(ctx context.Context, bucketName string) <-chan ObjectMultipartInfo {
+	return c.listIncompleteUploads(ctx, bucketName, "", true, false)
+}
+
+// ListIncompleteUploadsOptions holds all options of a list incomplete
+// uploads request.
+type ListIncompleteUploadsOptions struct {
+	// Only list uploads of objects with this prefix.
+	Prefix string
+
+	// Recursive list, otherwise uploads are grouped by the "/"
+	// delimiter and common prefixes are returned as entries.
+	Recursive bool
+
+	// Fill Size with the total size of the parts uploaded so far,
+	// this lists the parts of every upload.
+	WithSize bool
+}
+
+// ListIncompleteUploadsWithOptions - List in-progress multipart uploads
+// with their upload id and initiated time. With opts.WithSize the size
+// of the parts uploaded so far is filled in, which shows how much space
+// pending uploads consume.
+//
+//	api := client.New(....)
+//	opts := minio.ListIncompleteUploadsOptions{Recursive: true, WithSize: true}
+//	for upload := range api.ListIncompleteUploadsWithOptions(context.Background(), "mytestbucket", opts) {
+//	    if upload.Err != nil {
+//	        fmt.Println(upload.Err)
+//	        return
+//	    }
+//	    fmt.Println(upload.Key, upload.UploadID, upload.Initiated, upload.Size)
+//	}
+func (c *Client) ListIncompleteUploadsWithOptions(ctx context.Context, bucketName string, opts ListIncompleteUploadsOptions) <-chan ObjectMultipartInfo {
+	return c.listIncompleteUploads(ctx, bucketName, opts.Prefix, opts.Recursive, opts.WithSize)
 }
 
 // contextCanceled returns whether a context is canceled.
@@ -776,8 +911,9 @@ func contextCanceled(ctx context.Context) bool {
 	}
 }
 
-// listIncompleteUploads lists all incomplete uploads.
-func (c *Client) listIncompleteUploads(ctx context.Context, bucketName, objectPrefix string, recursive bool) <-chan ObjectMultipartInfo {
+// listIncompleteUploads lists all incomplete uploads, the size of the
+// uploaded parts is filled in when aggregateSize is set.
+func (c *Client) listIncompleteUploads(ctx context.Context, bucketName, objectPrefix string, recursive, aggregateSize bool) <-chan ObjectMultipartInfo {
 	// Allocate channel for multipart uploads.
 	objectMultipartStatCh := make(chan ObjectMultipartInfo, 1)
 	// Delimiter is set to "/" by default.
@@ -826,10 +962,25 @@ func (c *Client) listIncompleteUploads(ctx context.Context, bucketName, objectPr
 			}
 			objectMarker = result.NextKeyMarker
 			uploadIDMarker = result.NextUploadIDMarker
+			// Some S3 compatible servers do not return the next markers,
+			// continue listing after the last upload returned instead.
+			if result.IsTruncated && objectMarker == "" && uploadIDMarker == "" && len(result.Uploads) > 0 {
+				last := result.Uploads[len(result.Uploads)-1]
+				objectMarker, uploadIDMarker = last.Key, last.UploadID
+			}
 
 			// Send all multipart uploads.
 			for _, obj := range result.Uploads {
 				// Calculate total size of the uploaded parts if 'aggregateSize' is enabled.
+				if aggregateSize {
+					obj.Size, err = c.getTotalMultipartSize(ctx, bucketName, obj.Key, obj.UploadID)
+					if err != nil {
+						objectMultipartStatCh <- ObjectMultipartInfo{
+							Err: err,
+						}
+						return
+					}
+				}
 				select {
 				// Send individual uploads here.
 				case objectMultipartStatCh <- obj:
@@ -859,6 +1010,61 @@ func (c *Client) listIncompleteUploads(ctx context.Context, bucketName, objectPr
 	return objectMultipartStatCh
 }
 
+// ListMultipartUploadsOptions holds all options of a list multipart
+// uploads query.
+type ListMultipartUploadsOptions struct {
+	// Only list uploads of objects with the prefix.
+	Prefix string
+
+	// Group the uploads of objects whose name contains the delimiter
+	// after the prefix into CommonPrefixes, such as "/".
+	Delimiter string
+
+	// List the uploads after KeyMarker, and after UploadIDMarker for
+	// the uploads of KeyMarker itself. Set them to NextKeyMarker and
+	// NextUploadIDMarker of a truncated result to list the next page.
+	KeyMarker      string
+	UploadIDMarker string
+
+	// The maximum number of uploads and common prefixes of a page,
+	// at most 1000 which is the default.
+	MaxUploads int
+}
+
+// ListMultipartUploads - Lists a page of in-progress multipart uploads,
+// with their initiated time, storage class and initiator, and of common
+// prefixes when a delimiter is set. List the next page with the markers
+// of the result as long as it IsTruncated.
+//
+//	opts := minio.ListMultipartUploadsOptions{Delimiter: "/"}
+//	for {
+//	    result, err := api.ListMultipartUploads(context.Background(), "mytestbucket", opts)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(result.Uploads, result.CommonPrefixes)
+//	    if !result.IsTruncated {
+//	        break
+//	    }
+//	    opts.KeyMarker, opts.UploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
+//	}
+func (c *Client) ListMultipartUploads(ctx context.Context, bucketName string, opts ListMultipartUploadsOptions) (ListMultipartUploadsResult, error) {
+	// Input validation.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		return ListMultipartUploadsResult{}, err
+	}
+	if err := s3utils.CheckValidObjectNamePrefix(opts.Prefix); err != nil {
+		return ListMultipartUploadsResult{}, err
+	}
+	if opts.MaxUploads < 0 || opts.MaxUploads > 1000 {
+		return ListMultipartUploadsResult{}, errInvalidArgument("MaxUploads must be between 0 and 1000.")
+	}
+	if opts.UploadIDMarker != "" && opts.KeyMarker == "" {
+		return ListMultipartUploadsResult{}, errInvalidArgument("UploadIDMarker cannot be set without KeyMarker.")
+	}
+	return c.listMultipartUploadsQuery(ctx, bucketName, opts.KeyMarker, opts.UploadIDMarker, opts.Prefix, opts.Delimiter, opts.MaxUploads)
+}
+
 // listMultipartUploadsQuery - (List Multipart Uploads).
 //   - Lists some or all (up to 1000) in-progress multipart uploads in a bucket.
 //
@@ -948,9 +1154,20 @@ func (c *Client) listMultipartUploadsQuery(ctx context.Context, bucketName, keyM
 	return listMultipartUploadsResult, nil
 }
 
+// getTotalMultipartSize - returns the total size of the parts
+// uploaded so far for a multipart upload.
+func (c *Client) getTotalMultipartSize(ctx context.Context, bucketName, objectName, uploadID string) (size int64, err error) {
+	partsInfo, err := c.listObjectParts(ctx, bucketName, objectName, uploadID)
+	if err != nil {
+		return 0, err
+	}
+	for _, partInfo := range partsInfo {
+		size += partInfo.Size
+	}
+	return size, nil
+}
+
 // listObjectParts list all object parts recursively.
-//
-//lint:ignore U1000 Keep this around
 func (c *Client) listObjectParts(ctx context.Context, bucketName, objectName, uploadID string) (partsInfo map[int]ObjectPart, err error) {
 	// Part number marker for the next batch of request.
 	var nextPartNumberMarker int
@@ -985,7 +1202,7 @@ func (c *Client) findUploadIDs(ctx context.Context, bucketName, objectName strin
 	// Make list incomplete uploads recursive.
 	isRecursive := true
 	// List all incomplete uploads.
-	for mpUpload := range c.listIncompleteUploads(ctx, bucketName, objectName, isRecursive) {
+	for mpUpload := range c.listIncompleteUploads(ctx, bucketName, objectName, isRecursive, false) {
 		if mpUpload.Err != nil {
 			return nil, mpUpload.Err
 		}
@@ -1008,6 +1225,20 @@ func (c *Client) findUploadIDs(ctx context.Context, bucketName, objectName strin
 // begin.
 // ?max-parts - Maximum parts to be listed per request.
 func (c *Client) listObjectPartsQuery(ctx context.Context, bucketName, objectName, uploadID string, partNumberMarker, maxParts int) (ListObjectPartsResult, error) {
+	// Input validation.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		return ListObjectPartsResult{}, err
+	}
+	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+		return ListObjectPartsResult{}, err
+	}
+	if uploadID == "" {
+		return ListObjectPartsResult{}, errInvalidArgument("uploadID cannot be empty.")
+	}
+	if partNumberMarker < 0 {
+		return ListObjectPartsResult{}, errInvalidArgument("partNumberMarker cannot be negative.")
+	}
+
 	// Get resources properly escaped and lined up before using them in http request.
 	urlValues := make(url.Values)
 	// Set part number marker.
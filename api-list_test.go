@@ -0,0 +1,534 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// newListMultipartUploadsServer returns a server listing the given
+// uploads two at a time, the uploads must be sorted by key and upload id.
+func newListMultipartUploadsServer(t *testing.T, uploads []ObjectMultipartInfo, omitNextMarkers bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if _, ok := q["uploads"]; !ok || q.Get("prefix") != "" || q.Get("delimiter") != "" {
+			t.Errorf("Unexpected list multipart uploads query %s", r.URL.RawQuery)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		keyMarker, uploadIDMarker := q.Get("key-marker"), q.Get("upload-id-marker")
+		start := 0
+		if keyMarker != "" {
+			for start < len(uploads) {
+				u := uploads[start]
+				start++
+				if u.Key == keyMarker && u.UploadID == uploadIDMarker {
+					break
+				}
+			}
+		}
+		end := start + 2
+		if end > len(uploads) {
+			end = len(uploads)
+		}
+		result := ListMultipartUploadsResult{
+			Bucket:      "bucket",
+			Uploads:     uploads[start:end],
+			IsTruncated: end < len(uploads),
+		}
+		if result.IsTruncated && !omitNextMarkers {
+			result.NextKeyMarker = uploads[end-1].Key
+			result.NextUploadIDMarker = uploads[end-1].UploadID
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		if err := xml.NewEncoder(w).Encode(struct {
+			XMLName xml.Name `xml:"ListMultipartUploadsResult"`
+			ListMultipartUploadsResult
+		}{ListMultipartUploadsResult: result}); err != nil {
+			t.Error(err)
+		}
+	}))
+}
+
+func TestListObjectsOwner(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fetch-owner") != "true" {
+			t.Errorf("Expected fetch-owner=true, got %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<ListBucketResult><Name>bucket</Name><KeyCount>1</KeyCount><Contents><Key>object</Key>` +
+			`<LastModified>2015-10-21T07:28:00.000Z</LastModified><ETag>"etag"</ETag><Size>5</Size>` +
+			`<Owner><ID>owner-id</ID><DisplayName>owner</DisplayName></Owner></Contents></ListBucketResult>`))
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var objs []ObjectInfo
+	for obj := range clnt.ListObjects(context.Background(), "bucket", ListObjectsOptions{}) {
+		if obj.Err != nil {
+			t.Fatal(obj.Err)
+		}
+		objs = append(objs, obj)
+	}
+	if len(objs) != 1 {
+		t.Fatalf("Expected 1 object, got %d", len(objs))
+	}
+	if objs[0].Owner.ID != "owner-id" || objs[0].Owner.DisplayName != "owner" {
+		t.Fatalf("Unexpected owner %+v", objs[0].Owner)
+	}
+}
+
+func TestListAllIncompleteUploads(t *testing.T) {
+	initiated := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+	var uploads []ObjectMultipartInfo
+	for _, u := range [][2]string{{"a", "u1"}, {"a", "u2"}, {"b/c", "u1"}, {"d", "u1"}, {"d", "u2"}} {
+		uploads = append(uploads, ObjectMultipartInfo{
+			Key:       u[0],
+			UploadID:  u[1],
+			Initiated: initiated,
+			Owner:     owner{ID: "owner-id", DisplayName: "owner"},
+		})
+	}
+
+	for _, omitNextMarkers := range []bool{false, true} {
+		srv := newListMultipartUploadsServer(t, uploads, omitNextMarkers)
+		clnt, err := New(srv.Listener.Addr().String(), &Options{
+			Region: "us-east-1",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []ObjectMultipartInfo
+		for upload := range clnt.ListAllIncompleteUploads(context.Background(), "bucket") {
+			if upload.Err != nil {
+				t.Fatal(upload.Err)
+			}
+			got = append(got, upload)
+		}
+		srv.Close()
+
+		if len(got) != len(uploads) {
+			t.Fatalf("Expected %d uploads, got %d", len(uploads), len(got))
+		}
+		for i := range uploads {
+			if got[i].Key != uploads[i].Key || got[i].UploadID != uploads[i].UploadID {
+				t.Fatalf("Expected upload %s/%s, got %s/%s", uploads[i].Key, uploads[i].UploadID, got[i].Key, got[i].UploadID)
+			}
+			if !got[i].Initiated.Equal(initiated) || got[i].Owner.DisplayName != "owner" {
+				t.Fatalf("Unexpected upload metadata %+v", got[i])
+			}
+		}
+	}
+}
+
+func TestListIncompleteUploadsWithSize(t *testing.T) {
+	initiated := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+	uploads := []ObjectMultipartInfo{
+		{Key: "a", UploadID: "u1", Initiated: initiated},
+		{Key: "a", UploadID: "u2", Initiated: initiated.Add(time.Hour)},
+		{Key: "b", UploadID: "u1", Initiated: initiated.Add(2 * time.Hour)},
+	}
+	// Parts uploaded so far per upload.
+	parts := map[string][]int64{
+		"/bucket/a?u1": {5 << 20, 5 << 20, 100},
+		"/bucket/a?u2": {},
+		"/bucket/b?u1": {5 << 20},
+	}
+	listSrv := newListMultipartUploadsServer(t, uploads, false)
+	defer listSrv.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploadID := r.URL.Query().Get("uploadId")
+		if uploadID == "" {
+			listSrv.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+		var result ListObjectPartsResult
+		for i, size := range parts[r.URL.Path+"?"+uploadID] {
+			result.ObjectParts = append(result.ObjectParts, ObjectPart{PartNumber: i + 1, Size: size})
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		if err := xml.NewEncoder(w).Encode(struct {
+			XMLName xml.Name `xml:"ListPartsResult"`
+			ListObjectPartsResult
+		}{ListObjectPartsResult: result}); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSizes := []int64{10<<20 + 100, 0, 5 << 20}
+	for _, withSize := range []bool{false, true} {
+		var got []ObjectMultipartInfo
+		for upload := range clnt.ListIncompleteUploadsWithOptions(context.Background(), "bucket", ListIncompleteUploadsOptions{
+			Recursive: true,
+			WithSize:  withSize,
+		}) {
+			if upload.Err != nil {
+				t.Fatal(upload.Err)
+			}
+			got = append(got, upload)
+		}
+		if len(got) != len(uploads) {
+			t.Fatalf("Expected %d uploads, got %d", len(uploads), len(got))
+		}
+		for i := range uploads {
+			if got[i].UploadID != uploads[i].UploadID || !got[i].Initiated.Equal(uploads[i].Initiated) {
+				t.Fatalf("Expected upload id %s initiated at %v, got %s at %v", uploads[i].UploadID, uploads[i].Initiated, got[i].UploadID, got[i].Initiated)
+			}
+			expectedSize := int64(0)
+			if withSize {
+				expectedSize = expectedSizes[i]
+			}
+			if got[i].Size != expectedSize {
+				t.Fatalf("Upload %s/%s: expected size %d, got %d", got[i].Key, got[i].UploadID, expectedSize, got[i].Size)
+			}
+		}
+	}
+}
+
+func TestListBucketsWithOptions(t *testing.T) {
+	regions := map[string]string{
+		"alpha-1": "eu-west-1",
+		"alpha-2": "us-west-2",
+		"alpha-3": "ap-south-1",
+		"beta":    "us-east-2",
+	}
+	var (
+		mu            sync.Mutex
+		locationCalls []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		if _, ok := r.URL.Query()["location"]; ok {
+			bucket := strings.Trim(r.URL.Path, "/")
+			mu.Lock()
+			locationCalls = append(locationCalls, bucket)
+			mu.Unlock()
+			fmt.Fprintf(w, `<LocationConstraint>%s</LocationConstraint>`, regions[bucket])
+			return
+		}
+		io.WriteString(w, `<ListAllMyBucketsResult><Buckets>`+
+			`<Bucket><Name>alpha-1</Name><CreationDate>2024-01-02T03:04:05.000Z</CreationDate></Bucket>`+
+			`<Bucket><Name>alpha-2</Name><CreationDate>2024-01-02T03:04:05.000Z</CreationDate></Bucket>`+
+			`<Bucket><Name>alpha-3</Name><CreationDate>2024-01-02T03:04:05.000Z</CreationDate><BucketRegion>ap-south-1</BucketRegion></Bucket>`+
+			`<Bucket><Name>beta</Name><CreationDate>2024-01-02T03:04:05.000Z</CreationDate></Bucket>`+
+			`</Buckets></ListAllMyBucketsResult>`)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Creds: credentials.NewStaticV4("accessKey", "secretKey", ""),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The region of alpha-1 is known already.
+	clnt.bucketLocCache.Set("alpha-1", "eu-west-1")
+
+	buckets, err := clnt.ListBucketsWithOptions(context.Background(), ListBucketsOptions{Prefix: "alpha-", WithRegion: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 3 {
+		t.Fatalf("Expected 3 buckets, got %+v", buckets)
+	}
+	for _, bucket := range buckets {
+		if bucket.Region != regions[bucket.Name] {
+			t.Fatalf("Expected region %s for %s, got %s", regions[bucket.Name], bucket.Name, bucket.Region)
+		}
+	}
+	if len(locationCalls) != 1 || locationCalls[0] != "alpha-2" {
+		t.Fatalf("Expected a single location lookup for alpha-2, got %v", locationCalls)
+	}
+	if location, ok := clnt.bucketLocCache.Get("alpha-3"); !ok || location != "ap-south-1" {
+		t.Fatalf("Expected the listed region of alpha-3 to be cached, got %q", location)
+	}
+
+	// Regions are only resolved when asked for.
+	buckets, err = clnt.ListBucketsWithOptions(context.Background(), ListBucketsOptions{Prefix: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 1 || buckets[0].Name != "beta" || buckets[0].Region != "" {
+		t.Fatalf("Expected only beta without a region, got %+v", buckets)
+	}
+	if len(locationCalls) != 1 {
+		t.Fatalf("Expected no further location lookups, got %v", locationCalls)
+	}
+}
+
+func TestListObjectsReplicationAndRestore(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Optional-Object-Attributes") != "RestoreStatus" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, `<ListBucketResult><Name>bucket</Name><KeyCount>2</KeyCount><IsTruncated>false</IsTruncated>`+
+			`<Contents><Key>archived</Key><ETag>"etag"</ETag><Size>1</Size><ReplicationStatus>FAILED</ReplicationStatus>`+
+			`<RestoreStatus><IsRestoreInProgress>false</IsRestoreInProgress><RestoreExpiryDate>2012-12-21T00:00:00.000Z</RestoreExpiryDate></RestoreStatus></Contents>`+
+			`<Contents><Key>plain</Key><ETag>"etag"</ETag><Size>1</Size></Contents></ListBucketResult>`)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := ListObjectsOptions{Recursive: true, WithRestoreStatus: true}
+	var objects []ObjectInfo
+	for object := range clnt.ListObjects(context.Background(), "bucket", opts) {
+		if object.Err != nil {
+			t.Fatal(object.Err)
+		}
+		objects = append(objects, object)
+	}
+	if len(objects) != 2 {
+		t.Fatalf("Expected 2 objects, got %d", len(objects))
+	}
+	if objects[0].ReplicationStatus != "FAILED" {
+		t.Fatalf("Expected replication status FAILED, got %q", objects[0].ReplicationStatus)
+	}
+	expected := &RestoreInfo{ExpiryTime: time.Date(2012, time.December, 21, 0, 0, 0, 0, time.UTC)}
+	if !reflect.DeepEqual(objects[0].Restore, expected) {
+		t.Fatalf("Expected restore %+v, got %+v", expected, objects[0].Restore)
+	}
+	if objects[1].ReplicationStatus != "" || objects[1].Restore != nil {
+		t.Fatalf("Expected no replication status or restore, got %q %+v", objects[1].ReplicationStatus, objects[1].Restore)
+	}
+}
+
+func TestListMultipartUploads(t *testing.T) {
+	type upload struct{ key, uploadID string }
+	uploads := []upload{{"a/1", "u1"}, {"a/1", "u2"}, {"a/2", "u1"}, {"b", "u1"}, {"b", "u2"}, {"b", "u3"}, {"c/x", "u1"}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if _, ok := q["uploads"]; !ok || r.URL.Path != "/bucket/" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+		keyMarker, uploadIDMarker := q.Get("key-marker"), q.Get("upload-id-marker")
+		maxUploads, _ := strconv.Atoi(q.Get("max-uploads"))
+		if maxUploads == 0 {
+			maxUploads = 1000
+		}
+
+		var (
+			b                     strings.Builder
+			count                 int
+			truncated             bool
+			nextKey, nextUploadID string
+			lastPrefix            string
+		)
+		for _, u := range uploads {
+			if !strings.HasPrefix(u.key, prefix) {
+				continue
+			}
+			if u.key < keyMarker || (u.key == keyMarker && (uploadIDMarker == "" || u.uploadID <= uploadIDMarker)) {
+				continue
+			}
+			commonPrefix := ""
+			if delimiter != "" {
+				if i := strings.Index(u.key[len(prefix):], delimiter); i >= 0 {
+					commonPrefix = u.key[:len(prefix)+i+len(delimiter)]
+				}
+			}
+			if commonPrefix != "" && (commonPrefix == lastPrefix || commonPrefix <= keyMarker) {
+				continue
+			}
+			if count == maxUploads {
+				truncated = true
+				break
+			}
+			count++
+			if commonPrefix != "" {
+				lastPrefix = commonPrefix
+				nextKey, nextUploadID = commonPrefix, ""
+				fmt.Fprintf(&b, "<CommonPrefixes><Prefix>%s</Prefix></CommonPrefixes>", commonPrefix)
+				continue
+			}
+			nextKey, nextUploadID = u.key, u.uploadID
+			fmt.Fprintf(&b, "<Upload><Key>%s</Key><UploadId>%s</UploadId><Initiated>2024-01-01T00:00:00.000Z</Initiated>"+
+				"<StorageClass>STANDARD</StorageClass><Initiator><ID>id</ID><DisplayName>uploader</DisplayName></Initiator></Upload>", u.key, u.uploadID)
+		}
+		fmt.Fprintf(w, "<ListMultipartUploadsResult><Bucket>bucket</Bucket><IsTruncated>%t</IsTruncated>", truncated)
+		if truncated {
+			fmt.Fprintf(w, "<NextKeyMarker>%s</NextKeyMarker><NextUploadIdMarker>%s</NextUploadIdMarker>", nextKey, nextUploadID)
+		}
+		io.WriteString(w, b.String()+"</ListMultipartUploadsResult>")
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		opts     ListMultipartUploadsOptions
+		expected []string
+		pages    int
+	}{
+		{ListMultipartUploadsOptions{MaxUploads: 2}, []string{"a/1 u1", "a/1 u2", "a/2 u1", "b u1", "b u2", "b u3", "c/x u1"}, 4},
+		{ListMultipartUploadsOptions{Delimiter: "/", MaxUploads: 2}, []string{"a/", "b u1", "b u2", "b u3", "c/"}, 3},
+		{ListMultipartUploadsOptions{Prefix: "a/", Delimiter: "/"}, []string{"a/1 u1", "a/1 u2", "a/2 u1"}, 1},
+	}
+	for i, testCase := range testCases {
+		var (
+			got   []string
+			pages int
+		)
+		opts := testCase.opts
+		for {
+			result, err := clnt.ListMultipartUploads(context.Background(), "bucket", opts)
+			if err != nil {
+				t.Fatalf("Test %d: %v", i+1, err)
+			}
+			pages++
+			for _, prefix := range result.CommonPrefixes {
+				got = append(got, prefix.Prefix)
+			}
+			for _, upload := range result.Uploads {
+				if upload.StorageClass != "STANDARD" || upload.Initiator.DisplayName != "uploader" || upload.Initiated.IsZero() {
+					t.Fatalf("Test %d: unexpected upload %+v", i+1, upload)
+				}
+				got = append(got, upload.Key+" "+upload.UploadID)
+			}
+			if !result.IsTruncated {
+				break
+			}
+			opts.KeyMarker, opts.UploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, testCase.expected) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+		if pages != testCase.pages {
+			t.Fatalf("Test %d: expected %d pages, got %d", i+1, testCase.pages, pages)
+		}
+	}
+
+	invalid := []ListMultipartUploadsOptions{
+		{MaxUploads: -1},
+		{MaxUploads: 1001},
+		{UploadIDMarker: "u1"},
+	}
+	for i, opts := range invalid {
+		if _, err = clnt.ListMultipartUploads(context.Background(), "bucket", opts); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Fatalf("Test %d: expected InvalidArgument, got %v", i+1, err)
+		}
+	}
+}
+
+func TestListObjectsDelimiter(t *testing.T) {
+	keys := []string{"a/b/c.txt", "a/d.txt", "a/e/f/g.txt", "a/h", "i.txt"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix, delimiter := r.URL.Query().Get("prefix"), r.URL.Query().Get("delimiter")
+		var b strings.Builder
+		b.WriteString(`<ListBucketResult><Name>bucket</Name><IsTruncated>false</IsTruncated>`)
+		prefixes := make(map[string]bool)
+		var commonPrefixes []string
+		for _, key := range keys {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			if i := strings.Index(key[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+				p := key[:len(prefix)+i+len(delimiter)]
+				if !prefixes[p] {
+					prefixes[p] = true
+					commonPrefixes = append(commonPrefixes, p)
+				}
+				continue
+			}
+			fmt.Fprintf(&b, `<Contents><Key>%s</Key><ETag>"etag"</ETag><Size>1</Size></Contents>`, key)
+		}
+		for _, p := range commonPrefixes {
+			fmt.Fprintf(&b, `<CommonPrefixes><Prefix>%s</Prefix></CommonPrefixes>`, p)
+		}
+		b.WriteString(`</ListBucketResult>`)
+		io.WriteString(w, b.String())
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := func(opts ListObjectsOptions) (entries []string) {
+		for object := range clnt.ListObjects(context.Background(), "bucket", opts) {
+			if object.Err != nil {
+				t.Fatal(object.Err)
+			}
+			if object.IsPrefix {
+				if object.Size != 0 || object.ETag != "" {
+					t.Fatalf("Expected an empty prefix entry, got %+v", object)
+				}
+				entries = append(entries, "prefix:"+object.Key)
+				continue
+			}
+			entries = append(entries, object.Key)
+		}
+		return entries
+	}
+
+	testCases := []struct {
+		opts     ListObjectsOptions
+		expected []string
+	}{
+		{ListObjectsOptions{}, []string{"i.txt", "prefix:a/"}},
+		{ListObjectsOptions{Prefix: "a/"}, []string{"a/d.txt", "a/h", "prefix:a/b/", "prefix:a/e/"}},
+		{ListObjectsOptions{Prefix: "a/e/"}, []string{"prefix:a/e/f/"}},
+		{ListObjectsOptions{Prefix: "a/", Recursive: true}, []string{"a/b/c.txt", "a/d.txt", "a/e/f/g.txt", "a/h"}},
+		{ListObjectsOptions{Prefix: "a/", Delimiter: ".txt"}, []string{"a/h", "prefix:a/b/c.txt", "prefix:a/d.txt", "prefix:a/e/f/g.txt"}},
+	}
+	for i, testCase := range testCases {
+		entries := list(testCase.opts)
+		if !reflect.DeepEqual(entries, testCase.expected) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, entries)
+		}
+	}
+}
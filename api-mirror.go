@@ -0,0 +1,251 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+)
+
+// MirrorOptions represents options specified by user for MirrorDirectory call
+type MirrorOptions struct {
+	// Number of files compared and uploaded concurrently, defaults to 4.
+	Concurrency int
+
+	// RemoveStale removes objects under the prefix which do not
+	// exist in the local directory anymore.
+	RemoveStale bool
+
+	// Options used to upload new and changed files.
+	PutOptions PutObjectOptions
+}
+
+// MirrorSummary lists the object names handled by MirrorDirectory.
+type MirrorSummary struct {
+	Uploaded []string
+	Skipped  []string
+	Deleted  []string
+}
+
+// MirrorDirectory uploads the files of localDir to the bucket under the
+// given prefix, only files which are new or whose size or ETag differ
+// from the stored object are uploaded. Object names are the file paths
+// relative to localDir using '/' as separator, a '/' is appended to a
+// non-empty prefix if missing.
+//
+// Objects uploaded with server side encryption do not have an MD5 based
+// ETag and are always uploaded again.
+func (c *Client) MirrorDirectory(ctx context.Context, localDir, bucketName, prefix string, opts MirrorOptions) (summary MirrorSummary, err error) {
+	// Input validation.
+	if err = s3utils.CheckValidBucketName(bucketName); err != nil {
+		return summary, err
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if err = s3utils.CheckValidObjectNamePrefix(prefix); err != nil {
+		return summary, err
+	}
+	st, err := os.Stat(localDir)
+	if err != nil {
+		return summary, err
+	}
+	if !st.IsDir() {
+		return summary, errInvalidArgument(localDir + " is not a directory")
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Gather the objects currently stored under the prefix.
+	remote := make(map[string]ObjectInfo)
+	for object := range c.ListObjects(ctx, bucketName, ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return summary, object.Err
+		}
+		remote[object.Key] = object
+	}
+
+	type mirrorFile struct {
+		path, objectName string
+		size             int64
+	}
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		local    = make(map[string]bool)
+		wg       sync.WaitGroup
+		filesCh  = make(chan mirrorFile)
+	)
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range filesCh {
+				changed := true
+				if object, ok := remote[file.objectName]; ok && object.Size == file.size {
+					etag, err := c.fileETag(file.path, file.size, opts.PutOptions)
+					if err != nil {
+						setErr(err)
+						continue
+					}
+					changed = etag != trimEtag(object.ETag)
+				}
+				if changed {
+					if _, err := c.FPutObject(ctx, bucketName, file.objectName, file.path, opts.PutOptions); err != nil {
+						setErr(err)
+						continue
+					}
+				}
+				mu.Lock()
+				if changed {
+					summary.Uploaded = append(summary.Uploaded, file.objectName)
+				} else {
+					summary.Skipped = append(summary.Skipped, file.objectName)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	walkErr := filepath.WalkDir(localDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(localDir, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		file := mirrorFile{
+			path:       path,
+			objectName: prefix + filepath.ToSlash(rel),
+			size:       info.Size(),
+		}
+		local[file.objectName] = true
+		select {
+		case filesCh <- file:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return nil
+	})
+	close(filesCh)
+	wg.Wait()
+
+	if firstErr != nil {
+		return summary, firstErr
+	}
+	if walkErr != nil {
+		return summary, walkErr
+	}
+
+	if opts.RemoveStale {
+		var stale []string
+		for objectName := range remote {
+			if !local[objectName] {
+				stale = append(stale, objectName)
+			}
+		}
+		sort.Strings(stale)
+		objectsCh := make(chan ObjectInfo)
+		go func() {
+			defer close(objectsCh)
+			for _, objectName := range stale {
+				select {
+				case objectsCh <- ObjectInfo{Key: objectName}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		for res := range c.RemoveObjectsWithResult(ctx, bucketName, objectsCh, RemoveObjectsOptions{}) {
+			if res.Err != nil {
+				if err == nil {
+					err = res.Err
+				}
+				continue
+			}
+			summary.Deleted = append(summary.Deleted, res.ObjectName)
+		}
+	}
+
+	sort.Strings(summary.Uploaded)
+	sort.Strings(summary.Skipped)
+	sort.Strings(summary.Deleted)
+	return summary, err
+}
+
+// fileETag computes the ETag the file would get when uploaded with
+// FPutObject and the given options, the MD5 of the content for a
+// single PUT or the MD5 of the part MD5s for a multipart upload.
+func (c *Client) fileETag(filePath string, size int64, opts PutObjectOptions) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	partSize := opts.PartSize
+	if partSize == 0 {
+		partSize = minPartSize
+	}
+	if size <= int64(partSize) || opts.DisableMultipart || s3utils.IsGoogleEndpoint(*c.endpointURL) {
+		h := md5.New()
+		if _, err = io.Copy(h, f); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	_, optimalPartSize, _, err := OptimalPartInfo(size, opts.PartSize)
+	if err != nil {
+		return "", err
+	}
+	return readerMultipartETag(f, size, optimalPartSize)
+}
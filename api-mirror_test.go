@@ -0,0 +1,200 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newMirrorTestServer returns a server storing objects of a single
+// bucket in memory, supporting listing, uploads and multi-delete.
+func newMirrorTestServer(t *testing.T, objects map[string][]byte) *httptest.Server {
+	var mu sync.Mutex
+	// TLS avoids streaming signatures, so uploaded bodies are stored as is.
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := strings.TrimPrefix(r.URL.Path, "/bucket")
+		key = strings.TrimPrefix(key, "/")
+		switch {
+		case r.Method == http.MethodGet && key == "":
+			prefix := r.URL.Query().Get("prefix")
+			var keys []string
+			for k := range objects {
+				if strings.HasPrefix(k, prefix) {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			w.Header().Set("Content-Type", "application/xml")
+			io.WriteString(w, "<ListBucketResult><Name>bucket</Name><IsTruncated>false</IsTruncated>")
+			for _, k := range keys {
+				sum := md5.Sum(objects[k])
+				fmt.Fprintf(w, "<Contents><Key>%s</Key><Size>%d</Size><ETag>&quot;%s&quot;</ETag></Contents>", k, len(objects[k]), hex.EncodeToString(sum[:]))
+			}
+			io.WriteString(w, "</ListBucketResult>")
+		case r.Method == http.MethodPut:
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			objects[key] = data
+			sum := md5.Sum(data)
+			w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		case r.Method == http.MethodPost:
+			var del deleteMultiObjects
+			if err := xml.NewDecoder(r.Body).Decode(&del); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			var result deleteMultiObjectsResult
+			for _, obj := range del.Objects {
+				delete(objects, obj.Key)
+				result.DeletedObjects = append(result.DeletedObjects, deletedObject{Key: obj.Key})
+			}
+			xml.NewEncoder(w).Encode(result)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+	return srv
+}
+
+func TestMirrorDirectory(t *testing.T) {
+	objects := map[string][]byte{
+		"backup/stale.txt": []byte("stale"),
+		"other/keep.txt":   []byte("keep"),
+	}
+	srv := newMirrorTestServer(t, objects)
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":          "first file",
+		"sub/b.txt":      "second file",
+		"sub/deep/c.bin": strings.Repeat("c", 1024),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	opts := MirrorOptions{Concurrency: 2, RemoveStale: true}
+	summary, err := clnt.MirrorDirectory(ctx, dir, "bucket", "backup", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MirrorSummary{
+		Uploaded: []string{"backup/a.txt", "backup/sub/b.txt", "backup/sub/deep/c.bin"},
+		Deleted:  []string{"backup/stale.txt"},
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Fatalf("Expected summary %+v, got %+v", want, summary)
+	}
+	for name, content := range files {
+		if string(objects["backup/"+name]) != content {
+			t.Fatalf("Unexpected content for %s", name)
+		}
+	}
+	if _, ok := objects["other/keep.txt"]; !ok {
+		t.Fatal("Objects outside of the prefix must not be removed")
+	}
+
+	// A second run has nothing to upload.
+	summary, err = clnt.MirrorDirectory(ctx, dir, "bucket", "backup/", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = MirrorSummary{
+		Skipped: []string{"backup/a.txt", "backup/sub/b.txt", "backup/sub/deep/c.bin"},
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Fatalf("Expected summary %+v, got %+v", want, summary)
+	}
+
+	// Same size but different content is detected through the ETag.
+	if err = os.WriteFile(filepath.Join(dir, "a.txt"), []byte("FIRST FILE"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	summary, err = clnt.MirrorDirectory(ctx, dir, "bucket", "backup", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(summary.Uploaded, []string{"backup/a.txt"}) || len(summary.Skipped) != 2 {
+		t.Fatalf("Expected only backup/a.txt to be uploaded, got %+v", summary)
+	}
+
+	if _, err = clnt.MirrorDirectory(ctx, filepath.Join(dir, "a.txt"), "bucket", "backup", opts); err == nil {
+		t.Fatal("Expected mirroring a file instead of a directory to fail")
+	}
+}
+
+func TestMirrorDirectoryCanceled(t *testing.T) {
+	srv := newMirrorTestServer(t, map[string][]byte{})
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err = os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = clnt.MirrorDirectory(ctx, dir, "bucket", "", MirrorOptions{}); err == nil {
+		t.Fatal("Expected canceled context to fail")
+	}
+}
@@ -69,10 +69,10 @@ func (r LegalHoldStatus) IsValid() bool {
 
 func newObjectLegalHold(status *LegalHoldStatus) (*objectLegalHold, error) {
 	if status == nil {
-		return nil, fmt.Errorf("Status not set")
+		return nil, errInvalidArgument("Legal hold status not set")
 	}
 	if !status.IsValid() {
-		return nil, fmt.Errorf("invalid legal hold status `%v`", status)
+		return nil, errInvalidArgument(fmt.Sprintf("invalid legal hold status `%v`, must be either %s or %s", *status, LegalHoldEnabled, LegalHoldDisabled))
 	}
 	legalHold := &objectLegalHold{
 		Status: *status,
@@ -81,6 +81,9 @@ func newObjectLegalHold(status *LegalHoldStatus) (*objectLegalHold, error) {
 }
 
 // PutObjectLegalHold : sets object legal hold for a given object and versionID.
+// A version with legal hold ON cannot be deleted until the hold is cleared.
+// ObjectLockConfigurationNotFoundError is returned if the bucket was
+// created without object lock.
 func (c *Client) PutObjectLegalHold(ctx context.Context, bucketName, objectName string, opts PutObjectLegalHoldOptions) error {
 	// Input validation.
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
@@ -128,7 +131,7 @@ func (c *Client) PutObjectLegalHold(ctx context.Context, bucketName, objectName
 	}
 	if resp != nil {
 		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-			return httpRespToErrorResponse(resp, bucketName, objectName)
+			return toObjectLockError(httpRespToErrorResponse(resp, bucketName, objectName))
 		}
 	}
 	return nil
@@ -164,7 +167,7 @@ func (c *Client) GetObjectLegalHold(ctx context.Context, bucketName, objectName
 	}
 	if resp != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, httpRespToErrorResponse(resp, bucketName, objectName)
+			return nil, toObjectLockError(httpRespToErrorResponse(resp, bucketName, objectName))
 		}
 	}
 	lh := &objectLegalHold{}
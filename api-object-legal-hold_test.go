@@ -0,0 +1,116 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestObjectLegalHold(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		legalHold []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["legal-hold"]; !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path == "/unlocked/object" {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `<Error><Code>InvalidRequest</Code><Message>Bucket is missing Object Lock Configuration</Message></Error>`)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			legalHold, _ = io.ReadAll(r.Body)
+		case http.MethodGet:
+			w.Write(legalHold)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, status := range []LegalHoldStatus{LegalHoldEnabled, LegalHoldDisabled} {
+		status := status
+		if err = clnt.PutObjectLegalHold(ctx, "bucket", "object", PutObjectLegalHoldOptions{Status: &status}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := clnt.GetObjectLegalHold(ctx, "bucket", "object", GetObjectLegalHoldOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *got != status {
+			t.Fatalf("Expected legal hold %s, got %s", status, *got)
+		}
+	}
+
+	invalid := LegalHoldStatus("on")
+	err = clnt.PutObjectLegalHold(ctx, "bucket", "object", PutObjectLegalHoldOptions{Status: &invalid})
+	if ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Fatalf("Expected InvalidArgument for status %q, got %v", invalid, err)
+	}
+	err = clnt.PutObjectLegalHold(ctx, "bucket", "object", PutObjectLegalHoldOptions{})
+	if ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Fatalf("Expected InvalidArgument for missing status, got %v", err)
+	}
+
+	on := LegalHoldEnabled
+	err = clnt.PutObjectLegalHold(ctx, "unlocked", "object", PutObjectLegalHoldOptions{Status: &on})
+	if ToErrorResponse(err).Code != "ObjectLockConfigurationNotFoundError" {
+		t.Fatalf("Expected ObjectLockConfigurationNotFoundError, got %v", err)
+	}
+	_, err = clnt.GetObjectLegalHold(ctx, "unlocked", "object", GetObjectLegalHoldOptions{})
+	if ToErrorResponse(err).Code != "ObjectLockConfigurationNotFoundError" {
+		t.Fatalf("Expected ObjectLockConfigurationNotFoundError, got %v", err)
+	}
+}
+
+func TestToObjectLockError(t *testing.T) {
+	testCases := []struct {
+		err  error
+		code string
+	}{
+		{ErrorResponse{Code: "InvalidRequest", Message: "Bucket is missing Object Lock Configuration"}, "ObjectLockConfigurationNotFoundError"},
+		{ErrorResponse{Code: "InvalidRequest", Message: "Bucket is missing ObjectLockConfiguration"}, "ObjectLockConfigurationNotFoundError"},
+		{ErrorResponse{Code: "InvalidBucketState", Message: "Object Lock configuration cannot be enabled on existing buckets"}, "ObjectLockConfigurationNotFoundError"},
+		{ErrorResponse{Code: "InvalidBucketState", Message: "Some other problem"}, "InvalidBucketState"},
+		{ErrorResponse{Code: "InvalidRequest", Message: "Some other problem"}, "InvalidRequest"},
+		{ErrorResponse{Code: "AccessDenied", Message: "Access Denied."}, "AccessDenied"},
+	}
+	for i, testCase := range testCases {
+		if code := ToErrorResponse(toObjectLockError(testCase.err)).Code; code != testCase.code {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.code, code)
+		}
+	}
+}
@@ -106,11 +106,15 @@ func newObjectLockConfig(mode *RetentionMode, validity *uint, unit *ValidityUnit
 
 	if mode != nil && validity != nil && unit != nil {
 		if !mode.IsValid() {
-			return nil, fmt.Errorf("invalid retention mode `%v`", mode)
+			return nil, errInvalidArgument(fmt.Sprintf("invalid retention mode `%v`", *mode))
 		}
 
 		if !unit.isValid() {
-			return nil, fmt.Errorf("invalid validity unit `%v`", unit)
+			return nil, errInvalidArgument(fmt.Sprintf("invalid validity unit `%v`", *unit))
+		}
+
+		if *validity == 0 {
+			return nil, errInvalidArgument("retention validity must be greater than zero")
 		}
 
 		config.Rule = &struct {
@@ -135,10 +139,11 @@ func newObjectLockConfig(mode *RetentionMode, validity *uint, unit *ValidityUnit
 		return config, nil
 	}
 
-	return nil, fmt.Errorf("all of retention mode, validity and validity unit must be passed")
+	return nil, errInvalidArgument("all of retention mode, validity and validity unit must be passed")
 }
 
 // SetBucketObjectLockConfig sets object lock configuration in given bucket. mode, validity and unit are either all set or all nil.
+// ObjectLockConfigurationNotFoundError is returned if the bucket was created without object lock.
 func (c *Client) SetBucketObjectLockConfig(ctx context.Context, bucketName string, mode *RetentionMode, validity *uint, unit *ValidityUnit) error {
 	// Input validation.
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
@@ -177,7 +182,7 @@ func (c *Client) SetBucketObjectLockConfig(ctx context.Context, bucketName strin
 	}
 	if resp != nil {
 		if resp.StatusCode != http.StatusOK {
-			return httpRespToErrorResponse(resp, bucketName, "")
+			return toObjectLockError(httpRespToErrorResponse(resp, bucketName, ""))
 		}
 	}
 	return nil
@@ -205,7 +210,7 @@ func (c *Client) GetObjectLockConfig(ctx context.Context, bucketName string) (ob
 	}
 	if resp != nil {
 		if resp.StatusCode != http.StatusOK {
-			return "", nil, nil, nil, httpRespToErrorResponse(resp, bucketName, "")
+			return "", nil, nil, nil, toObjectLockError(httpRespToErrorResponse(resp, bucketName, ""))
 		}
 	}
 	config := &objectLockConfig{}
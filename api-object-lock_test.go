@@ -0,0 +1,122 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestObjectLockConfig(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		config []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["object-lock"]; !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if strings.Trim(r.URL.Path, "/") == "unlocked" {
+			w.Header().Set("Content-Type", "application/xml")
+			if r.Method == http.MethodPut {
+				w.WriteHeader(http.StatusConflict)
+				io.WriteString(w, `<Error><Code>InvalidBucketState</Code><Message>Object Lock configuration cannot be enabled on existing buckets</Message></Error>`)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `<Error><Code>ObjectLockConfigurationNotFoundError</Code><Message>Object Lock configuration does not exist for this bucket</Message></Error>`)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			config, _ = io.ReadAll(r.Body)
+		case http.MethodGet:
+			w.Write(config)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	mode := Governance
+	validity := uint(30)
+	unit := Days
+	if err = clnt.SetObjectLockConfig(ctx, "bucket", &mode, &validity, &unit); err != nil {
+		t.Fatal(err)
+	}
+	objectLock, gotMode, gotValidity, gotUnit, err := clnt.GetObjectLockConfig(ctx, "bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objectLock != "Enabled" || *gotMode != Governance || *gotValidity != 30 || *gotUnit != Days {
+		t.Fatalf("Unexpected object lock configuration %s %s %d %s", objectLock, *gotMode, *gotValidity, *gotUnit)
+	}
+
+	if err = clnt.SetObjectLockConfig(ctx, "unlocked", &mode, &validity, &unit); ToErrorResponse(err).Code != "ObjectLockConfigurationNotFoundError" {
+		t.Fatalf("Expected ObjectLockConfigurationNotFoundError, got %v", err)
+	}
+	if _, _, _, _, err = clnt.GetObjectLockConfig(ctx, "unlocked"); ToErrorResponse(err).Code != "ObjectLockConfigurationNotFoundError" {
+		t.Fatalf("Expected ObjectLockConfigurationNotFoundError, got %v", err)
+	}
+}
+
+func TestNewObjectLockConfig(t *testing.T) {
+	governance := Governance
+	invalidMode := RetentionMode("LOCKED")
+	days := Days
+	years := Years
+	invalidUnit := ValidityUnit("WEEKS")
+	one := uint(1)
+	zero := uint(0)
+
+	testCases := []struct {
+		mode     *RetentionMode
+		validity *uint
+		unit     *ValidityUnit
+		wantErr  bool
+	}{
+		{&governance, &one, &days, false},
+		{&governance, &one, &years, false},
+		{nil, nil, nil, false},
+		{&invalidMode, &one, &days, true},
+		{&governance, &one, &invalidUnit, true},
+		{&governance, &zero, &days, true},
+		{&governance, nil, &days, true},
+		{nil, &one, nil, true},
+	}
+	for i, testCase := range testCases {
+		_, err := newObjectLockConfig(testCase.mode, testCase.validity, testCase.unit)
+		if (err != nil) != testCase.wantErr {
+			t.Errorf("Test %d: want error %v, got %v", i+1, testCase.wantErr, err)
+		}
+	}
+}
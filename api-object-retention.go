@@ -38,18 +38,27 @@ type objectRetention struct {
 	RetainUntilDate *time.Time    `type:"timestamp" timestampFormat:"iso8601" xml:"RetainUntilDate,omitempty"`
 }
 
+// newObjectRetention validates and returns the retention to be applied,
+// mode and date must either be both set or both empty; an empty
+// retention removes an existing governance retention.
 func newObjectRetention(mode *RetentionMode, date *time.Time) (*objectRetention, error) {
 	objectRetention := &objectRetention{}
 
 	if date != nil && !date.IsZero() {
+		if !date.After(time.Now()) {
+			return nil, errInvalidArgument(fmt.Sprintf("Retain until date `%s` must be in the future", date.Format(time.RFC3339)))
+		}
 		objectRetention.RetainUntilDate = date
 	}
-	if mode != nil {
+	if mode != nil && *mode != "" {
 		if !mode.IsValid() {
-			return nil, fmt.Errorf("invalid retention mode `%v`", mode)
+			return nil, errInvalidArgument(fmt.Sprintf("invalid retention mode `%v`, must be either %s or %s", *mode, Governance, Compliance))
 		}
 		objectRetention.Mode = *mode
 	}
+	if (objectRetention.Mode == "") != (objectRetention.RetainUntilDate == nil) {
+		return nil, errInvalidArgument("Retention mode and retain until date must be specified together")
+	}
 
 	return objectRetention, nil
 }
@@ -0,0 +1,129 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestObjectRetention(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		retention []byte
+		bypass    string
+		versionID string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["retention"]; !ok || r.URL.Path != "/bucket/object" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		versionID = r.URL.Query().Get("versionId")
+		switch r.Method {
+		case http.MethodPut:
+			retention, _ = io.ReadAll(r.Body)
+			bypass = r.Header.Get(amzBypassGovernance)
+		case http.MethodGet:
+			w.Write(retention)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	mode := Governance
+	until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	err = clnt.PutObjectRetention(ctx, "bucket", "object", PutObjectRetentionOptions{
+		Mode:            &mode,
+		RetainUntilDate: &until,
+		VersionID:       "v1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bypass != "" || versionID != "v1" {
+		t.Fatalf("Unexpected bypass header %q or version %q", bypass, versionID)
+	}
+
+	gotMode, gotUntil, err := clnt.GetObjectRetention(ctx, "bucket", "object", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *gotMode != Governance || gotUntil == nil || !gotUntil.Equal(until) {
+		t.Fatalf("Unexpected retention %v %v", *gotMode, gotUntil)
+	}
+
+	// Shortening a governance retention requires the bypass header.
+	until = until.Add(-time.Minute)
+	err = clnt.PutObjectRetention(ctx, "bucket", "object", PutObjectRetentionOptions{
+		Mode:             &mode,
+		RetainUntilDate:  &until,
+		GovernanceBypass: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bypass != "true" {
+		t.Fatalf("Expected governance bypass header to be set, got %q", bypass)
+	}
+}
+
+func TestNewObjectRetention(t *testing.T) {
+	governance := Governance
+	compliance := Compliance
+	invalid := RetentionMode("LOCKED")
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	testCases := []struct {
+		mode    *RetentionMode
+		date    *time.Time
+		wantErr bool
+	}{
+		{&governance, &future, false},
+		{&compliance, &future, false},
+		{nil, nil, false},
+		{&invalid, &future, true},
+		{&governance, &past, true},
+		{&governance, nil, true},
+		{nil, &future, true},
+	}
+	for i, testCase := range testCases {
+		_, err := newObjectRetention(testCase.mode, testCase.date)
+		if (err != nil) != testCase.wantErr {
+			t.Errorf("Test %d: want error %v, got %v", i+1, testCase.wantErr, err)
+		}
+		if err != nil && ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Errorf("Test %d: expected InvalidArgument, got %v", i+1, err)
+		}
+	}
+}
@@ -41,12 +41,23 @@ type AdvancedObjectTaggingOptions struct {
 }
 
 // PutObjectTagging replaces or creates object tag(s) and can target
-// a specific object version in a versioned bucket.
+// a specific object version in a versioned bucket. Tags are validated
+// against the object tag limits before the request is sent.
 func (c *Client) PutObjectTagging(ctx context.Context, bucketName, objectName string, otags *tags.Tags, opts PutObjectTaggingOptions) error {
 	// Input validation.
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
 		return err
 	}
+	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+		return err
+	}
+	if otags == nil {
+		return errInvalidArgument("Object tags cannot be nil.")
+	}
+	// Tags built for a bucket allow more entries than an object accepts.
+	if _, err := tags.MapToObjectTags(otags.ToMap()); err != nil {
+		return errInvalidArgument(err.Error())
+	}
 
 	// Get resources properly escaped and lined up before
 	// using them in http request.
@@ -99,6 +110,14 @@ type GetObjectTaggingOptions struct {
 // GetObjectTagging fetches object tag(s) with options to target
 // a specific object version in a versioned bucket.
 func (c *Client) GetObjectTagging(ctx context.Context, bucketName, objectName string, opts GetObjectTaggingOptions) (*tags.Tags, error) {
+	// Input validation.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		return nil, err
+	}
+	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+		return nil, err
+	}
+
 	// Get resources properly escaped and lined up before
 	// using them in http request.
 	urlValues := make(url.Values)
@@ -142,6 +161,14 @@ type RemoveObjectTaggingOptions struct {
 // RemoveObjectTagging removes object tag(s) with options to control a specific object
 // version in a versioned bucket
 func (c *Client) RemoveObjectTagging(ctx context.Context, bucketName, objectName string, opts RemoveObjectTaggingOptions) error {
+	// Input validation.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		return err
+	}
+	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+		return err
+	}
+
 	// Get resources properly escaped and lined up before
 	// using them in http request.
 	urlValues := make(url.Values)
@@ -0,0 +1,141 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+func TestObjectTagging(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		tagging = make(map[string][]byte)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["tagging"]; !ok || r.URL.Path != "/bucket/object" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		versionID := r.URL.Query().Get("versionId")
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			tagging[versionID], _ = io.ReadAll(r.Body)
+		case http.MethodGet:
+			body, ok := tagging[versionID]
+			if !ok {
+				body = []byte(`<Tagging><TagSet></TagSet></Tagging>`)
+			}
+			w.Write(body)
+		case http.MethodDelete:
+			delete(tagging, versionID)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	tagMap := map[string]string{
+		"project": "minio-go",
+		"owner":   "storage team",
+		"stage":   "test+1",
+	}
+	for _, versionID := range []string{"", "e4b1b2d2-8f3c-4b4e-9bb6-0a2f6c3d3b7a"} {
+		otags, err := tags.MapToObjectTags(tagMap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = clnt.PutObjectTagging(ctx, "bucket", "object", otags, PutObjectTaggingOptions{VersionID: versionID}); err != nil {
+			t.Fatalf("version %q: %v", versionID, err)
+		}
+		got, err := clnt.GetObjectTagging(ctx, "bucket", "object", GetObjectTaggingOptions{VersionID: versionID})
+		if err != nil {
+			t.Fatalf("version %q: %v", versionID, err)
+		}
+		if !reflect.DeepEqual(got.ToMap(), tagMap) {
+			t.Fatalf("version %q: expected tags %v, got %v", versionID, tagMap, got.ToMap())
+		}
+		if err = clnt.RemoveObjectTagging(ctx, "bucket", "object", RemoveObjectTaggingOptions{VersionID: versionID}); err != nil {
+			t.Fatalf("version %q: %v", versionID, err)
+		}
+		got, err = clnt.GetObjectTagging(ctx, "bucket", "object", GetObjectTaggingOptions{VersionID: versionID})
+		if err != nil {
+			t.Fatalf("version %q: %v", versionID, err)
+		}
+		if got.Count() != 0 {
+			t.Fatalf("version %q: expected no tags after removal, got %v", versionID, got.ToMap())
+		}
+	}
+}
+
+func TestPutObjectTaggingValidation(t *testing.T) {
+	clnt, err := New("localhost:9000", &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bucketTags := make(map[string]string)
+	for _, key := range strings.Split("a b c d e f g h i j k", " ") {
+		bucketTags[key] = "value"
+	}
+	tooMany, err := tags.MapToBucketTags(bucketTags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		objectName string
+		otags      *tags.Tags
+	}{
+		{"", &tags.Tags{}},
+		{"object", nil},
+		{"object", tooMany},
+	}
+	for i, testCase := range testCases {
+		err = clnt.PutObjectTagging(context.Background(), "bucket", testCase.objectName, testCase.otags, PutObjectTaggingOptions{})
+		if err == nil {
+			t.Fatalf("Test %d: expected an error", i+1)
+		}
+	}
+
+	if _, err = tags.MapToObjectTags(map[string]string{strings.Repeat("k", 129): "value"}); err == nil {
+		t.Fatal("Expected a tag key longer than 128 characters to be rejected")
+	}
+	if _, err = tags.MapToObjectTags(map[string]string{"key": "value#1"}); err == nil {
+		t.Fatal("Expected a tag value with invalid characters to be rejected")
+	}
+}
@@ -19,27 +19,53 @@ package minio
 
 import (
 	"context"
+	"encoding/xml"
 	"errors"
+	"fmt"
+	"html/template"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/minio/minio-go/v7/pkg/s3utils"
 	"github.com/minio/minio-go/v7/pkg/signer"
 )
 
+// List of http methods which can be presigned.
+var supportedPresignMethods = map[string]struct{}{
+	http.MethodGet:    {},
+	http.MethodHead:   {},
+	http.MethodPut:    {},
+	http.MethodPost:   {},
+	http.MethodDelete: {},
+}
+
 // presignURL - Returns a presigned URL for an input 'method'.
 // Expires maximum is 7days - ie. 604800 and minimum is 1.
-func (c *Client) presignURL(ctx context.Context, method, bucketName, objectName string, expires time.Duration, reqParams url.Values, extraHeaders http.Header) (u *url.URL, err error) {
+func (c *Client) presignURL(ctx context.Context, method, bucketName, objectName string, expires time.Duration, reqParams url.Values, extraHeaders http.Header, signTime time.Time) (u *url.URL, err error) {
 	// Input validation.
 	if method == "" {
 		return nil, errInvalidArgument("method cannot be empty.")
 	}
+	if _, ok := supportedPresignMethods[method]; !ok {
+		return nil, errInvalidArgument("Unsupported method " + method + " for presigned URL.")
+	}
 	if err = s3utils.CheckValidBucketName(bucketName); err != nil {
 		return nil, err
 	}
-	if err = isValidExpiry(expires); err != nil {
-		return nil, err
+	// The limits of the signature version are verified once the
+	// credentials are known, expires is sent in whole seconds.
+	if expires < time.Second {
+		return nil, isValidExpiry(expires, credentials.SignatureDefault)
+	}
+	if !signTime.IsZero() {
+		if err = isValidPresignTime(signTime); err != nil {
+			return nil, err
+		}
 	}
 
 	// Convert expires into seconds.
@@ -51,6 +77,7 @@ func (c *Client) presignURL(ctx context.Context, method, bucketName, objectName
 		expires:            expireSeconds,
 		queryValues:        reqParams,
 		extraPresignHeader: extraHeaders,
+		presignTime:        signTime,
 	}
 
 	// Instantiate a new request.
@@ -70,7 +97,17 @@ func (c *Client) PresignedGetObject(ctx context.Context, bucketName, objectName
 	if err = s3utils.CheckValidObjectName(objectName); err != nil {
 		return nil, err
 	}
-	return c.presignURL(ctx, http.MethodGet, bucketName, objectName, expires, reqParams, nil)
+	return c.presignURL(ctx, http.MethodGet, bucketName, objectName, expires, reqParams, nil, time.Time{})
+}
+
+// PresignedGetObjectWithTime - Same as PresignedGetObject but the URL
+// is signed with the provided signTime instead of the current time, see
+// PresignWithTime. The URL is valid from signTime until signTime+expires.
+func (c *Client) PresignedGetObjectWithTime(ctx context.Context, bucketName, objectName string, expires time.Duration, reqParams url.Values, signTime time.Time) (u *url.URL, err error) {
+	if err = s3utils.CheckValidObjectName(objectName); err != nil {
+		return nil, err
+	}
+	return c.PresignWithTime(ctx, http.MethodGet, bucketName, objectName, expires, reqParams, signTime)
 }
 
 // PresignedHeadObject - Returns a presigned URL to access
@@ -81,7 +118,7 @@ func (c *Client) PresignedHeadObject(ctx context.Context, bucketName, objectName
 	if err = s3utils.CheckValidObjectName(objectName); err != nil {
 		return nil, err
 	}
-	return c.presignURL(ctx, http.MethodHead, bucketName, objectName, expires, reqParams, nil)
+	return c.presignURL(ctx, http.MethodHead, bucketName, objectName, expires, reqParams, nil, time.Time{})
 }
 
 // PresignedPutObject - Returns a presigned URL to upload an object
@@ -91,7 +128,83 @@ func (c *Client) PresignedPutObject(ctx context.Context, bucketName, objectName
 	if err = s3utils.CheckValidObjectName(objectName); err != nil {
 		return nil, err
 	}
-	return c.presignURL(ctx, http.MethodPut, bucketName, objectName, expires, nil, nil)
+	return c.presignURL(ctx, http.MethodPut, bucketName, objectName, expires, nil, nil, time.Time{})
+}
+
+// PresignedUploadPart - Returns a presigned URL to upload the part
+// partNumber of the multipart upload uploadID without credentials, the
+// uploadId and partNumber query parameters are covered by the
+// signature. Together with presigned URLs to create and complete the
+// upload this lets browsers upload large objects in parts. URL can
+// have a maximum expiry of upto 7days or a minimum of 1sec.
+func (c *Client) PresignedUploadPart(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, expires time.Duration) (u *url.URL, err error) {
+	if err = s3utils.CheckValidObjectName(objectName); err != nil {
+		return nil, err
+	}
+	if uploadID == "" {
+		return nil, errInvalidArgument("Upload ID cannot be empty.")
+	}
+	if partNumber < 1 || partNumber > maxPartsCount {
+		return nil, errInvalidArgument(fmt.Sprintf("Part number %d is not between 1 and %d.", partNumber, maxPartsCount))
+	}
+	reqParams := make(url.Values)
+	reqParams.Set("uploadId", uploadID)
+	reqParams.Set("partNumber", strconv.Itoa(partNumber))
+	return c.presignURL(ctx, http.MethodPut, bucketName, objectName, expires, reqParams, nil, time.Time{})
+}
+
+// PresignedCreateMultipartUpload - Returns a presigned URL to create a
+// multipart upload with a POST request without credentials. The
+// response is an InitiateMultipartUploadResult XML document with the
+// UploadId the parts and the completion are presigned for, see
+// PresignedUploadPart and PresignedCompleteMultipartUpload. URL can
+// have a maximum expiry of upto 7days or a minimum of 1sec.
+func (c *Client) PresignedCreateMultipartUpload(ctx context.Context, bucketName, objectName string, expires time.Duration) (u *url.URL, err error) {
+	if err = s3utils.CheckValidObjectName(objectName); err != nil {
+		return nil, err
+	}
+	reqParams := make(url.Values)
+	reqParams.Set("uploads", "")
+	return c.presignURL(ctx, http.MethodPost, bucketName, objectName, expires, reqParams, nil, time.Time{})
+}
+
+// PresignedCompleteMultipartUpload - Returns a presigned URL to complete
+// the multipart upload uploadID with a POST request without credentials.
+// The body of the request, which is not covered by the signature, lists
+// the uploaded parts in ascending part number order with the ETags
+// returned by the part uploads:
+//
+//	<CompleteMultipartUpload xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+//	  <Part><PartNumber>1</PartNumber><ETag>"etag-1"</ETag></Part>
+//	  <Part><PartNumber>2</PartNumber><ETag>"etag-2"</ETag></Part>
+//	</CompleteMultipartUpload>
+//
+// CompleteMultipartUploadBody builds it. The server may answer 200 OK
+// with an Error XML document when the completion fails. URL can have a
+// maximum expiry of upto 7days or a minimum of 1sec.
+func (c *Client) PresignedCompleteMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string, expires time.Duration) (u *url.URL, err error) {
+	if err = s3utils.CheckValidObjectName(objectName); err != nil {
+		return nil, err
+	}
+	if uploadID == "" {
+		return nil, errInvalidArgument("Upload ID cannot be empty.")
+	}
+	reqParams := make(url.Values)
+	reqParams.Set("uploadId", uploadID)
+	return c.presignURL(ctx, http.MethodPost, bucketName, objectName, expires, reqParams, nil, time.Time{})
+}
+
+// CompleteMultipartUploadBody returns the XML body of a request to a
+// URL returned by PresignedCompleteMultipartUpload, with the parts
+// sorted by part number.
+func CompleteMultipartUploadBody(parts []CompletePart) ([]byte, error) {
+	if len(parts) == 0 {
+		return nil, errInvalidArgument("Parts cannot be empty.")
+	}
+	complete := completeMultipartUpload{Parts: make([]CompletePart, len(parts))}
+	copy(complete.Parts, parts)
+	sort.Sort(completedParts(complete.Parts))
+	return xml.Marshal(complete)
 }
 
 // PresignHeader - similar to Presign() but allows including HTTP headers that
@@ -102,14 +215,26 @@ func (c *Client) PresignedPutObject(ctx context.Context, bucketName, objectName
 // FIXME: The extra header parameter should be included in Presign() in the next
 // major version bump, and this function should then be deprecated.
 func (c *Client) PresignHeader(ctx context.Context, method, bucketName, objectName string, expires time.Duration, reqParams url.Values, extraHeaders http.Header) (u *url.URL, err error) {
-	return c.presignURL(ctx, method, bucketName, objectName, expires, reqParams, extraHeaders)
+	return c.presignURL(ctx, method, bucketName, objectName, expires, reqParams, extraHeaders, time.Time{})
 }
 
 // Presign - returns a presigned URL for any http method of your choice along
-// with custom request params and extra signed headers. URL can have a maximum
-// expiry of upto 7days or a minimum of 1sec.
+// with custom request params. Supported methods are GET, HEAD, PUT, POST and
+// DELETE, this allows presigning object stat and delete operations as well.
+// URL can have a maximum expiry of upto 7days or a minimum of 1sec.
 func (c *Client) Presign(ctx context.Context, method, bucketName, objectName string, expires time.Duration, reqParams url.Values) (u *url.URL, err error) {
-	return c.presignURL(ctx, method, bucketName, objectName, expires, reqParams, nil)
+	return c.presignURL(ctx, method, bucketName, objectName, expires, reqParams, nil, time.Time{})
+}
+
+// PresignWithTime - same as Presign but the URL is signed with the
+// provided signTime instead of the current time, for any supported
+// method. This produces reproducible URLs for a fixed signTime and
+// allows signing for a server whose clock is skewed.
+func (c *Client) PresignWithTime(ctx context.Context, method, bucketName, objectName string, expires time.Duration, reqParams url.Values, signTime time.Time) (u *url.URL, err error) {
+	if signTime.IsZero() {
+		return nil, errInvalidArgument("Signing time cannot be empty.")
+	}
+	return c.presignURL(ctx, method, bucketName, objectName, expires, reqParams, nil, signTime)
 }
 
 // PresignedPostPolicy - Returns POST urlString, form data to upload an object.
@@ -132,7 +257,7 @@ func (c *Client) PresignedPostPolicy(ctx context.Context, p *PostPolicy) (u *url
 		return nil, nil, err
 	}
 
-	isVirtualHost := c.isVirtualHostStyleRequest(*c.endpointURL, bucketName)
+	isVirtualHost := c.isVirtualHostStyleRequest(*c.activeEndpointURL(), bucketName)
 
 	u, err = c.makeTargetURL(bucketName, "", location, isVirtualHost, nil)
 	if err != nil {
@@ -157,13 +282,13 @@ func (c *Client) PresignedPostPolicy(ctx context.Context, p *PostPolicy) (u *url
 	}
 
 	// Keep time.
-	t := time.Now().UTC()
+	t := c.now().UTC()
 	// For signature version '2' handle here.
 	if signerType.IsV2() {
 		policyBase64 := p.base64()
 		p.formData["policy"] = policyBase64
 		// For Google endpoint set this value to be 'GoogleAccessId'.
-		if s3utils.IsGoogleEndpoint(*c.endpointURL) {
+		if s3utils.IsGoogleEndpoint(*c.activeEndpointURL()) {
 			p.formData["GoogleAccessId"] = accessKeyID
 		} else {
 			// For all other endpoints set this value to be 'AWSAccessKeyId'.
@@ -226,3 +351,43 @@ func (c *Client) PresignedPostPolicy(ctx context.Context, p *PostPolicy) (u *url
 	p.formData["x-amz-signature"] = signer.PostPresignSignatureV4(policyBase64, t, secretAccessKey, location)
 	return u, p.formData, nil
 }
+
+// postPolicyFormTemplate renders the fields of a presigned POST policy as
+// hidden inputs, the file input comes last as required by S3.
+var postPolicyFormTemplate = template.Must(template.New("form").Parse(`<form action="{{.Action}}" method="post" enctype="multipart/form-data">
+{{- range .Fields}}
+  <input type="hidden" name="{{.Name}}" value="{{.Value}}">
+{{- end}}
+  <input type="file" name="file">
+  <input type="submit" value="Upload">
+</form>
+`))
+
+// PresignedPostPolicyHTMLForm - renders the URL and form data returned by
+// PresignedPostPolicy as an HTML form uploading a file from a browser,
+// which is convenient to test direct uploads. The form data fields are
+// sent as hidden inputs in sorted order.
+func PresignedPostPolicyHTMLForm(u *url.URL, formData map[string]string) (string, error) {
+	if u == nil {
+		return "", errInvalidArgument("URL cannot be empty.")
+	}
+	type field struct {
+		Name, Value string
+	}
+	fields := make([]field, 0, len(formData))
+	for name, value := range formData {
+		fields = append(fields, field{Name: name, Value: value})
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+
+	var form strings.Builder
+	if err := postPolicyFormTemplate.Execute(&form, struct {
+		Action string
+		Fields []field
+	}{u.String(), fields}); err != nil {
+		return "", err
+	}
+	return form.String(), nil
+}
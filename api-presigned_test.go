@@ -0,0 +1,574 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/signer"
+	"golang.org/x/net/html"
+)
+
+func TestPresignHeadAndDelete(t *testing.T) {
+	var gotMethod string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		if r.URL.Path != "/bucket/object" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		q := r.URL.Query()
+		for _, k := range []string{"X-Amz-Algorithm", "X-Amz-Credential", "X-Amz-Date", "X-Amz-Expires", "X-Amz-SignedHeaders", "X-Amz-Signature"} {
+			if q.Get(k) == "" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+		}
+		switch r.Method {
+		case http.MethodHead:
+			w.WriteHeader(http.StatusOK)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Creds:  credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		method     string
+		statusCode int
+	}{
+		{http.MethodHead, http.StatusOK},
+		{http.MethodDelete, http.StatusNoContent},
+	}
+	for _, testCase := range testCases {
+		u, err := clnt.Presign(context.Background(), testCase.method, "bucket", "object", time.Hour, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", testCase.method, err)
+		}
+		req, err := http.NewRequest(testCase.method, u.String(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if gotMethod != testCase.method {
+			t.Fatalf("Expected method %s, got %s", testCase.method, gotMethod)
+		}
+		if resp.StatusCode != testCase.statusCode {
+			t.Fatalf("%s: expected status %d, got %d", testCase.method, testCase.statusCode, resp.StatusCode)
+		}
+	}
+}
+
+func TestPresignUnsupportedMethod(t *testing.T) {
+	clnt, err := New("localhost:9000", &Options{
+		Creds:  credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range []string{"", "PATCH", "OPTIONS", "get"} {
+		if _, err = clnt.Presign(context.Background(), method, "bucket", "object", time.Hour, nil); err == nil {
+			t.Fatalf("Expected presign with method %q to fail", method)
+		}
+		if ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Fatalf("Expected InvalidArgument, got %v", err)
+		}
+	}
+}
+
+func TestPresignedGetObjectWithTime(t *testing.T) {
+	clnt, err := New("localhost:9000", &Options{
+		Creds:  credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signTime := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+	u1, err := clnt.PresignedGetObjectWithTime(context.Background(), "bucket", "object", time.Hour, nil, signTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u2, err := clnt.PresignedGetObjectWithTime(context.Background(), "bucket", "object", time.Hour, nil, signTime.In(time.FixedZone("UTC+2", 2*60*60)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u1.String() != u2.String() {
+		t.Fatalf("Expected identical URLs, got %s and %s", u1, u2)
+	}
+	if date := u1.Query().Get("X-Amz-Date"); date != "20240102T030405Z" {
+		t.Fatalf("Expected X-Amz-Date 20240102T030405Z, got %s", date)
+	}
+	if sig := u1.Query().Get("X-Amz-Signature"); sig != "c6a9305fcd47655c92632eb3eee42957501be7b5cdfa2a8a57e1d9a227a7397d" {
+		t.Fatalf("Expected stable signature, got %s", sig)
+	}
+
+	if _, err = clnt.PresignedGetObjectWithTime(context.Background(), "bucket", "object", time.Hour, nil, time.Time{}); err == nil {
+		t.Fatal("Expected presign with empty signing time to fail")
+	}
+	if _, err = clnt.PresignedGetObjectWithTime(context.Background(), "bucket", "object", time.Hour, nil, time.Now().Add(30*24*time.Hour)); err == nil {
+		t.Fatal("Expected presign with signing time far in the future to fail")
+	}
+}
+
+func TestPresignedPostPolicyHTMLForm(t *testing.T) {
+	clnt, err := New("localhost:9000", &Options{
+		Creds:  credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := NewPostPolicy()
+	policy.SetBucket("bucket")
+	policy.SetKey("uploads/<file>")
+	policy.SetExpires(time.Now().UTC().Add(time.Hour))
+	policy.SetContentType("image/png")
+	u, formData, err := clnt.PresignedPostPolicy(context.Background(), policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form, err := PresignedPostPolicyHTMLForm(u, formData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := html.Parse(strings.NewReader(form))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		action string
+		inputs []map[string]string
+		walk   func(n *html.Node)
+	)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			attrs := make(map[string]string)
+			for _, attr := range n.Attr {
+				attrs[attr.Key] = attr.Val
+			}
+			switch n.Data {
+			case "form":
+				action = attrs["action"]
+			case "input":
+				inputs = append(inputs, attrs)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	if action != u.String() {
+		t.Fatalf("Expected form action %s, got %s", u, action)
+	}
+	hidden := make(map[string]string)
+	fileInput := -1
+	for i, input := range inputs {
+		switch input["type"] {
+		case "hidden":
+			hidden[input["name"]] = input["value"]
+		case "file":
+			fileInput = i
+		}
+	}
+	for name, value := range formData {
+		if got, ok := hidden[name]; !ok || got != value {
+			t.Fatalf("Expected hidden input %s=%q, got %q", name, value, got)
+		}
+	}
+	if len(hidden) != len(formData) {
+		t.Fatalf("Expected %d hidden inputs, got %d", len(formData), len(hidden))
+	}
+	// S3 ignores the fields which follow the file.
+	if fileInput != len(formData) {
+		t.Fatalf("Expected the file input after the %d hidden inputs, got position %d", len(formData), fileInput)
+	}
+}
+
+func TestPresignExpiry(t *testing.T) {
+	testCases := []struct {
+		creds   *credentials.Credentials
+		expires time.Duration
+		err     string
+	}{
+		{credentials.NewStaticV4("accessKey", "secretKey", ""), 0, "Expires must be at least 1s, got 0s."},
+		{credentials.NewStaticV4("accessKey", "secretKey", ""), -time.Minute, "Expires must be at least 1s, got -1m0s."},
+		{credentials.NewStaticV4("accessKey", "secretKey", ""), 500 * time.Millisecond, "Expires must be at least 1s, got 500ms."},
+		{credentials.NewStaticV4("accessKey", "secretKey", ""), 8 * 24 * time.Hour, "Expires must be between 1s and 168h for signature v4, got 192h0m0s."},
+		{credentials.NewStaticV4("accessKey", "secretKey", ""), 7 * 24 * time.Hour, ""},
+		{credentials.NewStaticV2("accessKey", "secretKey", ""), 0, "Expires must be at least 1s, got 0s."},
+		{credentials.NewStaticV2("accessKey", "secretKey", ""), 30 * 24 * time.Hour, ""},
+	}
+	for i, testCase := range testCases {
+		clnt, err := New("localhost:9000", &Options{
+			Creds:  testCase.creds,
+			Region: "us-east-1",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := clnt.PresignedGetObject(context.Background(), "bucket", "object", testCase.expires, nil)
+		if testCase.err == "" {
+			if err != nil {
+				t.Fatalf("Test %d: unexpected error %v", i+1, err)
+			}
+			if u.Query().Get("X-Amz-Signature") == "" && u.Query().Get("Signature") == "" {
+				t.Fatalf("Test %d: expected a signed URL, got %s", i+1, u)
+			}
+			continue
+		}
+		if err == nil || err.Error() != testCase.err {
+			t.Fatalf("Test %d: expected error %q, got %v", i+1, testCase.err, err)
+		}
+		if ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Fatalf("Test %d: expected InvalidArgument, got %v", i+1, err)
+		}
+	}
+}
+
+func TestPresignWithTime(t *testing.T) {
+	clnt, err := New("localhost:9000", &Options{
+		Creds:  credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signTime := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete} {
+		u1, err := clnt.PresignWithTime(context.Background(), method, "bucket", "object", time.Hour, nil, signTime)
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		u2, err := clnt.PresignWithTime(context.Background(), method, "bucket", "object", time.Hour, nil, signTime)
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		if u1.String() != u2.String() {
+			t.Fatalf("%s: expected identical URLs, got %s and %s", method, u1, u2)
+		}
+		if date := u1.Query().Get("X-Amz-Date"); date != "20240102T030405Z" {
+			t.Fatalf("%s: expected X-Amz-Date 20240102T030405Z, got %s", method, date)
+		}
+	}
+
+	// The method is part of the signature, a GET presigned with the same
+	// time matches PresignedGetObjectWithTime.
+	u1, err := clnt.PresignWithTime(context.Background(), http.MethodGet, "bucket", "object", time.Hour, nil, signTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u2, err := clnt.PresignWithTime(context.Background(), http.MethodPut, "bucket", "object", time.Hour, nil, signTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig := u1.Query().Get("X-Amz-Signature"); sig != "c6a9305fcd47655c92632eb3eee42957501be7b5cdfa2a8a57e1d9a227a7397d" {
+		t.Fatalf("Expected stable signature, got %s", sig)
+	}
+	if u1.Query().Get("X-Amz-Signature") == u2.Query().Get("X-Amz-Signature") {
+		t.Fatal("Expected different signatures for GET and PUT")
+	}
+	u3, err := clnt.PresignedGetObjectWithTime(context.Background(), "bucket", "object", time.Hour, nil, signTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u1.String() != u3.String() {
+		t.Fatalf("Expected the URL of PresignedGetObjectWithTime, got %s and %s", u1, u3)
+	}
+
+	if _, err = clnt.PresignWithTime(context.Background(), http.MethodPut, "bucket", "object", time.Hour, nil, time.Time{}); err == nil {
+		t.Fatal("Expected presign with empty signing time to fail")
+	}
+}
+
+// verifyPresignedV4 checks the presigned signature of r made with the
+// accessKey and secretKey credentials in us-east-1.
+func verifyPresignedV4(r *http.Request) bool {
+	q := r.URL.Query()
+	signature := q.Get("X-Amz-Signature")
+	date, err := time.Parse(iso8601DateFormat, q.Get("X-Amz-Date"))
+	if err != nil {
+		return false
+	}
+	expires, err := strconv.ParseInt(q.Get("X-Amz-Expires"), 10, 64)
+	if err != nil {
+		return false
+	}
+	q.Del("X-Amz-Signature")
+	u := url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path, RawQuery: q.Encode()}
+	req, err := http.NewRequest(r.Method, u.String(), nil)
+	if err != nil {
+		return false
+	}
+	signed := signer.PreSignV4WithTime(*req, "accessKey", "secretKey", "", "us-east-1", expires, date)
+	return signed.URL.Query().Get("X-Amz-Signature") == signature
+}
+
+func TestPresignedUploadPart(t *testing.T) {
+	parts := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.Method != http.MethodPut || r.URL.Path != "/bucket/object" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if !verifyPresignedV4(r) {
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `<Error><Code>SignatureDoesNotMatch</Code></Error>`)
+			return
+		}
+		if q.Get("uploadId") != "upload-id" {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `<Error><Code>NoSuchUpload</Code></Error>`)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		parts[q.Get("partNumber")] = string(data)
+		w.Header().Set("ETag", `"etag-`+q.Get("partNumber")+`"`)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Creds:  credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	put := func(u *url.URL, body string) *http.Response {
+		req, err := http.NewRequest(http.MethodPut, u.String(), strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	for partNumber, body := range map[int]string{1: "first", 2: "second"} {
+		u, err := clnt.PresignedUploadPart(context.Background(), "bucket", "object", "upload-id", partNumber, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q := u.Query(); q.Get("uploadId") != "upload-id" || q.Get("partNumber") != strconv.Itoa(partNumber) {
+			t.Fatalf("Expected the upload id and part number in %s", u)
+		}
+		resp := put(u, body)
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") != `"etag-`+strconv.Itoa(partNumber)+`"` {
+			t.Fatalf("Part %d: unexpected response %s %s", partNumber, resp.Status, resp.Header.Get("ETag"))
+		}
+	}
+	if parts["1"] != "first" || parts["2"] != "second" {
+		t.Fatalf("Unexpected parts %v", parts)
+	}
+
+	// The part number and upload id cannot be changed.
+	u, err := clnt.PresignedUploadPart(context.Background(), "bucket", "object", "upload-id", 1, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range map[string]string{"partNumber": "3", "uploadId": "other"} {
+		tampered := *u
+		q := tampered.Query()
+		q.Set(k, v)
+		tampered.RawQuery = q.Encode()
+		if resp := put(&tampered, "tampered"); resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("Expected a changed %s to be rejected, got %s", k, resp.Status)
+		}
+	}
+
+	testCases := []struct {
+		uploadID   string
+		partNumber int
+		expires    time.Duration
+	}{
+		{"", 1, time.Hour},
+		{"upload-id", 0, time.Hour},
+		{"upload-id", 10001, time.Hour},
+		{"upload-id", 1, 0},
+		{"upload-id", 1, 8 * 24 * time.Hour},
+	}
+	for i, testCase := range testCases {
+		_, err = clnt.PresignedUploadPart(context.Background(), "bucket", "object", testCase.uploadID, testCase.partNumber, testCase.expires)
+		if ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Fatalf("Test %d: expected InvalidArgument, got %v", i+1, err)
+		}
+	}
+}
+
+func TestPresignedMultipartUpload(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		parts    = make(map[int]string)
+		uploaded []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path != "/bucket/object" || !verifyPresignedV4(r) {
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `<Error><Code>SignatureDoesNotMatch</Code></Error>`)
+			return
+		}
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>upload-id</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == http.MethodPut && q.Get("uploadId") == "upload-id":
+			partNumber, _ := strconv.Atoi(q.Get("partNumber"))
+			data, _ := io.ReadAll(r.Body)
+			parts[partNumber] = string(data)
+			w.Header().Set("ETag", `"etag-`+q.Get("partNumber")+`"`)
+		case r.Method == http.MethodPost && q.Get("uploadId") == "upload-id":
+			var complete completeMultipartUpload
+			if err := xml.NewDecoder(r.Body).Decode(&complete); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, `<Error><Code>MalformedXML</Code></Error>`)
+				return
+			}
+			for i, part := range complete.Parts {
+				if part.PartNumber != i+1 || part.ETag != fmt.Sprintf(`"etag-%d"`, i+1) {
+					w.WriteHeader(http.StatusBadRequest)
+					io.WriteString(w, `<Error><Code>InvalidPartOrder</Code></Error>`)
+					return
+				}
+				uploaded = append(uploaded, parts[part.PartNumber]...)
+			}
+			io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>"etag-3"</ETag></CompleteMultipartUploadResult>`)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Creds:  credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Everything below runs without credentials, as a browser would.
+	do := func(method string, u *url.URL, body string) *http.Response {
+		req, err := http.NewRequest(method, u.String(), strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s %s: unexpected status %s", method, u.Path, resp.Status)
+		}
+		return resp
+	}
+
+	u, err := clnt.PresignedCreateMultipartUpload(context.Background(), "bucket", "object", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := do(http.MethodPost, u, "")
+	var initiate initiateMultipartUploadResult
+	err = xml.NewDecoder(resp.Body).Decode(&initiate)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var completeParts []CompletePart
+	for i, data := range []string{"first ", "second ", "third"} {
+		u, err = clnt.PresignedUploadPart(context.Background(), "bucket", "object", initiate.UploadID, i+1, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp = do(http.MethodPut, u, data)
+		resp.Body.Close()
+		completeParts = append(completeParts, CompletePart{PartNumber: i + 1, ETag: resp.Header.Get("ETag")})
+	}
+
+	// Parts are sorted whatever the order they are given in.
+	completeParts[0], completeParts[2] = completeParts[2], completeParts[0]
+	body, err := CompleteMultipartUploadBody(completeParts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if completeParts[0].PartNumber != 3 {
+		t.Fatal("Expected the parts of the caller to be left unchanged")
+	}
+	u, err = clnt.PresignedCompleteMultipartUpload(context.Background(), "bucket", "object", initiate.UploadID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = do(http.MethodPost, u, string(body))
+	var result completeMultipartUploadResult
+	err = xml.NewDecoder(resp.Body).Decode(&result)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ETag != `"etag-3"` || string(uploaded) != "first second third" {
+		t.Fatalf("Unexpected completion %+v of %q", result, uploaded)
+	}
+
+	if _, err = clnt.PresignedCompleteMultipartUpload(context.Background(), "bucket", "object", "", time.Hour); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Fatalf("Expected InvalidArgument for an empty upload id, got %v", err)
+	}
+	if _, err = CompleteMultipartUploadBody(nil); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Fatalf("Expected InvalidArgument without parts, got %v", err)
+	}
+}
@@ -107,7 +107,10 @@ func (c *Client) doMakeBucket(ctx context.Context, bucketName, location string,
 type MakeBucketOptions struct {
 	// Bucket location
 	Region string
-	// Enable object locking
+	// Enable object locking, sets the x-amz-bucket-object-lock-enabled
+	// header. Object locking can only be enabled when the bucket is
+	// created, the server error is returned as is if the backend does
+	// not support it.
 	ObjectLocking bool
 }
 
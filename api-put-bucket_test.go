@@ -0,0 +1,91 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMakeBucketObjectLocking(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		locked = make(map[string]bool)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bucket := strings.Trim(r.URL.Path, "/")
+		lockHeader := r.Header.Get("x-amz-bucket-object-lock-enabled")
+		mu.Lock()
+		defer mu.Unlock()
+		switch _, objectLock := r.URL.Query()["object-lock"]; {
+		case r.Method == http.MethodPut && !objectLock:
+			if bucket == "legacy" && lockHeader != "" {
+				w.WriteHeader(http.StatusNotImplemented)
+				io.WriteString(w, `<Error><Code>NotImplemented</Code><Message>A header you provided implies functionality that is not implemented</Message></Error>`)
+				return
+			}
+			locked[bucket] = lockHeader == "true"
+		case r.Method == http.MethodPut && objectLock:
+			if !locked[bucket] {
+				w.WriteHeader(http.StatusConflict)
+				io.WriteString(w, `<Error><Code>InvalidBucketState</Code><Message>Object Lock configuration cannot be enabled on existing buckets</Message></Error>`)
+			}
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	mode := Compliance
+	validity := uint(1)
+	unit := Days
+	if err = clnt.MakeBucket(ctx, "worm", MakeBucketOptions{Region: "us-east-1", ObjectLocking: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !locked["worm"] {
+		t.Fatal("Expected bucket to be created with object locking")
+	}
+	if err = clnt.SetObjectLockConfig(ctx, "worm", &mode, &validity, &unit); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = clnt.MakeBucket(ctx, "plain", MakeBucketOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err = clnt.SetObjectLockConfig(ctx, "plain", &mode, &validity, &unit); ToErrorResponse(err).Code != "ObjectLockConfigurationNotFoundError" {
+		t.Fatalf("Expected ObjectLockConfigurationNotFoundError, got %v", err)
+	}
+
+	err = clnt.MakeBucket(ctx, "legacy", MakeBucketOptions{ObjectLocking: true})
+	if errResp := ToErrorResponse(err); errResp.Code != "NotImplemented" || errResp.StatusCode != http.StatusNotImplemented {
+		t.Fatalf("Expected NotImplemented, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+)
+
+// Canned ACLs which can be set on an object.
+var objectCannedACLs = map[string]bool{
+	"private":                   true,
+	"public-read":               true,
+	"public-read-write":         true,
+	"authenticated-read":        true,
+	"aws-exec-read":             true,
+	"bucket-owner-read":         true,
+	"bucket-owner-full-control": true,
+}
+
+// PutObjectACLOptions holds the ACL set by PutObjectACL, either a canned
+// ACL or explicit grants. Grantees are written as id="canonical-user-id",
+// uri="group-uri" or emailAddress="address".
+type PutObjectACLOptions struct {
+	CannedACL string
+
+	GrantRead        []string
+	GrantReadACP     []string
+	GrantWriteACP    []string
+	GrantFullControl []string
+
+	// Set the ACL of a specific version of the object.
+	VersionID string
+}
+
+// header returns the ACL headers of the options.
+func (opts PutObjectACLOptions) header() (http.Header, error) {
+	grants := map[string][]string{
+		"X-Amz-Grant-Read":         opts.GrantRead,
+		"X-Amz-Grant-Read-Acp":     opts.GrantReadACP,
+		"X-Amz-Grant-Write-Acp":    opts.GrantWriteACP,
+		"X-Amz-Grant-Full-Control": opts.GrantFullControl,
+	}
+	header := make(http.Header)
+	for k, grantees := range grants {
+		if len(grantees) > 0 {
+			header.Set(k, strings.Join(grantees, ", "))
+		}
+	}
+	if opts.CannedACL != "" {
+		if len(header) > 0 {
+			return nil, errInvalidArgument("Canned ACL cannot be set with explicit grants.")
+		}
+		if !objectCannedACLs[opts.CannedACL] {
+			return nil, errInvalidArgument("Unsupported canned ACL " + opts.CannedACL + ".")
+		}
+		header.Set("X-Amz-Acl", opts.CannedACL)
+	}
+	if len(header) == 0 {
+		return nil, errInvalidArgument("Either a canned ACL or grants must be set.")
+	}
+	return header, nil
+}
+
+// PutObjectACL - Replaces the ACL of an object with a canned ACL or
+// explicit grants. Backends without object ACLs, such as MinIO for any
+// ACL but private, return an error matching ErrNotImplemented.
+func (c *Client) PutObjectACL(ctx context.Context, bucketName, objectName string, opts PutObjectACLOptions) error {
+	// Input validation.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		return err
+	}
+	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+		return err
+	}
+	header, err := opts.header()
+	if err != nil {
+		return err
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("acl", "")
+	if opts.VersionID != "" {
+		urlValues.Set("versionId", opts.VersionID)
+	}
+
+	// Execute PUT on object to set its ACL.
+	resp, err := c.executeMethod(ctx, http.MethodPut, requestMetadata{
+		bucketName:       bucketName,
+		objectName:       objectName,
+		queryValues:      urlValues,
+		customHeader:     header,
+		contentSHA256Hex: emptySHA256Hex,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp, bucketName, objectName)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+func TestObjectACL(t *testing.T) {
+	const allUsers = "http://acs.amazonaws.com/groups/global/AllUsers"
+	cannedACL := "private"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bucket/object" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if _, ok := r.URL.Query()["acl"]; !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			cannedACL = r.Header.Get("X-Amz-Acl")
+			w.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			policy := `<AccessControlPolicy><Owner><ID>owner-id</ID><DisplayName>owner</DisplayName></Owner><AccessControlList>` +
+				`<Grant><Grantee><ID>owner-id</ID><DisplayName>owner</DisplayName></Grantee><Permission>FULL_CONTROL</Permission></Grant>`
+			if cannedACL == "public-read" {
+				policy += `<Grant><Grantee><URI>` + allUsers + `</URI></Grantee><Permission>READ</Permission></Grant>`
+			}
+			policy += `</AccessControlList></AccessControlPolicy>`
+			w.Write([]byte(policy))
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Creds:  credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := clnt.GetObjectACLPolicy(context.Background(), "bucket", "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acl := policy.CannedACL(); acl != "private" {
+		t.Fatalf("Expected private ACL, got %q", acl)
+	}
+
+	if err = clnt.PutObjectACL(context.Background(), "bucket", "object", PutObjectACLOptions{CannedACL: "public-read"}); err != nil {
+		t.Fatal(err)
+	}
+	policy, err = clnt.GetObjectACLPolicy(context.Background(), "bucket", "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acl := policy.CannedACL(); acl != "public-read" {
+		t.Fatalf("Expected public-read ACL, got %q", acl)
+	}
+	if policy.Owner.ID != "owner-id" || policy.Owner.DisplayName != "owner" {
+		t.Fatalf("Unexpected owner %+v", policy.Owner)
+	}
+	grants := policy.AccessControlList.Grant
+	if len(grants) != 2 || grants[1].Grantee.URI != allUsers || grants[1].Permission != "READ" {
+		t.Fatalf("Expected a READ grant for all users, got %+v", grants)
+	}
+}
+
+func TestPutObjectACLOptions(t *testing.T) {
+	testCases := []struct {
+		opts    PutObjectACLOptions
+		headers map[string]string
+		err     string
+	}{
+		{PutObjectACLOptions{}, nil, "Either a canned ACL or grants must be set."},
+		{PutObjectACLOptions{CannedACL: "public"}, nil, "Unsupported canned ACL public."},
+		{PutObjectACLOptions{CannedACL: "private", GrantRead: []string{`id="user"`}}, nil, "Canned ACL cannot be set with explicit grants."},
+		{PutObjectACLOptions{CannedACL: "bucket-owner-full-control"}, map[string]string{"X-Amz-Acl": "bucket-owner-full-control"}, ""},
+		{
+			PutObjectACLOptions{
+				GrantRead:        []string{`uri="http://acs.amazonaws.com/groups/global/AllUsers"`, `id="user"`},
+				GrantFullControl: []string{`id="owner"`},
+			},
+			map[string]string{
+				"X-Amz-Grant-Read":         `uri="http://acs.amazonaws.com/groups/global/AllUsers", id="user"`,
+				"X-Amz-Grant-Full-Control": `id="owner"`,
+			},
+			"",
+		},
+	}
+	for i, testCase := range testCases {
+		header, err := testCase.opts.header()
+		if testCase.err != "" {
+			if err == nil || err.Error() != testCase.err {
+				t.Fatalf("Test %d: expected error %q, got %v", i+1, testCase.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if len(header) != len(testCase.headers) {
+			t.Fatalf("Test %d: expected headers %v, got %v", i+1, testCase.headers, header)
+		}
+		for k, v := range testCase.headers {
+			if got := header.Get(k); got != v {
+				t.Fatalf("Test %d: expected %s: %s, got %s", i+1, k, v, got)
+			}
+		}
+	}
+}
+
+func TestObjectACLNotImplemented(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte(`<Error><Code>NotImplemented</Code><Message>A header you provided implies functionality that is not implemented</Message></Error>`))
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Creds:      credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region:     "us-east-1",
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = clnt.PutObjectACL(context.Background(), "bucket", "object", PutObjectACLOptions{CannedACL: "public-read"})
+	if !errors.Is(err, ErrNotImplemented) {
+		t.Fatalf("Expected ErrNotImplemented, got %v", err)
+	}
+	if _, err = clnt.GetObjectACLPolicy(context.Background(), "bucket", "object"); !errors.Is(err, ErrNotImplemented) {
+		t.Fatalf("Expected ErrNotImplemented, got %v", err)
+	}
+}
@@ -19,7 +19,7 @@ package minio
 
 import (
 	"context"
-	"mime"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -44,6 +44,35 @@ func (c *Client) FPutObject(ctx context.Context, bucketName, objectName, filePat
 	}
 	defer fileReader.Close()
 
+	return c.putObjectFromFile(ctx, bucketName, objectName, fileReader, opts)
+}
+
+// FPutObjectFromFile - Create an object in a bucket, with contents from an
+// already opened file. The whole file is uploaded, its size is read with
+// Stat and its offset is moved, the file is not closed. Allows request
+// cancellation.
+func (c *Client) FPutObjectFromFile(ctx context.Context, bucketName, objectName string, f *os.File, opts PutObjectOptions) (info UploadInfo, err error) {
+	// Input validation.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		return UploadInfo{}, err
+	}
+	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+		return UploadInfo{}, err
+	}
+	if f == nil {
+		return UploadInfo{}, errInvalidArgument("File cannot be nil.")
+	}
+
+	// Upload from the beginning of the file regardless of earlier reads.
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return UploadInfo{}, err
+	}
+	return c.putObjectFromFile(ctx, bucketName, objectName, f, opts)
+}
+
+// putObjectFromFile uploads the open file, the file is handed to PutObject
+// as is so multipart uploads can read the parts in parallel with ReadAt.
+func (c *Client) putObjectFromFile(ctx context.Context, bucketName, objectName string, fileReader *os.File, opts PutObjectOptions) (info UploadInfo, err error) {
 	// Save the file stat.
 	fileStat, err := fileReader.Stat()
 	if err != nil {
@@ -54,9 +83,10 @@ func (c *Client) FPutObject(ctx context.Context, bucketName, objectName, filePat
 	fileSize := fileStat.Size()
 
 	// Set contentType based on filepath extension if not given or default
-	// value of "application/octet-stream" if the extension has no associated type.
+	// value of "application/octet-stream" if the extension has no associated
+	// type, unless PutObject is asked to detect it from the content.
 	if opts.ContentType == "" {
-		if opts.ContentType = mime.TypeByExtension(filepath.Ext(filePath)); opts.ContentType == "" {
+		if opts.ContentType = contentTypeByExtension(filepath.Base(fileReader.Name())); opts.ContentType == "" && !opts.DetectContentType {
 			opts.ContentType = "application/octet-stream"
 		}
 	}
@@ -0,0 +1,277 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFPutObjectFromFile(t *testing.T) {
+	var (
+		gotBody        []byte
+		gotContentType string
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/bucket/object" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		gotBody, _ = io.ReadAll(r.Body)
+		gotContentType = r.Header.Get("Content-Type")
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := bytes.Repeat([]byte("minio-go"), 1024)
+	filePath := filepath.Join(t.TempDir(), "data.txt")
+	if err = os.WriteFile(filePath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// A partial read before the upload must not affect the uploaded content.
+	if _, err = f.Read(make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := clnt.FPutObjectFromFile(context.Background(), "bucket", "object", f, PutObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != int64(len(data)) {
+		t.Fatalf("Expected size %d, got %d", len(data), info.Size)
+	}
+	if !bytes.Equal(gotBody, data) {
+		t.Fatalf("Expected uploaded body to match the file content, got %d bytes", len(gotBody))
+	}
+	if gotContentType != "text/plain; charset=utf-8" {
+		t.Fatalf("Expected content type from the file extension, got %q", gotContentType)
+	}
+
+	// The file must remain open and usable by the caller.
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("Expected file to remain open, got %v", err)
+	}
+	if _, err = f.Read(make([]byte, 10)); err != nil {
+		t.Fatalf("Expected file to remain readable, got %v", err)
+	}
+
+	if _, err = clnt.FPutObjectFromFile(context.Background(), "bucket", "object", nil, PutObjectOptions{}); err == nil {
+		t.Fatal("Expected upload from a nil file to fail")
+	}
+}
+
+func TestFPutObjectResumable(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		parts      map[int][]byte
+		uploadID   string
+		uploads    int
+		putParts   []int
+		failFrom   int
+		aborted    bool
+		completed  []byte
+		partsCount int
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		q := r.URL.Query()
+		if r.URL.Path != "/bucket/object" && !(r.URL.Path == "/bucket/" && q.Has("uploads")) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && q.Has("uploads"):
+			io.WriteString(w, `<ListMultipartUploadsResult><Bucket>bucket</Bucket><IsTruncated>false</IsTruncated>`)
+			if uploadID != "" {
+				io.WriteString(w, `<Upload><Key>object</Key><UploadId>`+uploadID+`</UploadId></Upload>`)
+			}
+			io.WriteString(w, `</ListMultipartUploadsResult>`)
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			uploads++
+			uploadID, parts = fmt.Sprintf("upload-%d", uploads), make(map[int][]byte)
+			io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>`+uploadID+`</UploadId></InitiateMultipartUploadResult>`)
+		case uploadID == "" || q.Get("uploadId") != uploadID:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `<Error><Code>NoSuchUpload</Code><Message>The specified multipart upload does not exist.</Message></Error>`)
+		case r.Method == http.MethodPut:
+			partNumber, _ := strconv.Atoi(q.Get("partNumber"))
+			if failFrom > 0 && partNumber >= failFrom {
+				w.WriteHeader(http.StatusForbidden)
+				io.WriteString(w, `<Error><Code>AccessDenied</Code><Message>Connection lost.</Message></Error>`)
+				return
+			}
+			b, _ := io.ReadAll(r.Body)
+			parts[partNumber] = b
+			putParts = append(putParts, partNumber)
+			sum := md5.Sum(b)
+			w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		case r.Method == http.MethodGet:
+			io.WriteString(w, `<ListPartsResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>`+uploadID+`</UploadId><IsTruncated>false</IsTruncated>`)
+			for partNumber := 1; partNumber <= len(parts); partNumber++ {
+				sum := md5.Sum(parts[partNumber])
+				fmt.Fprintf(w, `<Part><PartNumber>%d</PartNumber><ETag>"%s"</ETag><Size>%d</Size></Part>`, partNumber, hex.EncodeToString(sum[:]), len(parts[partNumber]))
+			}
+			io.WriteString(w, `</ListPartsResult>`)
+		case r.Method == http.MethodPost:
+			var complete completeMultipartUpload
+			if err := xml.NewDecoder(r.Body).Decode(&complete); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			completed = nil
+			for _, part := range complete.Parts {
+				sum := md5.Sum(parts[part.PartNumber])
+				if strings.Trim(part.ETag, `"`) != hex.EncodeToString(sum[:]) {
+					w.WriteHeader(http.StatusBadRequest)
+					io.WriteString(w, `<Error><Code>InvalidPart</Code><Message>ETag mismatch.</Message></Error>`)
+					return
+				}
+				completed = append(completed, parts[part.PartNumber]...)
+			}
+			partsCount, uploadID = len(complete.Parts), ""
+			io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>"final-4"</ETag></CompleteMultipartUploadResult>`)
+		case r.Method == http.MethodDelete:
+			aborted, uploadID = true, ""
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := make([]byte, 3*absMinPartSize+1024)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	filePath := filepath.Join(t.TempDir(), "data.bin")
+	if err = os.WriteFile(filePath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	opts := PutObjectOptions{
+		PartSize:   absMinPartSize,
+		NumThreads: 1,
+		Resumable:  true,
+	}
+
+	// Interrupt the upload after two parts.
+	failFrom = 3
+	if _, err = clnt.FPutObject(context.Background(), "bucket", "object", filePath, opts); err == nil {
+		t.Fatal("Expected the interrupted upload to fail")
+	}
+	if aborted || uploadID == "" {
+		t.Fatal("Expected the incomplete upload to be kept for resuming")
+	}
+	if len(putParts) != 2 {
+		t.Fatalf("Expected 2 uploaded parts, got %v", putParts)
+	}
+
+	// Corrupt the second part, it must not be trusted on resume.
+	parts[2][0] ^= 0xff
+	failFrom, putParts = 0, nil
+	info, err := clnt.FPutObject(context.Background(), "bucket", "object", filePath, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uploads != 1 {
+		t.Fatalf("Expected the upload to be resumed, got %d uploads", uploads)
+	}
+	if fmt.Sprint(putParts) != "[2 3 4]" {
+		t.Fatalf("Expected parts [2 3 4] to be uploaded on resume, got %v", putParts)
+	}
+	if partsCount != 4 || !bytes.Equal(completed, data) {
+		t.Fatalf("Expected the completed object to match the file, got %d parts", partsCount)
+	}
+	if info.Size != int64(len(data)) {
+		t.Fatalf("Expected size %d, got %d", len(data), info.Size)
+	}
+
+	// An upload interrupted with other settings is not resumed, the
+	// completed object would carry them.
+	failFrom, putParts = 3, nil
+	if _, err = clnt.FPutObject(context.Background(), "bucket", "object", filePath, opts); err == nil {
+		t.Fatal("Expected the interrupted upload to fail")
+	}
+	failFrom, putParts = 0, nil
+	opts.ContentType = "text/plain"
+	if _, err = clnt.FPutObject(context.Background(), "bucket", "object", filePath, opts); err != nil {
+		t.Fatal(err)
+	}
+	if !aborted || uploads != 3 {
+		t.Fatalf("Expected the old upload to be aborted and a new one started, got %d uploads", uploads)
+	}
+	if fmt.Sprint(putParts) != "[1 2 3 4]" {
+		t.Fatalf("Expected all parts to be uploaded, got %v", putParts)
+	}
+
+	// Incomplete uploads of other clients are never resumed.
+	failFrom, putParts = 3, nil
+	if _, err = clnt.FPutObject(context.Background(), "bucket", "object", filePath, opts); err == nil {
+		t.Fatal("Expected the interrupted upload to fail")
+	}
+	other, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failFrom, putParts = 0, nil
+	if _, err = other.FPutObject(context.Background(), "bucket", "object", filePath, opts); err != nil {
+		t.Fatal(err)
+	}
+	if uploads != 5 || fmt.Sprint(putParts) != "[1 2 3 4]" {
+		t.Fatalf("Expected a new upload of all parts, got %d uploads and parts %v", uploads, putParts)
+	}
+}
@@ -106,7 +106,11 @@ func (c *Client) putObjectMultipartNoStream(ctx context.Context, bucketName, obj
 	partsInfo := make(map[int]ObjectPart)
 
 	// Create a buffer.
-	buf := make([]byte, partSize)
+	buf, err := c.uploadBuffers.get(ctx, partSize)
+	if err != nil {
+		return UploadInfo{}, err
+	}
+	defer c.uploadBuffers.put(buf)
 
 	// Create checksums
 	// CRC32C is ~50% faster on AMD64 @ 30GB/s
@@ -152,7 +156,7 @@ func (c *Client) putObjectMultipartNoStream(ctx context.Context, bucketName, obj
 			customHeader.Set(opts.AutoChecksum.Key(), base64.StdEncoding.EncodeToString(cSum))
 		}
 
-		p := uploadPartParams{bucketName: bucketName, objectName: objectName, uploadID: uploadID, reader: rd, partNumber: partNumber, md5Base64: md5Base64, sha256Hex: sha256Hex, size: int64(length), sse: opts.ServerSideEncryption, streamSha256: !opts.DisableContentSha256, customHeader: customHeader}
+		p := uploadPartParams{bucketName: bucketName, objectName: objectName, uploadID: uploadID, reader: rd, partNumber: partNumber, md5Base64: md5Base64, sha256Hex: sha256Hex, size: int64(length), sse: opts.ServerSideEncryption, streamSha256: !opts.DisableContentSha256, customHeader: customHeader, requestPayer: opts.RequestPayer}
 		// Proceed to upload the part.
 		objPart, uerr := c.uploadPart(ctx, p)
 		if uerr != nil {
@@ -278,6 +282,7 @@ type uploadPartParams struct {
 	streamSha256 bool
 	customHeader http.Header
 	trailer      http.Header
+	requestPayer bool
 }
 
 // uploadPart - Uploads a part in a multipart upload.
@@ -298,6 +303,9 @@ func (c *Client) uploadPart(ctx context.Context, p uploadPartParams) (ObjectPart
 	if p.partNumber <= 0 {
 		return ObjectPart{}, errInvalidArgument("Part number cannot be negative or equal to zero.")
 	}
+	if p.partNumber > maxPartsCount {
+		return ObjectPart{}, errInvalidArgument(fmt.Sprintf("Part number cannot be greater than %d.", maxPartsCount))
+	}
 	if p.uploadID == "" {
 		return ObjectPart{}, errInvalidArgument("UploadID cannot be empty.")
 	}
@@ -320,6 +328,9 @@ func (c *Client) uploadPart(ctx context.Context, p uploadPartParams) (ObjectPart
 	if p.sse != nil && p.sse.Type() == encrypt.SSEC {
 		p.sse.Marshal(p.customHeader)
 	}
+	if p.requestPayer {
+		p.customHeader.Set(amzRequestPayer, requester)
+	}
 
 	reqMetadata := requestMetadata{
 		bucketName:       p.bucketName,
@@ -437,6 +448,12 @@ func (c *Client) completeMultipartUpload(ctx context.Context, bucketName, object
 			// xml parsing failure due to presence an ill-formed xml fragment
 			return UploadInfo{}, err
 		}
+		// The error was sent with a 200 OK status, report the status
+		// of the error code instead.
+		completeMultipartUploadErr.StatusCode = ToErrorResponse(completeMultipartUploadErr).StatusCode
+		if completeMultipartUploadErr.RequestID == "" {
+			completeMultipartUploadErr.RequestID = resp.Header.Get("x-amz-request-id")
+		}
 		return UploadInfo{}, completeMultipartUploadErr
 	}
 
@@ -451,6 +468,7 @@ func (c *Client) completeMultipartUpload(ctx context.Context, bucketName, object
 		Location:         completeMultipartUploadResult.Location,
 		Expiration:       expTime,
 		ExpirationRuleID: ruleID,
+		RequestCharged:   resp.Header.Get(amzRequestCharged) == requester,
 
 		ChecksumSHA256:    completeMultipartUploadResult.ChecksumSHA256,
 		ChecksumSHA1:      completeMultipartUploadResult.ChecksumSHA1,
@@ -20,8 +20,12 @@ package minio
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"net/http"
 	"net/url"
@@ -115,21 +119,41 @@ func (c *Client) putObjectMultipartStreamFromReadAt(ctx context.Context, bucketN
 	if withChecksum {
 		addAutoChecksumHeaders(&opts)
 	}
-	// Initiate a new multipart upload.
-	uploadID, err := c.newUploadID(ctx, bucketName, objectName, opts)
-	if err != nil {
-		return UploadInfo{}, err
+	// Resume the incomplete upload of the object if requested.
+	var (
+		uploadID      string
+		uploadedParts map[int]ObjectPart
+		fingerprint   string
+	)
+	if opts.Resumable {
+		fingerprint = resumeFingerprint(opts)
+		uploadID, uploadedParts, err = c.findResumableUpload(ctx, bucketName, objectName, fingerprint)
+		if err != nil {
+			return UploadInfo{}, err
+		}
+	}
+	if uploadID == "" {
+		// Initiate a new multipart upload.
+		uploadID, err = c.newUploadID(ctx, bucketName, objectName, opts)
+		if err != nil {
+			return UploadInfo{}, err
+		}
 	}
 	delete(opts.UserMetadata, "X-Amz-Checksum-Algorithm")
 
 	// Aborts the multipart upload in progress, if the
-	// function returns any error, since we do not resume
-	// we should purge the parts which have been uploaded
+	// function returns any error, unless resumable we
+	// should purge the parts which have been uploaded
 	// to relinquish storage space.
 	defer func() {
-		if err != nil {
-			c.abortMultipartUpload(ctx, bucketName, objectName, uploadID)
+		if err == nil {
+			return
 		}
+		if opts.Resumable {
+			c.resumableUploads.put(bucketName, objectName, resumableUpload{uploadID: uploadID, fingerprint: fingerprint})
+			return
+		}
+		c.abortMultipartUpload(ctx, bucketName, objectName, uploadID)
 	}()
 
 	// Total data read and written to server. should be equal to 'size' at the end of the call.
@@ -190,6 +214,28 @@ func (c *Client) putObjectMultipartStreamFromReadAt(ctx context.Context, bucketN
 					partSize = lastPartSize
 				}
 
+				// Skip parts of a resumed upload which match the local data.
+				if part, ok := uploadedParts[uploadReq.PartNum]; ok {
+					verified, err := verifyUploadedPart(reader, readOffset, partSize, part, opts, withChecksum)
+					if err != nil {
+						uploadedPartsCh <- uploadedPartRes{
+							Error: err,
+						}
+						return
+					}
+					if verified {
+						if opts.Progress != nil {
+							io.CopyN(io.Discard, opts.Progress, partSize)
+						}
+						uploadedPartsCh <- uploadedPartRes{
+							Size:    part.Size,
+							PartNum: uploadReq.PartNum,
+							Part:    part,
+						}
+						continue
+					}
+				}
+
 				sectionReader := newHook(io.NewSectionReader(reader, readOffset, partSize), opts.Progress)
 				trailer := make(http.Header, 1)
 				if withChecksum {
@@ -212,6 +258,7 @@ func (c *Client) putObjectMultipartStreamFromReadAt(ctx context.Context, bucketN
 					streamSha256: !opts.DisableContentSha256,
 					sha256Hex:    "",
 					trailer:      trailer,
+					requestPayer: opts.RequestPayer,
 				}
 				objPart, err := c.uploadPart(ctx, p)
 				if err != nil {
@@ -286,6 +333,117 @@ func (c *Client) putObjectMultipartStreamFromReadAt(ctx context.Context, bucketN
 	return uploadInfo, nil
 }
 
+// resumableUploads holds the multipart uploads left incomplete by the
+// resumable uploads of a client, keyed by bucket and object name.
+type resumableUploads struct {
+	sync.Mutex
+	uploads map[string]resumableUpload
+}
+
+// resumableUpload is an incomplete multipart upload and the fingerprint
+// of the options it was started with.
+type resumableUpload struct {
+	uploadID    string
+	fingerprint string
+}
+
+// take - removes and returns the incomplete upload of the object.
+func (r *resumableUploads) take(bucketName, objectName string) (resumableUpload, bool) {
+	r.Lock()
+	defer r.Unlock()
+	key := bucketName + "/" + objectName
+	upload, ok := r.uploads[key]
+	delete(r.uploads, key)
+	return upload, ok
+}
+
+// put - keeps the incomplete upload of the object for resuming.
+func (r *resumableUploads) put(bucketName, objectName string, upload resumableUpload) {
+	r.Lock()
+	defer r.Unlock()
+	r.uploads[bucketName+"/"+objectName] = upload
+}
+
+// resumeFingerprint returns a digest of the headers an upload is started
+// with, which covers encryption, content type, user metadata, tagging,
+// the checksum algorithm and the other object settings.
+func resumeFingerprint(opts PutObjectOptions) string {
+	header := opts.Header()
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s:%q\n", k, header[k])
+	}
+	fmt.Fprintf(h, "checksum:%s\n", opts.AutoChecksum)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// findResumableUpload returns the incomplete multipart upload of the
+// object left by an earlier resumable upload of this client and its
+// uploaded parts, the upload id is empty if there is no upload to
+// resume. The settings of an incomplete upload cannot be read back from
+// the server, so uploads started by others are never resumed and an
+// upload started with other settings is aborted.
+func (c *Client) findResumableUpload(ctx context.Context, bucketName, objectName, fingerprint string) (string, map[int]ObjectPart, error) {
+	upload, ok := c.resumableUploads.take(bucketName, objectName)
+	if !ok {
+		return "", nil, nil
+	}
+	if upload.fingerprint != fingerprint {
+		// The completed object would carry the settings of the old upload.
+		err := c.abortMultipartUpload(ctx, bucketName, objectName, upload.uploadID)
+		if err != nil && ToErrorResponse(err).Code != "NoSuchUpload" {
+			c.resumableUploads.put(bucketName, objectName, upload)
+			return "", nil, err
+		}
+		return "", nil, nil
+	}
+	parts, err := c.listObjectParts(ctx, bucketName, objectName, upload.uploadID)
+	if err != nil {
+		// Completed or aborted meanwhile.
+		if ToErrorResponse(err).Code == "NoSuchUpload" {
+			return "", nil, nil
+		}
+		c.resumableUploads.put(bucketName, objectName, upload)
+		return "", nil, err
+	}
+	return upload.uploadID, parts, nil
+}
+
+// verifyUploadedPart returns true if a part uploaded before has the
+// size, MD5 ETag and checksum of the local part data. The ETag is not
+// an MD5 sum with server side encryption, such parts are only trusted
+// when verified by their checksum.
+func verifyUploadedPart(reader io.ReaderAt, offset, size int64, part ObjectPart, opts PutObjectOptions, withChecksum bool) (bool, error) {
+	if part.Size != size || (opts.ServerSideEncryption != nil && !withChecksum) {
+		return false, nil
+	}
+	md5Hash := md5.New()
+	var crc hash.Hash
+	w := io.Writer(md5Hash)
+	if withChecksum {
+		crc = opts.AutoChecksum.Hasher()
+		w = io.MultiWriter(md5Hash, crc)
+	}
+	if _, err := io.Copy(w, io.NewSectionReader(reader, offset, size)); err != nil {
+		return false, err
+	}
+	if opts.ServerSideEncryption == nil && trimEtag(part.ETag) != hex.EncodeToString(md5Hash.Sum(nil)) {
+		return false, nil
+	}
+	if withChecksum {
+		checksum, err := part.ChecksumRaw(opts.AutoChecksum)
+		if err != nil || !bytes.Equal(checksum, crc.Sum(nil)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 func (c *Client) putObjectMultipartStreamOptionalChecksum(ctx context.Context, bucketName, objectName string,
 	reader io.Reader, size int64, opts PutObjectOptions,
 ) (info UploadInfo, err error) {
@@ -342,7 +500,11 @@ func (c *Client) putObjectMultipartStreamOptionalChecksum(ctx context.Context, b
 	partsInfo := make(map[int]ObjectPart)
 
 	// Create a buffer.
-	buf := make([]byte, partSize)
+	buf, err := c.uploadBuffers.get(ctx, partSize)
+	if err != nil {
+		return UploadInfo{}, err
+	}
+	defer c.uploadBuffers.put(buf)
 
 	// Avoid declaring variables in the for loop
 	var md5Base64 string
@@ -381,7 +543,7 @@ func (c *Client) putObjectMultipartStreamOptionalChecksum(ctx context.Context, b
 		// Update progress reader appropriately to the latest offset
 		// as we read from the source.
 		hooked := newHook(bytes.NewReader(buf[:length]), opts.Progress)
-		p := uploadPartParams{bucketName: bucketName, objectName: objectName, uploadID: uploadID, reader: hooked, partNumber: partNumber, md5Base64: md5Base64, size: partSize, sse: opts.ServerSideEncryption, streamSha256: !opts.DisableContentSha256, customHeader: customHeader}
+		p := uploadPartParams{bucketName: bucketName, objectName: objectName, uploadID: uploadID, reader: hooked, partNumber: partNumber, md5Base64: md5Base64, size: partSize, sse: opts.ServerSideEncryption, streamSha256: !opts.DisableContentSha256, customHeader: customHeader, requestPayer: opts.RequestPayer}
 		objPart, uerr := c.uploadPart(ctx, p)
 		if uerr != nil {
 			return UploadInfo{}, uerr
@@ -442,7 +604,8 @@ func (c *Client) putObjectMultipartStreamOptionalChecksum(ctx context.Context, b
 }
 
 // putObjectMultipartStreamParallel uploads opts.NumThreads parts in parallel.
-// This is expected to take opts.PartSize * opts.NumThreads * (GOGC / 100) bytes of buffer.
+// This is expected to take opts.PartSize * opts.NumThreads * (GOGC / 100) bytes of buffer,
+// less when Options.MaxUploadBufferBytes is lower.
 func (c *Client) putObjectMultipartStreamParallel(ctx context.Context, bucketName, objectName string,
 	reader io.Reader, opts PutObjectOptions,
 ) (info UploadInfo, err error) {
@@ -499,12 +662,11 @@ func (c *Client) putObjectMultipartStreamParallel(ctx context.Context, bucketNam
 	// Initialize parts uploaded map.
 	partsInfo := make(map[int]ObjectPart)
 
-	// Create a buffer.
-	nBuffers := int64(opts.NumThreads)
-	bufs := make(chan []byte, nBuffers)
-	all := make([]byte, nBuffers*partSize)
-	for i := int64(0); i < nBuffers; i++ {
-		bufs <- all[i*partSize : i*partSize+partSize]
+	// Upload at most NumThreads parts at once, their buffers are
+	// taken from the client pool as long as its limit allows.
+	slots := make(chan struct{}, opts.NumThreads)
+	for i := uint(0); i < opts.NumThreads; i++ {
+		slots <- struct{}{}
 	}
 
 	var wg sync.WaitGroup
@@ -517,26 +679,30 @@ func (c *Client) putObjectMultipartStreamParallel(ctx context.Context, bucketNam
 	var partNumber int
 	for partNumber = 1; partNumber <= totalPartsCount; partNumber++ {
 		// Proceed to upload the part.
-		var buf []byte
 		select {
-		case buf = <-bufs:
+		case <-slots:
 		case err = <-errCh:
 			cancel()
 			wg.Wait()
 			return UploadInfo{}, err
 		}
 
-		if int64(len(buf)) != partSize {
-			return UploadInfo{}, fmt.Errorf("read buffer < %d than expected partSize: %d", len(buf), partSize)
+		buf, berr := c.uploadBuffers.get(ctx, partSize)
+		if berr != nil {
+			cancel()
+			wg.Wait()
+			return UploadInfo{}, berr
 		}
 
 		length, rerr := readFull(reader, buf)
 		if rerr == io.EOF && partNumber > 1 {
 			// Done
+			c.uploadBuffers.put(buf)
 			break
 		}
 
 		if rerr != nil && rerr != io.ErrUnexpectedEOF && err != io.EOF {
+			c.uploadBuffers.put(buf)
 			cancel()
 			wg.Wait()
 			return UploadInfo{}, rerr
@@ -565,6 +731,11 @@ func (c *Client) putObjectMultipartStreamParallel(ctx context.Context, bucketNam
 			}
 
 			defer wg.Done()
+			defer func() {
+				// Release the buffer and the slot so they can be reused.
+				c.uploadBuffers.put(buf)
+				slots <- struct{}{}
+			}()
 			p := uploadPartParams{
 				bucketName:   bucketName,
 				objectName:   objectName,
@@ -576,6 +747,7 @@ func (c *Client) putObjectMultipartStreamParallel(ctx context.Context, bucketNam
 				sse:          opts.ServerSideEncryption,
 				streamSha256: !opts.DisableContentSha256,
 				customHeader: customHeader,
+				requestPayer: opts.RequestPayer,
 			}
 			objPart, uerr := c.uploadPart(ctx, p)
 			if uerr != nil {
@@ -587,9 +759,6 @@ func (c *Client) putObjectMultipartStreamParallel(ctx context.Context, bucketNam
 			mu.Lock()
 			partsInfo[partNumber] = objPart
 			mu.Unlock()
-
-			// Send buffer back so it can be reused.
-			bufs <- buf
 		}(partNumber)
 
 		// Save successfully uploaded size.
@@ -665,7 +834,7 @@ func (c *Client) putObject(ctx context.Context, bucketName, objectName string, r
 	if opts.SendContentMd5 && s3utils.IsGoogleEndpoint(*c.endpointURL) && size < 0 {
 		return UploadInfo{}, errInvalidArgument("MD5Sum cannot be calculated with size '-1'")
 	}
-	if opts.Checksum.IsSet() {
+	if opts.Checksum.IsSet() || opts.DisableContentMD5 {
 		opts.SendContentMd5 = false
 	}
 
@@ -684,8 +853,13 @@ func (c *Client) putObject(ctx context.Context, bucketName, objectName string, r
 		}
 	}
 
+	// Seekable bodies are read once more but not buffered, they send
+	// Content-MD5 by default unless a trailing checksum protects them.
+	sendContentMd5 := opts.SendContentMd5 ||
+		(readSeeker != nil && !opts.DisableContentMD5 && !opts.Checksum.IsSet() && !c.addsAutoChecksum(opts))
+
 	var md5Base64 string
-	if opts.SendContentMd5 {
+	if sendContentMd5 {
 		// Calculate md5sum.
 		hash := c.md5Hasher()
 
@@ -724,6 +898,22 @@ func (c *Client) putObject(ctx context.Context, bucketName, objectName string, r
 	return c.putObjectDo(ctx, bucketName, objectName, progressReader, md5Base64, "", size, opts)
 }
 
+// addsAutoChecksum returns true if a single PUT without Content-MD5 gets
+// the AutoChecksum as trailer: the client supports trailing headers, the
+// endpoint is not Google, chunks are not signed with SHA256 and the user
+// has not added checksums already.
+func (c *Client) addsAutoChecksum(opts PutObjectOptions) bool {
+	if !c.trailingHeaderSupport || s3utils.IsGoogleEndpoint(*c.endpointURL) || !(opts.DisableContentSha256 || c.secure) {
+		return false
+	}
+	for k := range opts.UserMetadata {
+		if strings.HasPrefix(strings.ToLower(k), "x-amz-checksum-") {
+			return false
+		}
+	}
+	return true
+}
+
 // putObjectDo - executes the put object http operation.
 // NOTE: You must have WRITE permissions on a bucket to add an object to it.
 func (c *Client) putObjectDo(ctx context.Context, bucketName, objectName string, reader io.Reader, md5Base64, sha256Hex string, size int64, opts PutObjectOptions) (UploadInfo, error) {
@@ -748,21 +938,11 @@ func (c *Client) putObjectDo(ctx context.Context, bucketName, objectName string,
 		contentSHA256Hex: sha256Hex,
 		streamSha256:     !opts.DisableContentSha256,
 	}
-	// Add CRC when client supports it, MD5 is not set, not Google and we don't add SHA256 to chunks.
-	addCrc := c.trailingHeaderSupport && md5Base64 == "" && !s3utils.IsGoogleEndpoint(*c.endpointURL) && (opts.DisableContentSha256 || c.secure)
 	if opts.Checksum.IsSet() {
 		reqMetadata.addCrc = &opts.Checksum
-	} else if addCrc {
-		// If user has added checksums, don't add them ourselves.
-		for k := range opts.UserMetadata {
-			if strings.HasPrefix(strings.ToLower(k), "x-amz-checksum-") {
-				addCrc = false
-			}
-		}
-		if addCrc {
-			opts.AutoChecksum.SetDefault(ChecksumCRC32C)
-			reqMetadata.addCrc = &opts.AutoChecksum
-		}
+	} else if md5Base64 == "" && c.addsAutoChecksum(opts) {
+		opts.AutoChecksum.SetDefault(ChecksumCRC32C)
+		reqMetadata.addCrc = &opts.AutoChecksum
 	}
 
 	if opts.Internal.SourceVersionID != "" {
@@ -799,6 +979,7 @@ func (c *Client) putObjectDo(ctx context.Context, bucketName, objectName string,
 		Size:             size,
 		Expiration:       expTime,
 		ExpirationRuleID: ruleID,
+		RequestCharged:   h.Get(amzRequestCharged) == requester,
 
 		// Checksum values
 		ChecksumCRC32:     h.Get(ChecksumCRC32.Key()),
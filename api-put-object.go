@@ -30,6 +30,7 @@ import (
 
 	"github.com/minio/minio-go/v7/pkg/encrypt"
 	"github.com/minio/minio-go/v7/pkg/s3utils"
+	"github.com/minio/minio-go/v7/pkg/tags"
 	"golang.org/x/net/http/httpguts"
 )
 
@@ -70,7 +71,15 @@ type AdvancedPutOptions struct {
 
 // PutObjectOptions represents options specified by user for PutObject call
 type PutObjectOptions struct {
-	UserMetadata            map[string]string
+	// UserMetadata keys may be given with or without the `x-amz-meta-`
+	// prefix, the prefix is added exactly once when sending the request.
+	// Reserved headers are rejected, except for ACL, grant and checksum
+	// headers which are sent as is. Keys naming the same header, e.g.
+	// "color" and "X-Amz-Meta-Color", are rejected as well.
+	UserMetadata map[string]string
+	// UserTags are sent in the x-amz-tagging header of the upload, or of
+	// the request initiating a multipart upload. At most 10 tags are
+	// accepted, see pkg/tags for the key and value constraints.
 	UserTags                map[string]string
 	Progress                io.Reader
 	ContentType             string
@@ -87,10 +96,25 @@ type PutObjectOptions struct {
 	WebsiteRedirectLocation string
 	PartSize                uint64
 	LegalHold               LegalHoldStatus
-	SendContentMd5          bool
 	DisableContentSha256    bool
 	DisableMultipart        bool
 
+	// SendContentMd5 computes the MD5 of every request body and sends
+	// it as the Content-MD5 header, the server then rejects a body
+	// corrupted in transit. Readers which are not seekable have to be
+	// buffered in memory to compute it before the body is sent, so it
+	// is off by default for multipart and streaming uploads. Single PUT
+	// uploads of seekable readers send it by default, unless a trailing
+	// checksum protects the body already. Ignored when Checksum is set,
+	// which protects the data the same way.
+	SendContentMd5 bool
+
+	// DisableContentMD5 never sends Content-MD5, not even on single PUT
+	// uploads, for gateways which reject the header. The body is then
+	// only protected by the SHA256 payload signature or a checksum, if
+	// any, and a body corrupted in transit may be stored.
+	DisableContentMD5 bool
+
 	// AutoChecksum is the type of checksum that will be added if no other checksum is added,
 	// like MD5 or SHA256 streaming checksum, and it is feasible for the upload type.
 	// If none is specified CRC32C is used, since it is generally the fastest.
@@ -103,11 +127,42 @@ type PutObjectOptions struct {
 	// This will disable content MD5 checksums if set.
 	Checksum ChecksumType
 
+	// Resumable resumes the incomplete multipart upload of the object
+	// left by an earlier resumable upload of the same Client, parts
+	// uploaded before are skipped if their ETag and checksum match the
+	// local data. The incomplete upload is kept on failure so it can be
+	// resumed. It is only resumed with the same encryption, content
+	// type, metadata, tagging and checksum settings, otherwise it is
+	// aborted and a new upload is started. Uploads started by other
+	// clients are never resumed, their settings cannot be verified.
+	// Only used for multipart uploads from an io.ReaderAt, such as the
+	// file of FPutObject, without SendContentMd5 or ConcurrentStreamParts.
+	Resumable bool
+
 	// ConcurrentStreamParts will create NumThreads buffers of PartSize bytes,
 	// fill them serially and upload them in parallel.
 	// This can be used for faster uploads on non-seekable or slow-to-seek input.
 	ConcurrentStreamParts bool
-	Internal              AdvancedPutOptions
+
+	// DetectContentType sets an empty ContentType from the extension
+	// of the object name, using the types registered with
+	// AddContentTypeMapping and the mime package. When the extension
+	// is unknown the content is sniffed: the type is detected from its
+	// first 512 bytes with http.DetectContentType, for FPutObject too.
+	DetectContentType bool
+
+	// RequestPayer sends x-amz-request-payer: requester with all the
+	// requests of the upload to write to a requester pays bucket, use
+	// Options.RequestPayer to send it when aborting failed uploads.
+	RequestPayer bool
+
+	// MaxSize rejects objects larger than MaxSize bytes with a
+	// MaxSizeError, before anything is uploaded when the size is known
+	// and as soon as more bytes are read otherwise, the incomplete
+	// multipart upload is aborted then. Zero means no limit.
+	MaxSize int64
+
+	Internal AdvancedPutOptions
 
 	customHeaders http.Header
 }
@@ -174,6 +229,9 @@ func (opts PutObjectOptions) Header() (header http.Header) {
 	if opts.CacheControl != "" {
 		header.Set("Cache-Control", opts.CacheControl)
 	}
+	if opts.RequestPayer {
+		header.Set(amzRequestPayer, requester)
+	}
 
 	if !opts.Expires.IsZero() {
 		header.Set("Expires", opts.Expires.UTC().Format(http.TimeFormat))
@@ -233,11 +291,7 @@ func (opts PutObjectOptions) Header() (header http.Header) {
 	}
 
 	for k, v := range opts.UserMetadata {
-		if isAmzHeader(k) || isStandardHeader(k) || isStorageClassHeader(k) || isMinioHeader(k) {
-			header.Set(k, v)
-		} else {
-			header.Set("x-amz-meta-"+k, v)
-		}
+		header.Set(userMetadataHeaderKey(k), v)
 	}
 
 	// set any other additional custom headers.
@@ -250,12 +304,28 @@ func (opts PutObjectOptions) Header() (header http.Header) {
 
 // validate() checks if the UserMetadata map has standard headers or and raises an error if so.
 func (opts PutObjectOptions) validate(c *Client) (err error) {
-	for k, v := range opts.UserMetadata {
-		if !httpguts.ValidHeaderFieldName(k) || isStandardHeader(k) || isSSEHeader(k) || isStorageClassHeader(k) || isMinioHeader(k) {
+	for k := range opts.UserMetadata {
+		if isStandardHeader(k) || isSSEHeader(k) || isStorageClassHeader(k) || isMinioHeader(k) {
 			return errInvalidArgument(k + " unsupported user defined metadata name")
 		}
-		if !httpguts.ValidHeaderFieldValue(v) {
-			return errInvalidArgument(v + " unsupported user defined metadata value")
+	}
+	if err = validateUserMetadata(opts.UserMetadata); err != nil {
+		return err
+	}
+	if len(opts.UserTags) != 0 {
+		if _, err = tags.MapToObjectTags(opts.UserTags); err != nil {
+			return errInvalidArgument(err.Error())
+		}
+	}
+	if opts.StorageClass != "" {
+		// Storage classes are tokens, e.g. STANDARD or REDUCED_REDUNDANCY.
+		if !httpguts.ValidHeaderFieldName(opts.StorageClass) {
+			return errInvalidArgument(opts.StorageClass + " invalid storage class")
+		}
+		if c != nil && len(c.storageClasses) > 0 {
+			if _, ok := c.storageClasses[opts.StorageClass]; !ok {
+				return errInvalidArgument(opts.StorageClass + " unsupported storage class")
+			}
 		}
 	}
 	if opts.Mode != "" && !opts.Mode.IsValid() {
@@ -264,6 +334,9 @@ func (opts PutObjectOptions) validate(c *Client) (err error) {
 	if opts.LegalHold != "" && !opts.LegalHold.IsValid() {
 		return errInvalidArgument(opts.LegalHold.String() + " unsupported legal-hold status")
 	}
+	if opts.MaxSize < 0 {
+		return errInvalidArgument("MaxSize cannot be negative.")
+	}
 	if opts.Checksum.IsSet() {
 		switch {
 		case !c.trailingHeaderSupport:
@@ -311,20 +384,100 @@ func (c *Client) PutObject(ctx context.Context, bucketName, objectName string, r
 		return UploadInfo{}, errors.New("object size must be provided with disable multipart upload")
 	}
 
+	opts.UserMetadata = c.withDefaultUserMetadata(opts.UserMetadata)
+
 	err = opts.validate(c)
 	if err != nil {
 		return UploadInfo{}, err
 	}
 
+	if opts.DetectContentType && opts.ContentType == "" {
+		if opts.ContentType = contentTypeByExtension(objectName); opts.ContentType == "" {
+			if opts.ContentType, reader, err = sniffContentType(reader); err != nil {
+				return UploadInfo{}, err
+			}
+		}
+	}
+
+	if opts.MaxSize > 0 {
+		if objectSize > opts.MaxSize {
+			return UploadInfo{}, &MaxSizeError{BucketName: bucketName, ObjectName: objectName, MaxSize: opts.MaxSize, Size: objectSize}
+		}
+		// A known size is all that is read, only streams are counted.
+		if objectSize < 0 {
+			reader = &maxSizeReader{r: reader, bucketName: bucketName, objectName: objectName, maxSize: opts.MaxSize}
+		}
+	}
+
 	return c.putObjectCommon(ctx, bucketName, objectName, reader, objectSize, opts)
 }
 
+// withDefaultUserMetadata returns userMeta merged over the client
+// DefaultUserMetadata. Keys of userMeta win over the defaults naming
+// the same header, with or without the `x-amz-meta-` prefix.
+func (c *Client) withDefaultUserMetadata(userMeta map[string]string) map[string]string {
+	if len(c.defaultUserMetadata) == 0 {
+		return userMeta
+	}
+	set := make(map[string]bool, len(userMeta))
+	for k := range userMeta {
+		set[http.CanonicalHeaderKey(userMetadataHeaderKey(k))] = true
+	}
+	merged := make(map[string]string, len(c.defaultUserMetadata)+len(userMeta))
+	for k, v := range c.defaultUserMetadata {
+		if !set[http.CanonicalHeaderKey(userMetadataHeaderKey(k))] {
+			merged[k] = v
+		}
+	}
+	for k, v := range userMeta {
+		merged[k] = v
+	}
+	return merged
+}
+
+// MaxSizeError is returned by PutObject for an object larger than
+// PutObjectOptions.MaxSize. Size is the declared size of the object, or
+// the number of bytes read when the upload was aborted for a stream of
+// unknown size.
+type MaxSizeError struct {
+	BucketName string
+	ObjectName string
+	MaxSize    int64
+	Size       int64
+}
+
+func (err *MaxSizeError) Error() string {
+	return fmt.Sprintf("Object %s/%s of size %d exceeds the maximum size %d.", err.BucketName, err.ObjectName, err.Size, err.MaxSize)
+}
+
+// maxSizeReader fails with a MaxSizeError once more than maxSize bytes
+// are read.
+type maxSizeReader struct {
+	r                      io.Reader
+	bucketName, objectName string
+	maxSize, read          int64
+}
+
+func (m *maxSizeReader) Read(p []byte) (n int, err error) {
+	n, err = m.r.Read(p)
+	m.read += int64(n)
+	if m.read > m.maxSize {
+		// Drop the bytes read, readFull ignores an error returned
+		// along with a full buffer.
+		return 0, &MaxSizeError{BucketName: m.bucketName, ObjectName: m.objectName, MaxSize: m.maxSize, Size: m.read}
+	}
+	return n, err
+}
+
 func (c *Client) putObjectCommon(ctx context.Context, bucketName, objectName string, reader io.Reader, size int64, opts PutObjectOptions) (info UploadInfo, err error) {
 	// Check for largest object size allowed.
 	if size > int64(maxMultipartPutObjectSize) {
 		return UploadInfo{}, errEntityTooLarge(size, maxMultipartPutObjectSize, bucketName, objectName)
 	}
 	opts.AutoChecksum.SetDefault(ChecksumCRC32C)
+	if opts.DisableContentMD5 {
+		opts.SendContentMd5 = false
+	}
 
 	// NOTE: Streaming signature is not supported by GCS.
 	if s3utils.IsGoogleEndpoint(*c.endpointURL) {
@@ -410,7 +563,11 @@ func (c *Client) putObjectMultipartStreamNoLength(ctx context.Context, bucketNam
 	partsInfo := make(map[int]ObjectPart)
 
 	// Create a buffer.
-	buf := make([]byte, partSize)
+	buf, err := c.uploadBuffers.get(ctx, partSize)
+	if err != nil {
+		return UploadInfo{}, err
+	}
+	defer c.uploadBuffers.put(buf)
 
 	// Create checksums
 	// CRC32C is ~50% faster on AMD64 @ 30GB/s
@@ -446,7 +603,7 @@ func (c *Client) putObjectMultipartStreamNoLength(ctx context.Context, bucketNam
 		rd := newHook(bytes.NewReader(buf[:length]), opts.Progress)
 
 		// Proceed to upload the part.
-		p := uploadPartParams{bucketName: bucketName, objectName: objectName, uploadID: uploadID, reader: rd, partNumber: partNumber, md5Base64: md5Base64, size: int64(length), sse: opts.ServerSideEncryption, streamSha256: !opts.DisableContentSha256, customHeader: customHeader}
+		p := uploadPartParams{bucketName: bucketName, objectName: objectName, uploadID: uploadID, reader: rd, partNumber: partNumber, md5Base64: md5Base64, size: int64(length), sse: opts.ServerSideEncryption, streamSha256: !opts.DisableContentSha256, customHeader: customHeader, requestPayer: opts.RequestPayer}
 		objPart, uerr := c.uploadPart(ctx, p)
 		if uerr != nil {
 			return UploadInfo{}, uerr
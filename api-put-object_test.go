@@ -17,13 +17,24 @@
 package minio
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/tags"
 )
 
 func TestPutObjectOptionsValidate(t *testing.T) {
@@ -68,6 +79,221 @@ func TestPutObjectOptionsValidate(t *testing.T) {
 	}
 }
 
+func TestPutObjectOptionsValidateUserTags(t *testing.T) {
+	tooMany := make(map[string]string)
+	for i := 0; i < 11; i++ {
+		tooMany[fmt.Sprintf("key%d", i)] = "value"
+	}
+	testCases := []struct {
+		tags       map[string]string
+		shouldPass bool
+	}{
+		{nil, true},
+		{map[string]string{"project": "minio-go", "stage": "test+1"}, true},
+		{map[string]string{"key$": "value"}, false},
+		{map[string]string{"key": strings.Repeat("v", 257)}, false},
+		{tooMany, false},
+	}
+	for i, testCase := range testCases {
+		err := PutObjectOptions{UserTags: testCase.tags}.validate(nil)
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: expected shouldPass %t, got %v", i+1, testCase.shouldPass, err)
+		}
+		err = CopyDestOptions{Bucket: "bucket", Object: "object", UserTags: testCase.tags}.validate()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Test %d: expected shouldPass %t for copy, got %v", i+1, testCase.shouldPass, err)
+		}
+	}
+}
+
+func TestPutObjectUserTags(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		tagging = make(map[string]string)
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		object := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		query := r.URL.Query()
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && query.Has("tagging"):
+			otags, err := tags.ParseObjectTags(tagging[object])
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			xml.NewEncoder(w).Encode(otags)
+		case r.Method == http.MethodPost && query.Has("uploads"):
+			// Tags are sent with the request initiating a multipart upload.
+			tagging[object] = r.Header.Get(amzTaggingHeader)
+			xml.NewEncoder(w).Encode(initiateMultipartUploadResult{Bucket: "bucket", Key: object, UploadID: "upload-id"})
+		case r.Method == http.MethodPut && query.Has("uploadId"):
+			if r.Header.Get(amzTaggingHeader) != "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			io.Copy(io.Discard, r.Body)
+			w.Header().Set("ETag", `"etag-`+query.Get("partNumber")+`"`)
+		case r.Method == http.MethodPost && query.Has("uploadId"):
+			io.Copy(io.Discard, r.Body)
+			xml.NewEncoder(w).Encode(completeMultipartUploadResult{Bucket: "bucket", Key: object, ETag: `"etag-2"`})
+		case r.Method == http.MethodPut && object != "multipart":
+			tagging[object] = r.Header.Get(amzTaggingHeader)
+			io.Copy(io.Discard, r.Body)
+			w.Header().Set("ETag", `"etag"`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userTags := map[string]string{
+		"project":       "minio-go",
+		"store forever": "a/b+c=d",
+	}
+	testCases := []struct {
+		object string
+		size   int64
+	}{
+		{"single", 1024},
+		{"multipart", 6 << 20},
+	}
+	for _, testCase := range testCases {
+		_, err = clnt.PutObject(context.Background(), "bucket", testCase.object, bytes.NewReader(make([]byte, testCase.size)), testCase.size, PutObjectOptions{
+			UserTags: userTags,
+			PartSize: absMinPartSize,
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.object, err)
+		}
+		if header := tagging[testCase.object]; header != "project=minio-go&store%20forever=a%2Fb%2Bc%3Dd" {
+			t.Fatalf("%s: unexpected x-amz-tagging header %q", testCase.object, header)
+		}
+		otags, err := clnt.GetObjectTagging(context.Background(), "bucket", testCase.object, GetObjectTaggingOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.object, err)
+		}
+		if !reflect.DeepEqual(otags.ToMap(), userTags) {
+			t.Fatalf("%s: expected tags %v, got %v", testCase.object, userTags, otags.ToMap())
+		}
+	}
+
+	// Invalid tags are rejected before any request is sent.
+	tagging = make(map[string]string)
+	_, err = clnt.PutObject(context.Background(), "bucket", "invalid", bytes.NewReader(nil), 0, PutObjectOptions{
+		UserTags: map[string]string{"key": strings.Repeat("v", 257)},
+	})
+	if ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+	if _, ok := tagging["invalid"]; ok {
+		t.Fatal("Expected no request for invalid tags")
+	}
+}
+
+func TestPutObjectStorageClass(t *testing.T) {
+	var (
+		mu             sync.Mutex
+		storageClasses = make(map[string]string)
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		object := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		query := r.URL.Query()
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodHead:
+			class, ok := storageClasses[object]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+			w.Header().Set(amzStorageClass, class)
+		case r.Method == http.MethodPost && query.Has("uploads"):
+			storageClasses[object] = r.Header.Get(amzStorageClass)
+			xml.NewEncoder(w).Encode(initiateMultipartUploadResult{Bucket: "bucket", Key: object, UploadID: "upload-id"})
+		case r.Method == http.MethodPut && query.Has("uploadId"):
+			io.Copy(io.Discard, r.Body)
+			w.Header().Set("ETag", `"etag-`+query.Get("partNumber")+`"`)
+		case r.Method == http.MethodPost && query.Has("uploadId"):
+			io.Copy(io.Discard, r.Body)
+			xml.NewEncoder(w).Encode(completeMultipartUploadResult{Bucket: "bucket", Key: object, ETag: `"etag-2"`})
+		case r.Method == http.MethodPut && object != "multipart":
+			storageClasses[object] = r.Header.Get(amzStorageClass)
+			io.Copy(io.Discard, r.Body)
+			w.Header().Set("ETag", `"etag"`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		object, storageClass string
+		size                 int64
+	}{
+		{"single", "REDUCED_REDUNDANCY", 1024},
+		{"custom", "COLD_TIER", 1024},
+		{"multipart", "STANDARD_IA", 6 << 20},
+	}
+	for _, testCase := range testCases {
+		_, err = clnt.PutObject(context.Background(), "bucket", testCase.object, bytes.NewReader(make([]byte, testCase.size)), testCase.size, PutObjectOptions{
+			StorageClass: testCase.storageClass,
+			PartSize:     absMinPartSize,
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.object, err)
+		}
+		objInfo, err := clnt.StatObject(context.Background(), "bucket", testCase.object, StatObjectOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.object, err)
+		}
+		if objInfo.StorageClass != testCase.storageClass {
+			t.Fatalf("%s: expected storage class %s, got %s", testCase.object, testCase.storageClass, objInfo.StorageClass)
+		}
+	}
+
+	// Restrict the accepted storage classes.
+	clnt, err = New(srv.Listener.Addr().String(), &Options{
+		Region:         "us-east-1",
+		Secure:         true,
+		Transport:      srv.Client().Transport,
+		StorageClasses: []string{"STANDARD", "GLACIER"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, storageClass := range []string{"COLD_TIER", "INVALID CLASS"} {
+		_, err = clnt.PutObject(context.Background(), "bucket", "rejected", bytes.NewReader(nil), 0, PutObjectOptions{StorageClass: storageClass})
+		if ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Fatalf("Expected storage class %q to be rejected, got %v", storageClass, err)
+		}
+	}
+	if _, err = clnt.PutObject(context.Background(), "bucket", "glacier", bytes.NewReader(nil), 0, PutObjectOptions{StorageClass: "GLACIER"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
 type InterceptRouteTripper struct {
 	request *http.Request
 }
@@ -166,3 +392,492 @@ func Test_SSEHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestUserMetadataPrefixNormalization(t *testing.T) {
+	unprefixed := map[string]string{"Color": "red", "x-amz-acl": "private"}
+	prefixed := map[string]string{"X-Amz-Meta-Color": "red", "x-amz-acl": "private"}
+	lowerPrefixed := map[string]string{"x-amz-meta-Color": "red", "x-amz-acl": "private"}
+
+	want := http.Header{
+		"X-Amz-Meta-Color": []string{"red"},
+		"X-Amz-Acl":        []string{"private"},
+	}
+	for _, userMetadata := range []map[string]string{unprefixed, prefixed, lowerPrefixed} {
+		putOpts := PutObjectOptions{UserMetadata: userMetadata}
+		if err := putOpts.validate(&Client{}); err != nil {
+			t.Fatal(err)
+		}
+		got := putOpts.Header()
+		delete(got, "Content-Type")
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("PutObject headers for %v: want %v, got %v", userMetadata, want, got)
+		}
+
+		dst := CopyDestOptions{Bucket: "bucket", Object: "object", ReplaceMetadata: true, UserMetadata: userMetadata}
+		if err := dst.validate(); err != nil {
+			t.Fatal(err)
+		}
+		got = make(http.Header)
+		dst.Marshal(got)
+		delete(got, "X-Amz-Metadata-Directive")
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("CopyObject headers for %v: want %v, got %v", userMetadata, want, got)
+		}
+	}
+}
+
+func TestUserMetadataReservedNames(t *testing.T) {
+	testCases := []map[string]string{
+		{"x-amz-tagging": "k=v"},
+		{"X-Amz-Date": "20240102T030405Z"},
+		{"x-amz-content-sha256": "UNSIGNED-PAYLOAD"},
+		{"x-amz-object-lock-legal-hold": "ON"},
+		{"x-amz-meta-": "empty"},
+		{"color": "red", "x-amz-meta-color": "blue"},
+	}
+	for i, userMetadata := range testCases {
+		if err := (PutObjectOptions{UserMetadata: userMetadata}).validate(&Client{}); err == nil {
+			t.Errorf("Test %d: expected PutObject to reject %v", i+1, userMetadata)
+		}
+		dst := CopyDestOptions{Bucket: "bucket", Object: "object", ReplaceMetadata: true, UserMetadata: userMetadata}
+		if err := dst.validate(); err == nil {
+			t.Errorf("Test %d: expected CopyObject to reject %v", i+1, userMetadata)
+		}
+	}
+}
+
+func TestPutObjectServerSideEncryption(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		headers = make(map[string]http.Header)
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		object := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			io.Copy(io.Discard, r.Body)
+			headers[object] = r.Header.Clone()
+			w.Header().Set("ETag", `"etag"`)
+		case http.MethodHead:
+			h, ok := headers[object]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+			for _, k := range []string{encrypt.SseGenericHeader, encrypt.SseKmsKeyID} {
+				if v := h.Get(k); v != "" {
+					w.Header().Set(k, v)
+				}
+			}
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kms, err := encrypt.NewSSEKMS("my-key", map[string]string{"project": "minio-go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		object    string
+		sse       encrypt.ServerSide
+		algorithm string
+		keyID     string
+		context   string
+	}{
+		{"sse-s3", encrypt.NewSSE(), "AES256", "", ""},
+		{"sse-kms", kms, "aws:kms", "my-key", base64.StdEncoding.EncodeToString([]byte(`{"project":"minio-go"}`))},
+	}
+	for _, testCase := range testCases {
+		_, err = clnt.PutObject(context.Background(), "bucket", testCase.object, strings.NewReader("data"), 4, PutObjectOptions{
+			ServerSideEncryption: testCase.sse,
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.object, err)
+		}
+		h := headers[testCase.object]
+		if h.Get(encrypt.SseGenericHeader) != testCase.algorithm || h.Get(encrypt.SseKmsKeyID) != testCase.keyID || h.Get(encrypt.SseEncryptionContext) != testCase.context {
+			t.Fatalf("%s: unexpected encryption headers %v", testCase.object, h)
+		}
+		objInfo, err := clnt.StatObject(context.Background(), "bucket", testCase.object, StatObjectOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.object, err)
+		}
+		if objInfo.ServerSideEncryption != testCase.algorithm || objInfo.SSEKMSKeyID != testCase.keyID {
+			t.Fatalf("%s: expected encryption %q with key %q, got %q with key %q", testCase.object, testCase.algorithm, testCase.keyID, objInfo.ServerSideEncryption, objInfo.SSEKMSKeyID)
+		}
+	}
+}
+
+func TestPutObjectSSECRequiresTLS(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer srv.Close()
+
+	sse, err := encrypt.NewSSEC(bytes.Repeat([]byte{'k'}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = clnt.PutObject(context.Background(), "bucket", "object", strings.NewReader("data"), 4, PutObjectOptions{ServerSideEncryption: sse})
+	if ToErrorResponse(err).Code != "InsecureSSECustomerRequest" {
+		t.Fatalf("Expected InsecureSSECustomerRequest, got %v", err)
+	}
+	_, err = clnt.StatObject(context.Background(), "bucket", "object", StatObjectOptions{ServerSideEncryption: sse})
+	if ToErrorResponse(err).Code != "InsecureSSECustomerRequest" {
+		t.Fatalf("Expected InsecureSSECustomerRequest, got %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Fatalf("Expected no request to be sent, got %d", n)
+	}
+
+	// Requests without SSE-C keys are not affected.
+	if _, err = clnt.PutObject(context.Background(), "bucket", "object", strings.NewReader("data"), 4, PutObjectOptions{ServerSideEncryption: encrypt.NewSSE()}); err != nil {
+		t.Fatal(err)
+	}
+
+	clnt, err = New(srv.Listener.Addr().String(), &Options{
+		Region:            "us-east-1",
+		AllowInsecureSSEC: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = clnt.PutObject(context.Background(), "bucket", "object", strings.NewReader("data"), 4, PutObjectOptions{ServerSideEncryption: sse}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPutObjectContentMD5(t *testing.T) {
+	var contentMD5 string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		contentMD5 = r.Header.Get("Content-Md5")
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("hello world")
+	expectedMD5 := sumMD5Base64(data)
+	testCases := []struct {
+		name     string
+		reader   func() io.Reader
+		opts     PutObjectOptions
+		expected string
+	}{
+		// Single PUTs of seekable readers send it by default.
+		{"default", func() io.Reader { return bytes.NewReader(data) }, PutObjectOptions{}, expectedMD5},
+		{"seekable", func() io.Reader { return bytes.NewReader(data) }, PutObjectOptions{SendContentMd5: true}, expectedMD5},
+		{"non-seekable", func() io.Reader { return io.MultiReader(bytes.NewReader(data)) }, PutObjectOptions{SendContentMd5: true}, expectedMD5},
+		{"non-seekable default", func() io.Reader { return io.MultiReader(bytes.NewReader(data)) }, PutObjectOptions{}, ""},
+		{"disabled", func() io.Reader { return bytes.NewReader(data) }, PutObjectOptions{DisableContentMD5: true}, ""},
+		{"disabled wins", func() io.Reader { return bytes.NewReader(data) }, PutObjectOptions{SendContentMd5: true, DisableContentMD5: true}, ""},
+	}
+	for _, testCase := range testCases {
+		contentMD5 = "unset"
+		if _, err = clnt.PutObject(context.Background(), "bucket", "object", testCase.reader(), int64(len(data)), testCase.opts); err != nil {
+			t.Fatalf("%s: %v", testCase.name, err)
+		}
+		if contentMD5 != testCase.expected {
+			t.Fatalf("%s: expected Content-Md5 %q, got %q", testCase.name, testCase.expected, contentMD5)
+		}
+	}
+}
+
+func TestPutObjectExpiration(t *testing.T) {
+	const expiration = `expiry-date="Fri, 23 Dec 2012 00:00:00 GMT", rule-id="tmp-cleanup"`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Amz-Expiration", expiration)
+		w.Header().Set("ETag", `"etag"`)
+		if r.Method == http.MethodHead {
+			w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+			w.Header().Set("Content-Length", "5")
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiry := time.Date(2012, time.December, 23, 0, 0, 0, 0, time.UTC)
+	info, err := clnt.PutObject(context.Background(), "bucket", "tmp/object", strings.NewReader("hello"), 5, PutObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Expiration.Equal(expiry) || info.ExpirationRuleID != "tmp-cleanup" {
+		t.Fatalf("Expected expiration %s tmp-cleanup, got %s %s", expiry, info.Expiration, info.ExpirationRuleID)
+	}
+
+	st, err := clnt.StatObject(context.Background(), "bucket", "tmp/object", StatObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.Expiration.Equal(expiry) || st.ExpirationRuleID != "tmp-cleanup" {
+		t.Fatalf("Expected expiration %s tmp-cleanup, got %s %s", expiry, st.Expiration, st.ExpirationRuleID)
+	}
+}
+
+func TestPutObjectMaxSize(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			requests = append(requests, "new-multipart")
+			io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>upload-id</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == http.MethodPut && q.Get("uploadId") == "upload-id":
+			requests = append(requests, "upload-part")
+			w.Header().Set("ETag", `"etag-`+q.Get("partNumber")+`"`)
+		case r.Method == http.MethodDelete && q.Get("uploadId") == "upload-id":
+			requests = append(requests, "abort-multipart")
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && q.Get("uploadId") == "upload-id":
+			requests = append(requests, "complete-multipart")
+			io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>"etag-2"</ETag></CompleteMultipartUploadResult>`)
+		case r.Method == http.MethodPut:
+			requests = append(requests, "put")
+			w.Header().Set("ETag", `"etag"`)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:     "us-east-1",
+		Secure:     true,
+		Transport:  srv.Client().Transport,
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const maxSize = 6 << 20
+	data := bytes.Repeat([]byte("a"), 12<<20)
+
+	// A known size above the limit is rejected before any request.
+	_, err = clnt.PutObject(context.Background(), "bucket", "object", bytes.NewReader(data), int64(len(data)), PutObjectOptions{MaxSize: maxSize})
+	var sizeErr *MaxSizeError
+	if !errors.As(err, &sizeErr) || sizeErr.Size != int64(len(data)) || sizeErr.MaxSize != maxSize {
+		t.Fatalf("Expected MaxSizeError for %d bytes, got %v", len(data), err)
+	}
+	if len(requests) != 0 {
+		t.Fatalf("Expected no requests, got %v", requests)
+	}
+
+	// A stream is aborted once it grows above the limit.
+	_, err = clnt.PutObject(context.Background(), "bucket", "object", io.MultiReader(bytes.NewReader(data)), -1, PutObjectOptions{MaxSize: maxSize, PartSize: 5 << 20})
+	if !errors.As(err, &sizeErr) || sizeErr.Size <= maxSize || sizeErr.Size > int64(len(data)) {
+		t.Fatalf("Expected MaxSizeError after more than %d bytes, got %v", maxSize, err)
+	}
+	expected := []string{"new-multipart", "upload-part", "abort-multipart"}
+	if fmt.Sprint(requests) != fmt.Sprint(expected) {
+		t.Fatalf("Expected requests %v, got %v", expected, requests)
+	}
+
+	// Objects within the limit are uploaded.
+	requests = nil
+	if _, err = clnt.PutObject(context.Background(), "bucket", "object", io.MultiReader(bytes.NewReader(data[:maxSize])), -1, PutObjectOptions{MaxSize: maxSize, PartSize: 5 << 20}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = clnt.PutObject(context.Background(), "bucket", "object", bytes.NewReader(data[:1024]), 1024, PutObjectOptions{MaxSize: maxSize}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPutObjectDefaultUserMetadata(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		headers = make(map[string]http.Header)
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		meta := make(http.Header)
+		for k, v := range r.Header {
+			if strings.HasPrefix(k, "X-Amz-Meta-") {
+				meta[k] = v
+			}
+		}
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			headers["new-multipart"] = meta
+			io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>upload-id</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == http.MethodPut && q.Get("uploadId") == "upload-id":
+			w.Header().Set("ETag", `"etag-`+q.Get("partNumber")+`"`)
+		case r.Method == http.MethodPost && q.Get("uploadId") == "upload-id":
+			io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>"etag-2"</ETag></CompleteMultipartUploadResult>`)
+		case r.Method == http.MethodPut:
+			headers["put"] = meta
+			w.Header().Set("ETag", `"etag"`)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	defaults := map[string]string{"x-amz-meta-app": "uploader", "Env": "prod"}
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:              "us-east-1",
+		Secure:              true,
+		Transport:           srv.Client().Transport,
+		MaxRetries:          1,
+		DefaultUserMetadata: defaults,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The client keeps its own copy of the defaults.
+	defaults["Env"] = "changed"
+
+	data := bytes.Repeat([]byte("a"), 6<<20)
+	testCases := []struct {
+		userMetadata map[string]string
+		expected     http.Header
+	}{
+		{nil, http.Header{"X-Amz-Meta-App": {"uploader"}, "X-Amz-Meta-Env": {"prod"}}},
+		{map[string]string{"X-Amz-Meta-Env": "dev", "Owner": "me"}, http.Header{"X-Amz-Meta-App": {"uploader"}, "X-Amz-Meta-Env": {"dev"}, "X-Amz-Meta-Owner": {"me"}}},
+		{map[string]string{"APP": "other"}, http.Header{"X-Amz-Meta-App": {"other"}, "X-Amz-Meta-Env": {"prod"}}},
+	}
+	for i, testCase := range testCases {
+		headers = make(map[string]http.Header)
+		opts := PutObjectOptions{UserMetadata: testCase.userMetadata}
+		if _, err = clnt.PutObject(context.Background(), "bucket", "object", bytes.NewReader(data[:1]), 1, opts); err != nil {
+			t.Fatal(err)
+		}
+		opts.PartSize = 5 << 20
+		if _, err = clnt.PutObject(context.Background(), "bucket", "object", bytes.NewReader(data), int64(len(data)), opts); err != nil {
+			t.Fatal(err)
+		}
+		for _, request := range []string{"put", "new-multipart"} {
+			if !reflect.DeepEqual(headers[request], testCase.expected) {
+				t.Fatalf("Test %d: expected %v for %s, got %v", i+1, testCase.expected, request, headers[request])
+			}
+		}
+		if len(testCase.userMetadata) > 0 && len(opts.UserMetadata) != len(testCase.userMetadata) {
+			t.Fatalf("Test %d: the caller's metadata was modified", i+1)
+		}
+	}
+
+	if _, err = New(srv.Listener.Addr().String(), &Options{DefaultUserMetadata: map[string]string{"X-Amz-Date": "1"}}); err == nil {
+		t.Fatal("Expected an error for reserved default metadata")
+	}
+}
+
+func TestPutObjectMaxUploadBufferBytes(t *testing.T) {
+	const partSize = 5 << 20
+	var inFlight, peak atomic.Int64
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>upload-id</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == http.MethodPut && q.Get("uploadId") == "upload-id":
+			n := inFlight.Add(1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			io.Copy(io.Discard, r.Body)
+			time.Sleep(20 * time.Millisecond)
+			inFlight.Add(-1)
+			w.Header().Set("ETag", `"etag-`+q.Get("partNumber")+`"`)
+		case r.Method == http.MethodPost && q.Get("uploadId") == "upload-id":
+			io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>"etag-8"</ETag></CompleteMultipartUploadResult>`)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	data := bytes.Repeat([]byte("a"), 8*partSize)
+	testCases := []struct {
+		maxUploadBufferBytes int64
+		maxInFlight          int64
+		maxBuffered          int64
+	}{
+		// Parts in flight across both uploads hold a buffer each.
+		{3 * partSize, 3, 3 * partSize},
+		// A single part larger than the limit is uploaded alone.
+		{partSize / 2, 1, partSize},
+	}
+	for i, testCase := range testCases {
+		clnt, err := New(srv.Listener.Addr().String(), &Options{
+			Region:               "us-east-1",
+			Secure:               true,
+			Transport:            srv.Client().Transport,
+			MaxRetries:           1,
+			MaxUploadBufferBytes: testCase.maxUploadBufferBytes,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		peak.Store(0)
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for j := range errs {
+			wg.Add(1)
+			go func(j int) {
+				defer wg.Done()
+				opts := PutObjectOptions{PartSize: partSize, NumThreads: 4, ConcurrentStreamParts: j == 0}
+				_, errs[j] = clnt.PutObject(context.Background(), "bucket", "object", bytes.NewReader(data), -1, opts)
+			}(j)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				t.Fatalf("Test %d: %v", i+1, err)
+			}
+		}
+		if buffered := clnt.uploadBuffers.peak; buffered > testCase.maxBuffered {
+			t.Fatalf("Test %d: expected at most %d bytes buffered, got %d", i+1, testCase.maxBuffered, buffered)
+		}
+		if p := peak.Load(); p > testCase.maxInFlight {
+			t.Fatalf("Test %d: expected at most %d parts in flight, got %d", i+1, testCase.maxInFlight, p)
+		}
+		if used := clnt.uploadBuffers.used; used != 0 {
+			t.Fatalf("Test %d: expected all buffers to be released, %d bytes are in use", i+1, used)
+		}
+	}
+}
@@ -21,9 +21,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/xml"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/minio/minio-go/v7/pkg/s3utils"
@@ -36,17 +40,32 @@ type BucketOptions = RemoveBucketOptions
 
 //revive:enable
 
-// RemoveBucketOptions special headers to purge buckets, only
-// useful when endpoint is MinIO
+// RemoveBucketOptions options to remove a bucket which is not empty.
 type RemoveBucketOptions struct {
+	// ForceDelete removes the bucket with all its objects, including
+	// all object versions and delete markers, and its incomplete
+	// multipart uploads. MinIO purges the bucket on its own, on other
+	// servers the bucket is emptied client-side before removing it.
 	ForceDelete bool
+
+	// GovernanceBypass removes objects under governance retention
+	// when emptying the bucket.
+	GovernanceBypass bool
+
+	// Number of MultiDelete requests sent concurrently when emptying
+	// the bucket, defaults to 4.
+	Concurrency int
 }
 
 // RemoveBucketWithOptions deletes the bucket name.
 //
 // All objects (including all object versions and delete markers)
 // in the bucket will be deleted forcibly if bucket options set
-// ForceDelete to 'true'.
+// ForceDelete to 'true'. MinIO is asked to purge the bucket with
+// the x-minio-force-delete header, servers ignoring the header
+// answer BucketNotEmpty and have the bucket emptied client-side.
+// The errors met while emptying the bucket are returned joined,
+// the bucket is only removed once empty.
 func (c *Client) RemoveBucketWithOptions(ctx context.Context, bucketName string, opts RemoveBucketOptions) error {
 	// Input validation.
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
@@ -59,7 +78,24 @@ func (c *Client) RemoveBucketWithOptions(ctx context.Context, bucketName string,
 		headers.Set(minIOForceDelete, "true")
 	}
 
-	// Execute DELETE on bucket.
+	err := c.deleteBucket(ctx, bucketName, headers)
+	if opts.ForceDelete && errors.Is(err, ErrBucketNotEmpty) {
+		if err = c.emptyBucket(ctx, bucketName, opts); err != nil {
+			return err
+		}
+		err = c.deleteBucket(ctx, bucketName, headers)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Remove the location from cache on a successful delete.
+	c.bucketLocCache.Delete(bucketName)
+	return nil
+}
+
+// deleteBucket - executes DELETE on the bucket with the given headers.
+func (c *Client) deleteBucket(ctx context.Context, bucketName string, headers http.Header) error {
 	resp, err := c.executeMethod(ctx, http.MethodDelete, requestMetadata{
 		bucketName:       bucketName,
 		contentSHA256Hex: emptySHA256Hex,
@@ -74,12 +110,55 @@ func (c *Client) RemoveBucketWithOptions(ctx context.Context, bucketName string,
 			return httpRespToErrorResponse(resp, bucketName, "")
 		}
 	}
-
-	// Remove the location from cache on a successful delete.
-	c.bucketLocCache.Delete(bucketName)
 	return nil
 }
 
+// emptyBucket removes all object versions and delete markers of the
+// bucket and aborts its incomplete multipart uploads.
+func (c *Client) emptyBucket(ctx context.Context, bucketName string, opts RemoveBucketOptions) error {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultWorkers
+	}
+
+	var (
+		errs      []error
+		listErr   error
+		objectsCh = make(chan ObjectInfo)
+	)
+	go func() {
+		defer close(objectsCh)
+		for object := range c.ListObjects(ctx, bucketName, ListObjectsOptions{WithVersions: true, Recursive: true}) {
+			if object.Err != nil {
+				listErr = object.Err
+				return
+			}
+			select {
+			case objectsCh <- object:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	for res := range c.RemoveObjectsWithResult(ctx, bucketName, objectsCh, RemoveObjectsOptions{
+		GovernanceBypass: opts.GovernanceBypass,
+		Concurrency:      concurrency,
+	}) {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("%s (version %q): %w", res.ObjectName, res.ObjectVersionID, res.Err))
+		}
+	}
+	// The results are closed after the objects, listErr is set.
+	if listErr != nil {
+		errs = append(errs, listErr)
+	}
+
+	if _, err := c.removeIncompleteUploads(ctx, bucketName, "", time.Time{}, concurrency); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
 // RemoveBucket deletes the bucket name.
 //
 //	All objects (including all object versions and delete markers).
@@ -124,7 +203,12 @@ type RemoveObjectOptions struct {
 	ForceDelete      bool
 	GovernanceBypass bool
 	VersionID        string
-	Internal         AdvancedRemoveOptions
+
+	// RequestPayer sends x-amz-request-payer: requester to delete from
+	// a requester pays bucket, see also Options.RequestPayer.
+	RequestPayer bool
+
+	Internal AdvancedRemoveOptions
 }
 
 // RemoveObject removes an object from a bucket.
@@ -157,6 +241,9 @@ func (c *Client) removeObject(ctx context.Context, bucketName, objectName string
 		// Set the bypass goverenance retention header
 		headers.Set(amzBypassGovernance, "true")
 	}
+	if opts.RequestPayer {
+		headers.Set(amzRequestPayer, requester)
+	}
 	if opts.Internal.ReplicationDeleteMarker {
 		headers.Set(minIOBucketReplicationDeleteMarker, "true")
 	}
@@ -277,10 +364,11 @@ func processRemoveMultiObjectsResponse(body io.Reader, resultCh chan<- RemoveObj
 		resultCh <- RemoveObjectResult{
 			ObjectName:      obj.Key,
 			ObjectVersionID: obj.VersionID,
-			Err: ErrorResponse{
+			Err: ToErrorResponse(ErrorResponse{
 				Code:    obj.Code,
 				Message: obj.Message,
-			},
+				Key:     obj.Key,
+			}),
 		}
 	}
 }
@@ -288,11 +376,19 @@ func processRemoveMultiObjectsResponse(body io.Reader, resultCh chan<- RemoveObj
 // RemoveObjectsOptions represents options specified by user for RemoveObjects call
 type RemoveObjectsOptions struct {
 	GovernanceBypass bool
+
+	// Number of MultiDelete requests sent concurrently, defaults
+	// to 1. Every request removes a batch of up to 1000 objects.
+	Concurrency int
 }
 
 // RemoveObjects removes multiple objects from a bucket while
 // it is possible to specify objects versions which are received from
-// objectsCh. Remove failures are sent back via error channel.
+// objectsCh. Only Key and VersionID of each ObjectInfo are used, a
+// non-empty VersionID removes that specific version. Objects are sent
+// in batches of up to 1000 per request, opts.Concurrency batches are
+// removed in parallel. Remove failures are sent back via error channel,
+// with the VersionID of the failed object.
 func (c *Client) RemoveObjects(ctx context.Context, bucketName string, objectsCh <-chan ObjectInfo, opts RemoveObjectsOptions) <-chan RemoveObjectError {
 	errorCh := make(chan RemoveObjectError, 1)
 
@@ -361,6 +457,176 @@ func (c *Client) RemoveObjectsWithResult(ctx context.Context, bucketName string,
 	return resultCh
 }
 
+// KeepLastNVersionsOptions represents options specified by user for KeepLastNVersions call
+type KeepLastNVersionsOptions struct {
+	GovernanceBypass bool
+}
+
+// KeepLastNVersions prunes the versions of an object, the newest 'n'
+// versions (including delete markers) are kept and all the older
+// versions are removed by their version id. Remove results, successes
+// and failures are sent back via RemoveObjectResult channel.
+func (c *Client) KeepLastNVersions(ctx context.Context, bucketName, objectName string, n int, opts KeepLastNVersionsOptions) <-chan RemoveObjectResult {
+	resultCh := make(chan RemoveObjectResult, 1)
+
+	// Input validation.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		defer close(resultCh)
+		resultCh <- RemoveObjectResult{
+			Err: err,
+		}
+		return resultCh
+	}
+	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+		defer close(resultCh)
+		resultCh <- RemoveObjectResult{
+			Err: err,
+		}
+		return resultCh
+	}
+	if n < 1 {
+		defer close(resultCh)
+		resultCh <- RemoveObjectResult{
+			Err: errInvalidArgument("Number of versions to keep should be at least 1"),
+		}
+		return resultCh
+	}
+
+	go func() {
+		defer close(resultCh)
+
+		var listErr error
+		objectsCh := make(chan ObjectInfo)
+		go func() {
+			defer close(objectsCh)
+
+			// Versions of a key are listed from the newest to the oldest.
+			count := 0
+			for object := range c.ListObjects(ctx, bucketName, ListObjectsOptions{
+				Prefix:       objectName,
+				WithVersions: true,
+				Recursive:    true,
+			}) {
+				if object.Err != nil {
+					listErr = object.Err
+					return
+				}
+				if object.Key != objectName {
+					continue
+				}
+				if count++; count <= n {
+					continue
+				}
+				select {
+				case objectsCh <- object:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		removeCh := make(chan RemoveObjectResult, 1)
+		go c.removeObjects(ctx, bucketName, objectsCh, removeCh, RemoveObjectsOptions{
+			GovernanceBypass: opts.GovernanceBypass,
+		})
+		for res := range removeCh {
+			resultCh <- res
+		}
+		if listErr != nil {
+			resultCh <- RemoveObjectResult{
+				ObjectName: objectName,
+				Err:        listErr,
+			}
+		}
+	}()
+
+	return resultCh
+}
+
+// ObjectVersion identifies a single version of an object.
+type ObjectVersion struct {
+	Key       string
+	VersionID string
+}
+
+// DeleteObjectVersionsOptions represents options specified by user for DeleteObjectVersions call
+type DeleteObjectVersionsOptions struct {
+	GovernanceBypass bool
+
+	// Number of MultiDelete requests sent concurrently, defaults to 1.
+	Concurrency int
+}
+
+// DeleteObjectVersions removes the object versions received from
+// versionsCh, versions are sent in batches of up to 1000 per request.
+// Every entry needs a VersionID, entries without one are reported as
+// failed instead of creating a delete marker. Remove results, successes
+// and failures are sent back via RemoveObjectResult channel with the
+// version id of the entry.
+func (c *Client) DeleteObjectVersions(ctx context.Context, bucketName string, versionsCh <-chan ObjectVersion, opts DeleteObjectVersionsOptions) <-chan RemoveObjectResult {
+	resultCh := make(chan RemoveObjectResult, 1)
+
+	// Validate if bucket name is valid.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		defer close(resultCh)
+		resultCh <- RemoveObjectResult{
+			Err: err,
+		}
+		return resultCh
+	}
+	// Validate versions channel to be properly allocated.
+	if versionsCh == nil {
+		defer close(resultCh)
+		resultCh <- RemoveObjectResult{
+			Err: errInvalidArgument("Versions channel cannot be nil"),
+		}
+		return resultCh
+	}
+
+	go func() {
+		defer close(resultCh)
+
+		// Entries without a version id are reported once all the
+		// versions have been received and removed.
+		var invalid []ObjectVersion
+		versionsDone := make(chan struct{})
+		objectsCh := make(chan ObjectInfo)
+		go func() {
+			defer close(versionsDone)
+			defer close(objectsCh)
+			for version := range versionsCh {
+				if version.VersionID == "" {
+					invalid = append(invalid, version)
+					continue
+				}
+				select {
+				case objectsCh <- ObjectInfo{Key: version.Key, VersionID: version.VersionID}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		removeCh := make(chan RemoveObjectResult, 1)
+		go c.removeObjects(ctx, bucketName, objectsCh, removeCh, RemoveObjectsOptions{
+			GovernanceBypass: opts.GovernanceBypass,
+			Concurrency:      opts.Concurrency,
+		})
+		for res := range removeCh {
+			resultCh <- res
+		}
+		<-versionsDone
+		for _, version := range invalid {
+			resultCh <- RemoveObjectResult{
+				ObjectName: version.Key,
+				Err:        errInvalidArgument("VersionID cannot be empty for " + version.Key),
+			}
+		}
+	}()
+
+	return resultCh
+}
+
 // Return true if the character is within the allowed characters in an XML 1.0 document
 // The list of allowed characters can be found here: https://www.w3.org/TR/xml/#charsets
 func validXMLChar(r rune) (ok bool) {
@@ -385,12 +651,31 @@ func hasInvalidXMLChar(str string) bool {
 func (c *Client) removeObjects(ctx context.Context, bucketName string, objectsCh <-chan ObjectInfo, resultCh chan<- RemoveObjectResult, opts RemoveObjectsOptions) {
 	maxEntries := 1000
 	finish := false
-	urlValues := make(url.Values)
-	urlValues.Set("delete", "")
 
 	// Close result channel when Multi delete finishes.
 	defer close(resultCh)
 
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	// Batches are removed by concurrent workers, each worker
+	// reports the results of its batch on resultCh.
+	batchCh := make(chan []ObjectInfo)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batchCh {
+				c.removeObjectsBatch(ctx, bucketName, batch, resultCh, opts)
+			}
+		}()
+	}
+	defer wg.Wait()
+	defer close(batchCh)
+
 	// Loop over entries by 1000 and call MultiDelete requests
 	for {
 		if finish {
@@ -413,7 +698,6 @@ func (c *Client) removeObjects(ctx context.Context, bucketName string, objectsCh
 					case "InvalidArgument", "NoSuchVersion":
 						continue
 					}
-					resultCh <- removeResult
 				}
 
 				resultCh <- removeResult
@@ -434,47 +718,69 @@ func (c *Client) removeObjects(ctx context.Context, bucketName string, objectsCh
 			finish = true
 		}
 
-		// Build headers.
-		headers := make(http.Header)
-		if opts.GovernanceBypass {
-			// Set the bypass goverenance retention header
-			headers.Set(amzBypassGovernance, "true")
-		}
-
-		// Generate remove multi objects XML request
-		removeBytes := generateRemoveMultiObjectsRequest(batch)
-		// Execute POST on bucket to remove objects.
-		resp, err := c.executeMethod(ctx, http.MethodPost, requestMetadata{
-			bucketName:       bucketName,
-			queryValues:      urlValues,
-			contentBody:      bytes.NewReader(removeBytes),
-			contentLength:    int64(len(removeBytes)),
-			contentMD5Base64: sumMD5Base64(removeBytes),
-			contentSHA256Hex: sum256Hex(removeBytes),
-			customHeader:     headers,
-		})
-		if resp != nil {
-			if resp.StatusCode != http.StatusOK {
-				e := httpRespToErrorResponse(resp, bucketName, "")
-				resultCh <- RemoveObjectResult{ObjectName: "", Err: e}
-			}
-		}
-		if err != nil {
+		select {
+		case batchCh <- batch:
+		case <-ctx.Done():
+			// No worker is going to pick up the batch anymore,
+			// report the cancellation against every object.
 			for _, b := range batch {
 				resultCh <- RemoveObjectResult{
 					ObjectName:      b.Key,
 					ObjectVersionID: b.VersionID,
-					Err:             err,
+					Err:             ctx.Err(),
 				}
 			}
-			continue
+			return
 		}
+	}
+}
 
-		// Process multiobjects remove xml response
-		processRemoveMultiObjectsResponse(resp.Body, resultCh)
+// removeObjectsBatch removes a batch of at most 1000 objects with a
+// single MultiDelete request and reports the result of every object.
+func (c *Client) removeObjectsBatch(ctx context.Context, bucketName string, batch []ObjectInfo, resultCh chan<- RemoveObjectResult, opts RemoveObjectsOptions) {
+	urlValues := make(url.Values)
+	urlValues.Set("delete", "")
 
+	// Build headers.
+	headers := make(http.Header)
+	if opts.GovernanceBypass {
+		// Set the bypass goverenance retention header
+		headers.Set(amzBypassGovernance, "true")
+	}
+
+	// Generate remove multi objects XML request
+	removeBytes := generateRemoveMultiObjectsRequest(batch)
+	// Execute POST on bucket to remove objects.
+	resp, err := c.executeMethod(ctx, http.MethodPost, requestMetadata{
+		bucketName:       bucketName,
+		queryValues:      urlValues,
+		contentBody:      bytes.NewReader(removeBytes),
+		contentLength:    int64(len(removeBytes)),
+		contentMD5Base64: sumMD5Base64(removeBytes),
+		contentSHA256Hex: sum256Hex(removeBytes),
+		customHeader:     headers,
+	})
+	if err == nil && resp != nil && resp.StatusCode != http.StatusOK {
+		// The whole batch failed, report it against every object
+		// and version so that callers can retry them individually.
+		err = httpRespToErrorResponse(resp, bucketName, "")
 		closeResponse(resp)
 	}
+	if err != nil {
+		for _, b := range batch {
+			resultCh <- RemoveObjectResult{
+				ObjectName:      b.Key,
+				ObjectVersionID: b.VersionID,
+				Err:             err,
+			}
+		}
+		return
+	}
+
+	// Process multiobjects remove xml response
+	processRemoveMultiObjectsResponse(resp.Body, resultCh)
+
+	closeResponse(resp)
 }
 
 // RemoveIncompleteUpload aborts an partially uploaded object.
@@ -503,6 +809,70 @@ func (c *Client) RemoveIncompleteUpload(ctx context.Context, bucketName, objectN
 	return nil
 }
 
+// RemoveIncompleteUploadsOptions holds the options of
+// RemoveIncompleteUploads.
+type RemoveIncompleteUploadsOptions struct {
+	// Only abort the uploads initiated more than OlderThan ago.
+	OlderThan time.Duration
+
+	// Number of uploads aborted concurrently, defaults to 4.
+	Concurrency int
+}
+
+// RemoveIncompleteUploads aborts the multipart uploads of all objects
+// under the prefix which were initiated more than opts.OlderThan ago,
+// this reclaims the storage of abandoned uploads. Uploads are aborted
+// concurrently, the number of aborted uploads is returned along with
+// the first error encountered.
+func (c *Client) RemoveIncompleteUploads(ctx context.Context, bucketName, prefix string, opts RemoveIncompleteUploadsOptions) (int, error) {
+	// Input validation.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		return 0, err
+	}
+	if err := s3utils.CheckValidObjectNamePrefix(prefix); err != nil {
+		return 0, err
+	}
+	if opts.OlderThan < 0 {
+		return 0, errInvalidArgument("Age of uploads to remove cannot be negative.")
+	}
+	return c.removeIncompleteUploads(ctx, bucketName, prefix, time.Now().Add(-opts.OlderThan), opts.Concurrency)
+}
+
+// removeIncompleteUploads aborts the incomplete multipart uploads under
+// prefix initiated before cutoff, all of them for a zero cutoff.
+func (c *Client) removeIncompleteUploads(ctx context.Context, bucketName, prefix string, cutoff time.Time, concurrency int) (int, error) {
+	var aborted int32
+
+	g, gctx := newWorkerGroup(ctx, concurrency)
+	// Listing stops once the context of the group is cancelled.
+	for upload := range c.listIncompleteUploads(gctx, bucketName, prefix, true, false) {
+		if upload.Err != nil {
+			g.setErr(upload.Err)
+			continue
+		}
+		if !cutoff.IsZero() && !upload.Initiated.Before(cutoff) {
+			continue
+		}
+		g.Go(func() error {
+			err := c.abortMultipartUpload(gctx, bucketName, upload.Key, upload.UploadID)
+			if err != nil {
+				// Upload was already completed or aborted.
+				if ToErrorResponse(err).Code == "NoSuchUpload" {
+					return nil
+				}
+				return err
+			}
+			atomic.AddInt32(&aborted, 1)
+			return nil
+		})
+	}
+	err := g.Wait()
+	if err == nil {
+		err = ctx.Err()
+	}
+	return int(atomic.LoadInt32(&aborted)), err
+}
+
 // abortMultipartUpload aborts a multipart upload for the given
 // uploadID, all previously uploaded parts are deleted.
 func (c *Client) abortMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string) error {
@@ -513,6 +883,10 @@ func (c *Client) abortMultipartUpload(ctx context.Context, bucketName, objectNam
 	if err := s3utils.CheckValidObjectName(objectName); err != nil {
 		return err
 	}
+	// An empty upload id would turn the request into an object delete.
+	if uploadID == "" {
+		return errInvalidArgument("uploadID cannot be empty.")
+	}
 
 	// Initialize url queries.
 	urlValues := make(url.Values)
@@ -538,6 +912,7 @@ func (c *Client) abortMultipartUpload(ctx context.Context, bucketName, objectNam
 				// This is needed specifically for abort and it cannot
 				// be converged into default case.
 				errorResponse = ErrorResponse{
+					StatusCode: resp.StatusCode,
 					Code:       "NoSuchUpload",
 					Message:    "The specified multipart upload does not exist.",
 					BucketName: bucketName,
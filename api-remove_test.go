@@ -0,0 +1,574 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRemoveObjectsWithVersions(t *testing.T) {
+	var requests [][]deleteObject
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["delete"]; !ok || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var del deleteMultiObjects
+		if err := xml.NewDecoder(r.Body).Decode(&del); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, del.Objects)
+
+		var result deleteMultiObjectsResult
+		for _, obj := range del.Objects {
+			if obj.VersionID == "locked" {
+				result.UnDeletedObjects = append(result.UnDeletedObjects, nonDeletedObject{
+					Key:       obj.Key,
+					VersionID: obj.VersionID,
+					Code:      "AccessDenied",
+					Message:   "Object is WORM protected and cannot be overwritten",
+				})
+				continue
+			}
+			result.DeletedObjects = append(result.DeletedObjects, deletedObject{
+				Key:       obj.Key,
+				VersionID: obj.VersionID,
+			})
+		}
+		xml.NewEncoder(w).Encode(result)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// More than one batch worth of versions spread over several keys.
+	var objects []ObjectInfo
+	for i := 0; i < 1500; i++ {
+		objects = append(objects, ObjectInfo{Key: []string{"a", "b", "c"}[i%3], VersionID: "v" + string(rune('A'+i%26))})
+	}
+	objects = append(objects, ObjectInfo{Key: "a", VersionID: "locked"}, ObjectInfo{Key: "d"})
+
+	objectsCh := make(chan ObjectInfo)
+	go func() {
+		defer close(objectsCh)
+		for _, obj := range objects {
+			objectsCh <- obj
+		}
+	}()
+
+	var removed, failed []RemoveObjectResult
+	for res := range clnt.RemoveObjectsWithResult(context.Background(), "bucket", objectsCh, RemoveObjectsOptions{}) {
+		if res.Err != nil {
+			failed = append(failed, res)
+			continue
+		}
+		removed = append(removed, res)
+	}
+
+	if len(requests) != 2 || len(requests[0]) != 1000 || len(requests[1]) != len(objects)-1000 {
+		t.Fatalf("Unexpected batches %d", len(requests))
+	}
+	var sent []deleteObject
+	for _, batch := range requests {
+		sent = append(sent, batch...)
+	}
+	for i, obj := range objects {
+		if sent[i].Key != obj.Key || sent[i].VersionID != obj.VersionID {
+			t.Fatalf("Object %d: expected %s (%s) in request, got %s (%s)", i, obj.Key, obj.VersionID, sent[i].Key, sent[i].VersionID)
+		}
+	}
+	if len(removed) != len(objects)-1 {
+		t.Fatalf("Expected %d removed objects, got %d", len(objects)-1, len(removed))
+	}
+	if len(failed) != 1 || failed[0].ObjectName != "a" || failed[0].ObjectVersionID != "locked" {
+		t.Fatalf("Expected a single failure for a (locked), got %+v", failed)
+	}
+	if ToErrorResponse(failed[0].Err).Code != "AccessDenied" {
+		t.Fatalf("Expected AccessDenied, got %v", failed[0].Err)
+	}
+}
+
+func TestRemoveObjectsBatchFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, `<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>`)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects := []ObjectInfo{{Key: "a", VersionID: "v1"}, {Key: "a", VersionID: "v2"}, {Key: "b", VersionID: "v1"}}
+	objectsCh := make(chan ObjectInfo, len(objects))
+	for _, obj := range objects {
+		objectsCh <- obj
+	}
+	close(objectsCh)
+
+	var got []string
+	for rErr := range clnt.RemoveObjects(context.Background(), "bucket", objectsCh, RemoveObjectsOptions{}) {
+		if ToErrorResponse(rErr.Err).Code != "AccessDenied" {
+			t.Fatalf("Expected AccessDenied, got %v", rErr.Err)
+		}
+		got = append(got, rErr.ObjectName+"/"+rErr.VersionID)
+	}
+	sort.Strings(got)
+	want := []string{"a/v1", "a/v2", "b/v1"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected errors for %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected errors for %v, got %v", want, got)
+		}
+	}
+}
+
+func TestRemoveObjectsConcurrent(t *testing.T) {
+	var (
+		mu                    sync.Mutex
+		batchSizes            []int
+		inFlight, maxInFlight int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var del deleteMultiObjects
+		if err := xml.NewDecoder(r.Body).Decode(&del); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		batchSizes = append(batchSizes, len(del.Objects))
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+
+		// Keep the request in flight so that batches overlap.
+		time.Sleep(50 * time.Millisecond)
+
+		var result deleteMultiObjectsResult
+		for _, obj := range del.Objects {
+			var i int
+			fmt.Sscanf(obj.Key, "object-%d", &i)
+			if i%1000 == 7 {
+				result.UnDeletedObjects = append(result.UnDeletedObjects, nonDeletedObject{
+					Key:     obj.Key,
+					Code:    "AccessDenied",
+					Message: "Access Denied.",
+				})
+				continue
+			}
+			result.DeletedObjects = append(result.DeletedObjects, deletedObject{Key: obj.Key})
+		}
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		xml.NewEncoder(w).Encode(result)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objectsCh := make(chan ObjectInfo)
+	go func() {
+		defer close(objectsCh)
+		for i := 0; i < 5000; i++ {
+			objectsCh <- ObjectInfo{Key: fmt.Sprintf("object-%d", i)}
+		}
+	}()
+
+	removed := make(map[string]bool)
+	var failed []string
+	for res := range clnt.RemoveObjectsWithResult(context.Background(), "bucket", objectsCh, RemoveObjectsOptions{Concurrency: 4}) {
+		if res.Err != nil {
+			if ToErrorResponse(res.Err).Code != "AccessDenied" {
+				t.Fatalf("Expected AccessDenied, got %v", res.Err)
+			}
+			failed = append(failed, res.ObjectName)
+			continue
+		}
+		removed[res.ObjectName] = true
+	}
+
+	if len(batchSizes) != 5 {
+		t.Fatalf("Expected 5 batches, got %d", len(batchSizes))
+	}
+	for _, size := range batchSizes {
+		if size != 1000 {
+			t.Fatalf("Expected batches of 1000 objects, got %v", batchSizes)
+		}
+	}
+	if maxInFlight < 2 || maxInFlight > 4 {
+		t.Fatalf("Expected between 2 and 4 concurrent requests, got %d", maxInFlight)
+	}
+	if len(removed) != 4995 {
+		t.Fatalf("Expected 4995 removed objects, got %d", len(removed))
+	}
+	sort.Strings(failed)
+	want := []string{"object-1007", "object-2007", "object-3007", "object-4007", "object-7"}
+	if fmt.Sprint(failed) != fmt.Sprint(want) {
+		t.Fatalf("Expected failures for %v, got %v", want, failed)
+	}
+}
+
+func TestRemoveObjectsContextCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	objectsCh := make(chan ObjectInfo)
+	go func() {
+		defer close(objectsCh)
+		for i := 0; i < 3000; i++ {
+			select {
+			case objectsCh <- ObjectInfo{Key: fmt.Sprintf("object-%d", i)}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	time.AfterFunc(100*time.Millisecond, cancel)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for res := range clnt.RemoveObjectsWithResult(ctx, "bucket", objectsCh, RemoveObjectsOptions{Concurrency: 2}) {
+			if res.Err == nil {
+				t.Errorf("Unexpected removal of %s", res.ObjectName)
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RemoveObjects did not stop after the context was cancelled")
+	}
+}
+
+func TestDeleteObjectVersions(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		versions = map[string]bool{
+			"a/v1": true, "a/v2": true, "a/v3": true,
+			"b/v1": true, "b/v2": true,
+		}
+		bypass string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var del deleteMultiObjects
+		if err := xml.NewDecoder(r.Body).Decode(&del); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		bypass = r.Header.Get(amzBypassGovernance)
+		var result deleteMultiObjectsResult
+		for _, obj := range del.Objects {
+			if obj.VersionID == "v3" {
+				result.UnDeletedObjects = append(result.UnDeletedObjects, nonDeletedObject{
+					Key:       obj.Key,
+					VersionID: obj.VersionID,
+					Code:      "AccessDenied",
+					Message:   "Object is WORM protected and cannot be overwritten",
+				})
+				continue
+			}
+			delete(versions, obj.Key+"/"+obj.VersionID)
+			result.DeletedObjects = append(result.DeletedObjects, deletedObject{
+				Key:       obj.Key,
+				VersionID: obj.VersionID,
+			})
+		}
+		xml.NewEncoder(w).Encode(result)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versionsCh := make(chan ObjectVersion, 4)
+	versionsCh <- ObjectVersion{Key: "a", VersionID: "v1"}
+	versionsCh <- ObjectVersion{Key: "a", VersionID: "v3"}
+	versionsCh <- ObjectVersion{Key: "b", VersionID: "v2"}
+	versionsCh <- ObjectVersion{Key: "b"}
+	close(versionsCh)
+
+	var removed, failed []string
+	for res := range clnt.DeleteObjectVersions(context.Background(), "bucket", versionsCh, DeleteObjectVersionsOptions{GovernanceBypass: true}) {
+		if res.Err != nil {
+			failed = append(failed, res.ObjectName+"/"+res.ObjectVersionID+":"+ToErrorResponse(res.Err).Code)
+			continue
+		}
+		removed = append(removed, res.ObjectName+"/"+res.ObjectVersionID)
+	}
+	sort.Strings(removed)
+	sort.Strings(failed)
+
+	if fmt.Sprint(removed) != "[a/v1 b/v2]" {
+		t.Fatalf("Expected a/v1 and b/v2 to be removed, got %v", removed)
+	}
+	if fmt.Sprint(failed) != "[a/v3:AccessDenied b/:InvalidArgument]" {
+		t.Fatalf("Unexpected failures %v", failed)
+	}
+	if bypass != "true" {
+		t.Fatalf("Expected governance bypass header, got %q", bypass)
+	}
+	var remaining []string
+	for version := range versions {
+		remaining = append(remaining, version)
+	}
+	sort.Strings(remaining)
+	if fmt.Sprint(remaining) != "[a/v2 a/v3 b/v1]" {
+		t.Fatalf("Unexpected remaining versions %v", remaining)
+	}
+}
+
+func TestRemoveIncompleteUploads(t *testing.T) {
+	now := time.Now().UTC()
+	var uploads []ObjectMultipartInfo
+	for i, initiated := range []time.Time{
+		now.Add(-72 * time.Hour),
+		now.Add(-time.Minute),
+		now.Add(-48 * time.Hour),
+		now.Add(-25 * time.Hour),
+		now,
+	} {
+		uploads = append(uploads, ObjectMultipartInfo{
+			Key:       fmt.Sprintf("object-%d", i),
+			UploadID:  fmt.Sprintf("upload-%d", i),
+			Initiated: initiated,
+		})
+	}
+	listSrv := newListMultipartUploadsServer(t, uploads, false)
+	defer listSrv.Close()
+
+	var (
+		mu      sync.Mutex
+		aborted []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			listSrv.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+		mu.Lock()
+		aborted = append(aborted, r.URL.Path+"?"+r.URL.Query().Get("uploadId"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := clnt.RemoveIncompleteUploads(context.Background(), "bucket", "", RemoveIncompleteUploadsOptions{OlderThan: 24 * time.Hour, Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("Expected 3 aborted uploads, got %d", n)
+	}
+	sort.Strings(aborted)
+	want := "[/bucket/object-0?upload-0 /bucket/object-2?upload-2 /bucket/object-3?upload-3]"
+	if fmt.Sprint(aborted) != want {
+		t.Fatalf("Expected %s to be aborted, got %v", want, aborted)
+	}
+}
+
+func TestRemoveBucketForce(t *testing.T) {
+	type version struct {
+		key, id      string
+		deleteMarker bool
+	}
+	var (
+		mu       sync.Mutex
+		versions []version
+		uploads  map[string]string // upload id => key
+		minio    bool              // Purge the bucket on x-minio-force-delete.
+		listed   int
+		deletes  int
+	)
+	reset := func() {
+		versions = nil
+		for i := 0; i < 30; i++ {
+			key := fmt.Sprintf("object-%d", i%10)
+			versions = append(versions, version{key, fmt.Sprintf("v%d", i), i >= 20})
+		}
+		versions = append(versions, version{"locked", "v0", false})
+		uploads = map[string]string{"upload-1": "object-1", "upload-2": "partial"}
+		listed, deletes = 0, 0
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodGet && q.Has("versions"):
+			listed++
+			var b strings.Builder
+			b.WriteString(`<ListVersionsResult><Name>bucket</Name><IsTruncated>false</IsTruncated>`)
+			for _, v := range versions {
+				tag := "Version"
+				if v.deleteMarker {
+					tag = "DeleteMarker"
+				}
+				fmt.Fprintf(&b, `<%s><Key>%s</Key><VersionId>%s</VersionId><LastModified>2024-01-01T00:00:00.000Z</LastModified></%s>`, tag, v.key, v.id, tag)
+			}
+			b.WriteString(`</ListVersionsResult>`)
+			io.WriteString(w, b.String())
+		case r.Method == http.MethodPost && q.Has("delete"):
+			var del deleteMultiObjects
+			if err := xml.NewDecoder(r.Body).Decode(&del); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			var result deleteMultiObjectsResult
+			for _, obj := range del.Objects {
+				if obj.Key == "locked" && r.Header.Get("X-Amz-Bypass-Governance-Retention") != "true" {
+					result.UnDeletedObjects = append(result.UnDeletedObjects, nonDeletedObject{Key: obj.Key, VersionID: obj.VersionID, Code: "AccessDenied", Message: "Object is WORM protected"})
+					continue
+				}
+				for i, v := range versions {
+					if v.key == obj.Key && v.id == obj.VersionID {
+						versions = append(versions[:i], versions[i+1:]...)
+						break
+					}
+				}
+				result.DeletedObjects = append(result.DeletedObjects, deletedObject{Key: obj.Key, VersionID: obj.VersionID})
+			}
+			xml.NewEncoder(w).Encode(result)
+		case r.Method == http.MethodGet && q.Has("uploads"):
+			var b strings.Builder
+			b.WriteString(`<ListMultipartUploadsResult><Bucket>bucket</Bucket><IsTruncated>false</IsTruncated>`)
+			for id, key := range uploads {
+				fmt.Fprintf(&b, `<Upload><Key>%s</Key><UploadId>%s</UploadId><Initiated>%s</Initiated></Upload>`, key, id, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+			}
+			b.WriteString(`</ListMultipartUploadsResult>`)
+			io.WriteString(w, b.String())
+		case r.Method == http.MethodDelete && q.Has("uploadId"):
+			delete(uploads, q.Get("uploadId"))
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodDelete && r.URL.Path == "/bucket/":
+			deletes++
+			if minio && r.Header.Get("X-Minio-Force-Delete") == "true" {
+				versions, uploads = nil, nil
+			}
+			if len(versions) > 0 || len(uploads) > 0 {
+				w.WriteHeader(http.StatusConflict)
+				io.WriteString(w, `<Error><Code>BucketNotEmpty</Code><Message>The bucket you tried to delete is not empty</Message></Error>`)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1", MaxRetries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without force the bucket is not emptied.
+	reset()
+	if err = clnt.RemoveBucketWithOptions(context.Background(), "bucket", RemoveBucketOptions{}); !errors.Is(err, ErrBucketNotEmpty) {
+		t.Fatalf("Expected ErrBucketNotEmpty, got %v", err)
+	}
+	if len(versions) != 31 || len(uploads) != 2 {
+		t.Fatalf("Expected the bucket to be untouched, got %d versions and %d uploads", len(versions), len(uploads))
+	}
+
+	// A locked object stops the removal, the failure names the object.
+	deletes = 0
+	err = clnt.RemoveBucketWithOptions(context.Background(), "bucket", RemoveBucketOptions{ForceDelete: true, Concurrency: 2})
+	if !errors.Is(err, ErrAccessDenied) || !strings.Contains(err.Error(), `locked (version "v0")`) {
+		t.Fatalf("Expected AccessDenied for the locked object, got %v", err)
+	}
+	if len(versions) != 1 || len(uploads) != 0 || deletes != 1 {
+		t.Fatalf("Expected only the locked object left, got %v, %v after %d bucket deletes", versions, uploads, deletes)
+	}
+
+	reset()
+	if err = clnt.RemoveBucketWithOptions(context.Background(), "bucket", RemoveBucketOptions{ForceDelete: true, GovernanceBypass: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 0 || len(uploads) != 0 {
+		t.Fatalf("Expected an empty bucket, got %v, %v", versions, uploads)
+	}
+	if deletes != 2 {
+		t.Fatalf("Expected the bucket delete to be retried once emptied, got %d deletes", deletes)
+	}
+
+	// MinIO purges the bucket itself, it is not emptied client-side.
+	reset()
+	minio = true
+	if err = clnt.RemoveBucketWithOptions(context.Background(), "bucket", RemoveBucketOptions{ForceDelete: true}); err != nil {
+		t.Fatal(err)
+	}
+	if listed != 0 || deletes != 1 {
+		t.Fatalf("Expected a single forced bucket delete, got %d listings and %d deletes", listed, deletes)
+	}
+}
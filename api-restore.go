@@ -140,19 +140,42 @@ func (r *RestoreRequest) SetOutputLocation(v OutputLocation) {
 	r.OutputLocation = &v
 }
 
+// RestoreObjectResult is the outcome of a restore request.
+type RestoreObjectResult struct {
+	// AlreadyRestored is true when a restored copy of the object was
+	// already available, the server answered with 200 OK and only
+	// updated its expiry. It is false when the server accepted a new
+	// restore with 202 Accepted, the object can be read once the
+	// restore completes which is reported by ObjectInfo.Restore.
+	AlreadyRestored bool
+
+	// OutputPath is the path of the results of a SELECT restore
+	// request, relative to the requested output location.
+	OutputPath string
+}
+
 // RestoreObject is a implementation of https://docs.aws.amazon.com/AmazonS3/latest/API/API_RestoreObject.html AWS S3 API
 func (c *Client) RestoreObject(ctx context.Context, bucketName, objectName, versionID string, req RestoreRequest) error {
+	_, err := c.RestoreObjectWithResult(ctx, bucketName, objectName, versionID, req)
+	return err
+}
+
+// RestoreObjectWithResult - Same as RestoreObject but reports whether
+// the restore was started or the object was already restored. A restore
+// requested while another one is still running fails with an error
+// matching ErrRestoreInProgress.
+func (c *Client) RestoreObjectWithResult(ctx context.Context, bucketName, objectName, versionID string, req RestoreRequest) (RestoreObjectResult, error) {
 	// Input validation.
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
-		return err
+		return RestoreObjectResult{}, err
 	}
 	if err := s3utils.CheckValidObjectName(objectName); err != nil {
-		return err
+		return RestoreObjectResult{}, err
 	}
 
 	restoreRequestBytes, err := xml.Marshal(req)
 	if err != nil {
-		return err
+		return RestoreObjectResult{}, err
 	}
 
 	urlValues := make(url.Values)
@@ -173,10 +196,13 @@ func (c *Client) RestoreObject(ctx context.Context, bucketName, objectName, vers
 	})
 	defer closeResponse(resp)
 	if err != nil {
-		return err
+		return RestoreObjectResult{}, err
 	}
 	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
-		return httpRespToErrorResponse(resp, bucketName, "")
+		return RestoreObjectResult{}, httpRespToErrorResponse(resp, bucketName, objectName)
 	}
-	return nil
+	return RestoreObjectResult{
+		AlreadyRestored: resp.StatusCode == http.StatusOK,
+		OutputPath:      resp.Header.Get("x-amz-restore-output-path"),
+	}, nil
 }
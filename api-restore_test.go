@@ -0,0 +1,91 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRestoreObject(t *testing.T) {
+	var restoreReq RestoreRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["restore"]; !ok || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&restoreReq); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/bucket/archived":
+			w.WriteHeader(http.StatusAccepted)
+		case "/bucket/restored":
+			w.WriteHeader(http.StatusOK)
+		case "/bucket/restoring":
+			w.WriteHeader(http.StatusConflict)
+			io.WriteString(w, `<Error><Code>RestoreAlreadyInProgress</Code><Message>Object restore is already in progress</Message></Error>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1", MaxRetries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := RestoreRequest{}
+	req.SetDays(2)
+	req.SetGlacierJobParameters(GlacierJobParameters{Tier: TierBulk})
+
+	ctx := context.Background()
+	res, err := clnt.RestoreObjectWithResult(ctx, "bucket", "archived", "", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AlreadyRestored {
+		t.Fatal("Expected an accepted restore")
+	}
+	if restoreReq.Days == nil || *restoreReq.Days != 2 || restoreReq.GlacierJobParameters == nil || restoreReq.GlacierJobParameters.Tier != TierBulk {
+		t.Fatalf("Unexpected restore request %+v", restoreReq)
+	}
+
+	res, err = clnt.RestoreObjectWithResult(ctx, "bucket", "restored", "", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.AlreadyRestored {
+		t.Fatal("Expected an already restored object")
+	}
+
+	err = clnt.RestoreObject(ctx, "bucket", "restoring", "", req)
+	if !errors.Is(err, ErrRestoreInProgress) {
+		t.Fatalf("Expected restore in progress, got %v", err)
+	}
+	if errResp := ToErrorResponse(err); errResp.StatusCode != http.StatusConflict {
+		t.Fatalf("Unexpected error response %+v", errResp)
+	}
+}
@@ -249,8 +249,8 @@ type ListMultipartUploadsResult struct {
 	CommonPrefixes []CommonPrefix
 }
 
-// initiator container for who initiated multipart upload.
-type initiator struct {
+// Initiator container for who initiated multipart upload.
+type Initiator struct {
 	ID          string
 	DisplayName string
 }
@@ -324,7 +324,7 @@ type ListObjectPartsResult struct {
 	Key      string
 	UploadID string `xml:"UploadId"`
 
-	Initiator initiator
+	Initiator Initiator
 	Owner     owner
 
 	StorageClass         string
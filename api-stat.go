@@ -26,6 +26,11 @@ import (
 
 // BucketExists verifies if bucket exists and you have permission to access it. Allows for a Context to
 // control cancellations and timeouts.
+//
+// A missing bucket is reported as (false, nil). When the existence cannot be
+// determined, for example because access is denied, the error is returned as
+// an ErrorResponse so callers can branch on its Code, "AccessDenied" means the
+// bucket may exist but is not accessible with the current credentials.
 func (c *Client) BucketExists(ctx context.Context, bucketName string) (bool, error) {
 	// Input validation.
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
@@ -44,14 +49,12 @@ func (c *Client) BucketExists(ctx context.Context, bucketName string) (bool, err
 		}
 		return false, err
 	}
-	if resp != nil {
-		resperr := httpRespToErrorResponse(resp, bucketName, "")
-		if ToErrorResponse(resperr).Code == "NoSuchBucket" {
+	if resp != nil && resp.StatusCode != http.StatusOK {
+		err = httpRespToErrorResponse(resp, bucketName, "")
+		if ToErrorResponse(err).Code == "NoSuchBucket" {
 			return false, nil
 		}
-		if resp.StatusCode != http.StatusOK {
-			return false, httpRespToErrorResponse(resp, bucketName, "")
-		}
+		return false, err
 	}
 	return true, nil
 }
@@ -122,3 +125,76 @@ func (c *Client) StatObject(ctx context.Context, bucketName, objectName string,
 
 	return ToObjectInfo(bucketName, objectName, resp.Header)
 }
+
+// StatObjectResult - container of the result of StatObjects for a key.
+type StatObjectResult struct {
+	Key        string
+	ObjectInfo ObjectInfo
+	Err        error
+}
+
+// StatObjectsOptions holds the options of StatObjects.
+type StatObjectsOptions struct {
+	StatObjectOptions
+
+	// Number of StatObject requests sent concurrently, defaults to 4.
+	Concurrency int
+}
+
+// StatObjects - Stats the objects whose names are read from keysCh with
+// up to opts.Concurrency concurrent requests. A result is sent for
+// every key in no particular order, the error of a key, such as one
+// matching ErrObjectNotFound, is set in its result. The returned
+// channel is closed once keysCh is closed and all keys are handled, or
+// once ctx is cancelled.
+func (c *Client) StatObjects(ctx context.Context, bucketName string, keysCh <-chan string, opts StatObjectsOptions) <-chan StatObjectResult {
+	resultCh := make(chan StatObjectResult, 1)
+
+	// Validate if bucket name is valid.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		defer close(resultCh)
+		resultCh <- StatObjectResult{
+			Err: err,
+		}
+		return resultCh
+	}
+	// Validate keys channel to be properly allocated.
+	if keysCh == nil {
+		defer close(resultCh)
+		resultCh <- StatObjectResult{
+			Err: errInvalidArgument("Keys channel cannot be nil"),
+		}
+		return resultCh
+	}
+
+	go func() {
+		defer close(resultCh)
+
+		// Errors are sent with the result of their key, they do not
+		// stop the group.
+		g, gctx := newWorkerGroup(ctx, opts.Concurrency)
+		for {
+			var key string
+			select {
+			case <-gctx.Done():
+				g.Wait()
+				return
+			case k, ok := <-keysCh:
+				if !ok {
+					g.Wait()
+					return
+				}
+				key = k
+			}
+			g.Go(func() error {
+				objInfo, err := c.StatObject(gctx, bucketName, key, opts.StatObjectOptions)
+				select {
+				case resultCh <- StatObjectResult{Key: key, ObjectInfo: objInfo, Err: err}:
+				case <-gctx.Done():
+				}
+				return nil
+			})
+		}
+	}()
+	return resultCh
+}
@@ -0,0 +1,345 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBucketExists(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		switch strings.Trim(r.URL.Path, "/") {
+		case "found":
+			w.WriteHeader(http.StatusOK)
+		case "missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "denied":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:     "us-east-1",
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		bucket     string
+		exists     bool
+		code       string
+		statusCode int
+	}{
+		{"found", true, "", 0},
+		{"missing", false, "", 0},
+		{"denied", false, "AccessDenied", http.StatusForbidden},
+		{"broken", false, "500 Internal Server Error", http.StatusInternalServerError},
+	}
+	for _, testCase := range testCases {
+		exists, err := clnt.BucketExists(context.Background(), testCase.bucket)
+		if exists != testCase.exists {
+			t.Errorf("%s: expected exists %v, got %v", testCase.bucket, testCase.exists, exists)
+		}
+		errResp := ToErrorResponse(err)
+		if errResp.Code != testCase.code || errResp.StatusCode != testCase.statusCode {
+			t.Errorf("%s: expected code %q and status %d, got %v", testCase.bucket, testCase.code, testCase.statusCode, err)
+		}
+		if testCase.code == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", testCase.bucket, err)
+		}
+	}
+}
+
+func TestStatObjectReplicationAndRestore(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Content-Length", "0")
+		switch strings.TrimPrefix(r.URL.Path, "/bucket/") {
+		case "replicated":
+			w.Header().Set("X-Amz-Replication-Status", "COMPLETED")
+			w.Header().Set("X-Minio-Replication-Ready", "true")
+		case "restoring":
+			w.Header().Set("X-Amz-Replication-Status", "PENDING")
+			w.Header().Set("X-Amz-Restore", `ongoing-request="true"`)
+		case "restored":
+			w.Header().Set("X-Amz-Restore", `ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"`)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		object            string
+		replicationStatus string
+		replicationReady  bool
+		restore           *RestoreInfo
+	}{
+		{"plain", "", false, nil},
+		{"replicated", "COMPLETED", true, nil},
+		{"restoring", "PENDING", false, &RestoreInfo{OngoingRestore: true}},
+		{"restored", "", false, &RestoreInfo{ExpiryTime: time.Date(2012, time.December, 21, 0, 0, 0, 0, time.UTC)}},
+	}
+	for _, testCase := range testCases {
+		st, err := clnt.StatObject(context.Background(), "bucket", testCase.object, StatObjectOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.object, err)
+		}
+		if st.ReplicationStatus != testCase.replicationStatus {
+			t.Fatalf("%s: expected replication status %q, got %q", testCase.object, testCase.replicationStatus, st.ReplicationStatus)
+		}
+		if st.ReplicationReady != testCase.replicationReady {
+			t.Fatalf("%s: expected replication ready %t, got %t", testCase.object, testCase.replicationReady, st.ReplicationReady)
+		}
+		if !reflect.DeepEqual(st.Restore, testCase.restore) {
+			t.Fatalf("%s: expected restore %+v, got %+v", testCase.object, testCase.restore, st.Restore)
+		}
+	}
+}
+
+func TestObjectInfoUserTagCount(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Content-Length", "5")
+		switch strings.TrimPrefix(r.URL.Path, "/bucket/") {
+		case "tagged":
+			w.Header().Set("X-Amz-Tagging-Count", "3")
+		case "minio-tagged":
+			w.Header().Set("X-Amz-Tagging", "env=dev&team=storage")
+		}
+		if r.Method == http.MethodGet {
+			io.WriteString(w, "hello")
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		object string
+		count  int
+	}{
+		{"tagged", 3},
+		{"minio-tagged", 2},
+		{"untagged", 0},
+	}
+	for _, testCase := range testCases {
+		st, err := clnt.StatObject(context.Background(), "bucket", testCase.object, StatObjectOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.object, err)
+		}
+		if st.UserTagCount != testCase.count {
+			t.Fatalf("%s: expected stat tag count %d, got %d", testCase.object, testCase.count, st.UserTagCount)
+		}
+
+		obj, err := clnt.GetObject(context.Background(), "bucket", testCase.object, GetObjectOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.object, err)
+		}
+		if _, err = io.ReadAll(obj); err != nil {
+			t.Fatalf("%s: %v", testCase.object, err)
+		}
+		st, err = obj.Stat()
+		obj.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.object, err)
+		}
+		if st.UserTagCount != testCase.count {
+			t.Fatalf("%s: expected get tag count %d, got %d", testCase.object, testCase.count, st.UserTagCount)
+		}
+	}
+}
+
+func TestStatObjects(t *testing.T) {
+	var active, maxActive int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			m := atomic.LoadInt32(&maxActive)
+			if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		var i int
+		if _, err := fmt.Sscanf(r.URL.Path, "/bucket/object-%d", &i); err != nil || i%7 == 0 {
+			w.Header().Set("X-Amz-Request-Id", "request-id")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(i))
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1", MaxRetries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const keys, concurrency = 500, 8
+	keysCh := make(chan string)
+	go func() {
+		defer close(keysCh)
+		for i := 0; i < keys; i++ {
+			keysCh <- fmt.Sprintf("object-%d", i)
+		}
+	}()
+
+	seen := make(map[string]bool)
+	for res := range clnt.StatObjects(context.Background(), "bucket", keysCh, StatObjectsOptions{Concurrency: concurrency}) {
+		if seen[res.Key] {
+			t.Fatalf("Duplicate result for %s", res.Key)
+		}
+		seen[res.Key] = true
+		var i int
+		if _, err = fmt.Sscanf(res.Key, "object-%d", &i); err != nil {
+			t.Fatalf("Unexpected key %q", res.Key)
+		}
+		if i%7 == 0 {
+			if !errors.Is(res.Err, ErrObjectNotFound) || ToErrorResponse(res.Err).Code != "NoSuchKey" {
+				t.Fatalf("%s: expected NoSuchKey, got %v", res.Key, res.Err)
+			}
+			continue
+		}
+		if res.Err != nil {
+			t.Fatalf("%s: unexpected error %v", res.Key, res.Err)
+		}
+		if res.ObjectInfo.Key != res.Key || res.ObjectInfo.Size != int64(i) {
+			t.Fatalf("%s: unexpected object info %+v", res.Key, res.ObjectInfo)
+		}
+	}
+	if len(seen) != keys {
+		t.Fatalf("Expected %d results, got %d", keys, len(seen))
+	}
+	if m := atomic.LoadInt32(&maxActive); m > concurrency || m < 2 {
+		t.Fatalf("Expected between 2 and %d concurrent requests, got %d", concurrency, m)
+	}
+
+	// Cancelling the context closes the results.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range clnt.StatObjects(ctx, "bucket", make(chan string), StatObjectsOptions{}) {
+		t.Fatal("Expected no results after cancel")
+	}
+}
+
+func TestUserMetadataCaseNormalization(t *testing.T) {
+	var stored http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			stored = r.Header.Clone()
+			w.Header().Set("ETag", `"etag"`)
+		case http.MethodHead:
+			for k, v := range stored {
+				if strings.HasPrefix(k, "X-Amz-Meta-") {
+					// Servers may report the names in lower case.
+					w.Header()[strings.ToLower(k)] = v
+				}
+			}
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			w.Header().Set("Content-Length", "0")
+		case http.MethodGet:
+			io.WriteString(w, `<ListBucketResult><Name>bucket</Name><IsTruncated>false</IsTruncated>`+
+				`<Contents><Key>object</Key><ETag>"etag"</ETag><Size>0</Size><UserMetadata>`+
+				`<X-Amz-Meta-MY-KEY>a</X-Amz-Meta-MY-KEY><x-amz-meta-other-key>b</x-amz-meta-other-key>`+
+				`<X-Amz-Meta-Lower>c</X-Amz-Meta-Lower><content-type>text/plain</content-type>`+
+				`</UserMetadata></Contents></ListBucketResult>`)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userMetadata := map[string]string{"MY-KEY": "a", "x-amz-meta-Other-KEY": "b", "lower": "c"}
+	if _, err = clnt.PutObject(context.Background(), "bucket", "object", strings.NewReader(""), 0, PutObjectOptions{UserMetadata: userMetadata}); err != nil {
+		t.Fatal(err)
+	}
+	st, err := clnt.StatObject(context.Background(), "bucket", "object", StatObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := StringMap{"My-Key": "a", "Other-Key": "b", "Lower": "c"}
+	if !reflect.DeepEqual(st.UserMetadata, expected) {
+		t.Fatalf("Expected %v, got %v", expected, st.UserMetadata)
+	}
+
+	var listed []ObjectInfo
+	for object := range clnt.ListObjects(context.Background(), "bucket", ListObjectsOptions{WithMetadata: true}) {
+		if object.Err != nil {
+			t.Fatal(object.Err)
+		}
+		listed = append(listed, object)
+	}
+	if len(listed) != 1 {
+		t.Fatalf("Expected 1 object, got %d", len(listed))
+	}
+	expected["Content-Type"] = "text/plain"
+	if !reflect.DeepEqual(listed[0].UserMetadata, expected) {
+		t.Fatalf("Expected %v, got %v", expected, listed[0].UserMetadata)
+	}
+
+	for key, value := range map[string]string{
+		"my-key":               "a",
+		"X-AMZ-META-OTHER-KEY": "b",
+		"x-amz-meta-lower":     "c",
+		"missing":              "",
+	} {
+		if got := st.UserMetadata.Get(key); got != value {
+			t.Fatalf("Expected %q for %q, got %q", value, key, got)
+		}
+	}
+	if got := (StringMap{"odd key": "v"}).Get("ODD KEY"); got != "v" {
+		t.Fatalf("Expected a case-insensitive match, got %q", got)
+	}
+}
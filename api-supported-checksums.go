@@ -0,0 +1,218 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+)
+
+// Prefix of the object names used to probe the checksum algorithms
+// supported by the server, each probe adds a random suffix.
+const checksumProbePrefix = ".minio-go-checksum-probe-"
+
+// Maximum time spent removing the probe object.
+const checksumProbeCleanupTimeout = 30 * time.Second
+
+// Payload uploaded when probing checksum algorithms.
+var checksumProbeData = []byte("minio-go checksum probe")
+
+// defaultSupportedChecksums is the list of checksum algorithms supported by AWS S3.
+var defaultSupportedChecksums = []ChecksumType{
+	ChecksumCRC32,
+	ChecksumCRC32C,
+	ChecksumSHA1,
+	ChecksumSHA256,
+	ChecksumCRC64NVME,
+}
+
+// supportedChecksumsCache holds the result of a successful probe and
+// the algorithms verified by the server on uploads.
+type supportedChecksumsCache struct {
+	sync.Mutex
+	probed   bool
+	types    []ChecksumType
+	verified ChecksumType
+}
+
+// observe - records the checksum algorithms the server verified for an
+// upload, servers only return the checksum headers they understand.
+func (s *supportedChecksumsCache) observe(reqHeader, respHeader http.Header) {
+	var verified ChecksumType
+	for _, checksum := range defaultSupportedChecksums {
+		value := respHeader.Get(checksum.Key())
+		if value == "" {
+			continue
+		}
+		if value == reqHeader.Get(checksum.Key()) || strings.EqualFold(reqHeader.Get("X-Amz-Trailer"), checksum.Key()) {
+			verified |= checksum
+		}
+	}
+	if verified == 0 {
+		return
+	}
+	s.Lock()
+	s.verified |= verified
+	s.Unlock()
+}
+
+// SupportedChecksumsOptions holds the options of SupportedChecksumsWithOptions.
+type SupportedChecksumsOptions struct {
+	// Probe the server by uploading a tiny, randomly named object to
+	// the bucket with each algorithm, the probe object is removed
+	// afterwards. The uploads are regular writes: they trigger bucket
+	// notifications, replication and lifecycle rules, leave versions or
+	// delete markers on versioned buckets, and the probe object cannot
+	// be removed from buckets with object lock or default retention.
+	Probe bool
+}
+
+// SupportedChecksums returns the checksum algorithms supported by the
+// server without writing to it, see SupportedChecksumsWithOptions.
+func (c *Client) SupportedChecksums(ctx context.Context, bucketName string) ([]ChecksumType, error) {
+	return c.SupportedChecksumsWithOptions(ctx, bucketName, SupportedChecksumsOptions{})
+}
+
+// SupportedChecksumsWithOptions returns the checksum algorithms
+// supported by the server.
+//
+// By default nothing is sent to the server, the algorithms it verified
+// on earlier uploads of this client are returned, or the default AWS S3
+// set of algorithms when no upload carried a checksum yet.
+//
+// With opts.Probe the server is probed once, the result, even empty, is
+// cached for the lifetime of the client. If the probe cannot be
+// completed, for example due to missing permissions, the default AWS S3
+// set of algorithms is returned along with the error and nothing is
+// cached.
+func (c *Client) SupportedChecksumsWithOptions(ctx context.Context, bucketName string, opts SupportedChecksumsOptions) ([]ChecksumType, error) {
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		return append([]ChecksumType(nil), defaultSupportedChecksums...), err
+	}
+
+	c.checksumCache.Lock()
+	probed, types, verified := c.checksumCache.probed, c.checksumCache.types, c.checksumCache.verified
+	c.checksumCache.Unlock()
+	if probed {
+		return append([]ChecksumType(nil), types...), nil
+	}
+	if !opts.Probe {
+		if verified == 0 {
+			return append([]ChecksumType(nil), defaultSupportedChecksums...), nil
+		}
+		var supported []ChecksumType
+		for _, checksum := range defaultSupportedChecksums {
+			if verified.Is(checksum) {
+				supported = append(supported, checksum)
+			}
+		}
+		return supported, nil
+	}
+
+	supported, err := c.probeChecksums(ctx, bucketName)
+	if err != nil {
+		return append([]ChecksumType(nil), defaultSupportedChecksums...), err
+	}
+
+	c.checksumCache.Lock()
+	c.checksumCache.probed, c.checksumCache.types = true, supported
+	c.checksumCache.Unlock()
+	return append([]ChecksumType(nil), supported...), nil
+}
+
+// probeChecksums uploads an uniquely named probe object with each
+// checksum algorithm and returns the algorithms the server verified.
+func (c *Client) probeChecksums(ctx context.Context, bucketName string) ([]ChecksumType, error) {
+	var (
+		objectName = checksumProbePrefix + uuid.NewString()
+		supported  []ChecksumType
+		versions   []string
+	)
+	// Remove the probe object, errors are ignored since it is only a
+	// few bytes. On versioned buckets every probed version is removed.
+	// An upload interrupted by the cancellation of ctx may still have
+	// stored the object, the removal outlives ctx within a bound.
+	defer func() {
+		if len(versions) == 0 {
+			versions = append(versions, "")
+		}
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), checksumProbeCleanupTimeout)
+		defer cancel()
+		for _, versionID := range versions {
+			c.RemoveObject(cleanupCtx, bucketName, objectName, RemoveObjectOptions{VersionID: versionID})
+		}
+	}()
+	for _, checksum := range defaultSupportedChecksums {
+		value := checksum.ChecksumBytes(checksumProbeData).Encoded()
+		header, err := c.probeChecksum(ctx, bucketName, objectName, checksum.Key(), value)
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			// Rejected by the server.
+			continue
+		}
+		if versionID := header.Get(amzVersionID); versionID != "" {
+			versions = append(versions, versionID)
+		}
+		// Servers which do not know the algorithm ignore the header,
+		// only those which verified it return the checksum back.
+		if header.Get(checksum.Key()) == value {
+			supported = append(supported, checksum)
+		}
+	}
+
+	return supported, nil
+}
+
+// probeChecksum uploads the probe object with the given checksum header
+// and returns the response headers, or nil if the server rejected the
+// checksum. Other errors are returned.
+func (c *Client) probeChecksum(ctx context.Context, bucketName, objectName, key, value string) (http.Header, error) {
+	headers := make(http.Header)
+	headers.Set(key, value)
+	resp, err := c.executeMethod(ctx, http.MethodPut, requestMetadata{
+		bucketName:       bucketName,
+		objectName:       objectName,
+		customHeader:     headers,
+		contentBody:      bytes.NewReader(checksumProbeData),
+		contentLength:    int64(len(checksumProbeData)),
+		contentMD5Base64: sumMD5Base64(checksumProbeData),
+		contentSHA256Hex: sum256Hex(checksumProbeData),
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		err = httpRespToErrorResponse(resp, bucketName, objectName)
+		switch ToErrorResponse(err).Code {
+		case "InvalidArgument", "InvalidRequest", "NotImplemented", "BadDigest", "XAmzContentChecksumMismatch":
+			return nil, nil
+		}
+		return nil, err
+	}
+	return resp.Header, nil
+}
@@ -0,0 +1,263 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+func TestSupportedChecksums(t *testing.T) {
+	var (
+		puts, deletes int32
+		mu            sync.Mutex
+		paths         = make(map[string]bool)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.URL.Path] = true
+		mu.Unlock()
+		switch r.Method {
+		case http.MethodDelete:
+			atomic.AddInt32(&deletes, 1)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case http.MethodPut:
+			atomic.AddInt32(&puts, 1)
+		}
+		io.Copy(io.Discard, r.Body)
+		if r.Header.Get(ChecksumCRC64NVME.Key()) != "" {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `<Error><Code>InvalidArgument</Code><Message>Unsupported checksum algorithm</Message></Error>`)
+			return
+		}
+		for _, checksum := range []ChecksumType{ChecksumCRC32, ChecksumCRC32C, ChecksumSHA1, ChecksumSHA256} {
+			if v := r.Header.Get(checksum.Key()); v != "" {
+				w.Header().Set(checksum.Key(), v)
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ChecksumType{ChecksumCRC32, ChecksumCRC32C, ChecksumSHA1, ChecksumSHA256}
+	got, err := clnt.SupportedChecksumsWithOptions(context.Background(), "bucket", SupportedChecksumsOptions{Probe: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	if puts != 5 || deletes != 1 {
+		t.Fatalf("Expected 5 probe uploads and 1 removal, got %d and %d", puts, deletes)
+	}
+	// A single, randomly named, probe object is used.
+	for path := range paths {
+		if len(paths) != 1 || !strings.HasPrefix(path, "/bucket/"+checksumProbePrefix) || path == "/bucket/"+checksumProbePrefix {
+			t.Fatalf("Expected a single random probe object, got %v", paths)
+		}
+	}
+
+	// The result is cached.
+	got, err = clnt.SupportedChecksumsWithOptions(context.Background(), "bucket", SupportedChecksumsOptions{Probe: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) || puts != 5 {
+		t.Fatalf("Expected cached result %v without new probes, got %v after %d probes", want, got, puts)
+	}
+}
+
+func TestSupportedChecksumsProbeFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, `<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>`)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := clnt.SupportedChecksumsWithOptions(context.Background(), "bucket", SupportedChecksumsOptions{Probe: true})
+	if ToErrorResponse(err).Code != "AccessDenied" {
+		t.Fatalf("Expected AccessDenied, got %v", err)
+	}
+	if !reflect.DeepEqual(got, defaultSupportedChecksums) {
+		t.Fatalf("Expected default checksums %v, got %v", defaultSupportedChecksums, got)
+	}
+	if clnt.checksumCache.probed {
+		t.Fatal("Failed probes should not be cached")
+	}
+}
+
+func TestSupportedChecksumsNoneSupported(t *testing.T) {
+	var puts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			atomic.AddInt32(&puts, 1)
+		}
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `<Error><Code>InvalidArgument</Code><Message>Unsupported checksum algorithm</Message></Error>`)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := clnt.SupportedChecksumsWithOptions(context.Background(), "bucket", SupportedChecksumsOptions{Probe: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 0 {
+			t.Fatalf("Expected no supported checksums, got %v", got)
+		}
+	}
+	// The empty result is cached too.
+	if n := atomic.LoadInt32(&puts); n != 5 {
+		t.Fatalf("Expected a single probe of 5 uploads, got %d", n)
+	}
+}
+
+func TestSupportedChecksumsCleanupAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		put     string
+		deleted string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		io.Copy(io.Discard, r.Body)
+		switch r.Method {
+		case http.MethodPut:
+			put = r.URL.Path
+			// The caller gives up once the probe object exists.
+			cancel()
+			w.WriteHeader(http.StatusOK)
+		case http.MethodDelete:
+			deleted = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = clnt.SupportedChecksumsWithOptions(ctx, "bucket", SupportedChecksumsOptions{Probe: true}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if put == "" || deleted != put {
+		t.Fatalf("Expected the probe object %q to be removed, got %q", put, deleted)
+	}
+}
+
+func TestSupportedChecksumsWithoutProbe(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		io.Copy(io.Discard, r.Body)
+		// Echo the checksum the upload was sent with.
+		w.Header().Set(ChecksumCRC32C.Key(), ChecksumCRC32C.ChecksumBytes([]byte("hello")).Encoded())
+		w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Creds:           credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region:          "us-east-1",
+		TrailingHeaders: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is known before the first upload.
+	got, err := clnt.SupportedChecksums(context.Background(), "bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, defaultSupportedChecksums) {
+		t.Fatalf("Expected default checksums %v, got %v", defaultSupportedChecksums, got)
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Fatalf("Expected no requests, got %d", n)
+	}
+
+	data := []byte("hello")
+	_, err = clnt.PutObject(context.Background(), "bucket", "object", bytes.NewReader(data), int64(len(data)), PutObjectOptions{
+		Checksum:             ChecksumCRC32C,
+		DisableContentSha256: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = clnt.SupportedChecksums(context.Background(), "bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []ChecksumType{ChecksumCRC32C}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("Expected only the upload request, got %d", n)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"net/url"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -42,16 +43,28 @@ import (
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/minio/minio-go/v7/pkg/s3utils"
 	"github.com/minio/minio-go/v7/pkg/signer"
+	"golang.org/x/net/http/httpguts"
 	"golang.org/x/net/publicsuffix"
 )
 
 // Client implements Amazon S3 compatible methods.
 type Client struct {
+	// Offset of the server clock from the local clock in nanoseconds,
+	// learnt from RequestTimeTooSkewed errors and added to the time of
+	// signed requests. First field for 64-bit atomic alignment.
+	clockOffset int64
+
 	//  Standard options.
 
 	// Parsed endpoint url provided by the user.
 	endpointURL *url.URL
 
+	// Primary endpoint followed by the fallback endpoints, empty
+	// when no fallback endpoints are configured.
+	endpoints []*url.URL
+	// Index of the endpoint currently used for requests.
+	endpointIndex int32
+
 	// Holds various credential providers.
 	credsProvider *credentials.Credentials
 
@@ -64,6 +77,9 @@ type Client struct {
 		appVersion string
 	}
 
+	// Additional "name/version" entries appended by AppendAppInfo.
+	appInfoLayers []string
+
 	// Indicate whether we are using https or not
 	secure bool
 
@@ -76,6 +92,7 @@ type Client struct {
 	isTraceEnabled  bool
 	traceErrorsOnly bool
 	traceOutput     io.Writer
+	traceConfig     *TraceConfig
 
 	// S3 specific accelerated endpoint.
 	s3AccelerateEndpoint string
@@ -95,6 +112,9 @@ type Client struct {
 	// lookupFn is a custom function to return URL lookup type supported by the server.
 	lookupFn func(u url.URL, bucketName string) BucketLookupType
 
+	// regionResolver is a custom function to return the region of a bucket.
+	regionResolver func(bucketName string) (string, bool)
+
 	// Factory for MD5 hash functions.
 	md5Hasher    func() md5simd.Hasher
 	sha256Hasher func() md5simd.Hasher
@@ -103,10 +123,40 @@ type Client struct {
 
 	trailingHeaderSupport bool
 	maxRetries            int
+
+	// Checksum algorithms supported by the server, verified on uploads
+	// or probed on demand.
+	checksumCache *supportedChecksumsCache
+
+	// Incomplete multipart uploads kept for resuming.
+	resumableUploads *resumableUploads
+
+	// Storage classes accepted by uploads, any class if empty.
+	storageClasses map[string]struct{}
+
+	// Allow SSE-C keys to be sent over plain HTTP.
+	allowInsecureSSEC bool
+
+	// Send x-amz-request-payer with every request.
+	requestPayer bool
+
+	// User metadata of every object uploaded by PutObject.
+	defaultUserMetadata map[string]string
+
+	// Send Expect: 100-continue with the body of PUT requests.
+	expect100Continue bool
+
+	// Largest clock offset corrected, see Options.MaxClockSkew.
+	maxClockSkew time.Duration
+
+	// Part buffers of multipart uploads.
+	uploadBuffers *partBufferPool
 }
 
 // Options for New method
 type Options struct {
+	// Credentials used to sign requests, requests are not signed
+	// when nil or set to credentials.NewAnonymous().
 	Creds        *credentials.Credentials
 	Secure       bool
 	Transport    http.RoundTripper
@@ -120,6 +170,12 @@ type Options struct {
 	// function to perform region lookups appropriately.
 	CustomRegionViaURL func(u url.URL) string
 
+	// RegionResolver is consulted before looking up the location of a
+	// bucket with a GetBucketLocation request, it returns the region
+	// and true to skip the request or false to fall back to it. Only
+	// used when Region is not set.
+	RegionResolver func(bucketName string) (string, bool)
+
 	// Provide a custom function that returns BucketLookupType based
 	// on the input URL, this is just like s3utils.IsVirtualHostSupported()
 	// function but allows users to provide their own implementation.
@@ -140,7 +196,9 @@ type Options struct {
 	BucketLookupViaURL func(u url.URL, bucketName string) BucketLookupType
 
 	// TrailingHeaders indicates server support of trailing headers.
-	// Only supported for v4 signatures.
+	// Only supported for v4 signatures. Checksums trail the unsigned
+	// payload of Secure clients, whatever the endpoint, including IP
+	// and localhost ones, plain HTTP uploads rely on signed chunks.
 	TrailingHeaders bool
 
 	// Custom hash routines. Leave nil to use standard.
@@ -150,6 +208,80 @@ type Options struct {
 	// Number of times a request is retried. Defaults to 10 retries if this option is not configured.
 	// Set to 1 to disable retries.
 	MaxRetries int
+
+	// FallbackEndpoints are tried in order when the current endpoint
+	// cannot be connected to, the last endpoint which could be reached
+	// is used for subsequent requests. Only meant for active-active
+	// deployments where all endpoints serve the same data with the
+	// same credentials, S3 error responses never cause a fail over.
+	FallbackEndpoints []string
+
+	// StorageClasses restricts the storage classes accepted by uploads,
+	// any other class is rejected before the request is sent. Leave
+	// empty to accept any class, custom backends may define their own.
+	StorageClasses []string
+
+	// AllowInsecureSSEC allows sending SSE-C keys over plain HTTP, by
+	// default such requests fail before they are sent since the key
+	// would be readable on the wire. Only meant for test environments.
+	AllowInsecureSSEC bool
+
+	// HTTPClient is used for all requests, including credential
+	// refreshes, instead of a client built from Transport. The client
+	// is copied, its transport defaults to Transport or DefaultTransport
+	// when not set and redirects are returned to the caller unless it
+	// has its own CheckRedirect.
+	HTTPClient *http.Client
+
+	// DisableDualStack resolves Amazon S3 requests to the IPv4 only
+	// s3.<region>.amazonaws.com endpoints instead of the dual-stack
+	// (IPv4 and IPv6) s3.dualstack.<region>.amazonaws.com endpoints
+	// used by default, same as calling SetS3EnableDualstack(false).
+	DisableDualStack bool
+
+	// DialContext and Resolver customize how the default transport
+	// establishes connections, for example to dial IPv6 only or to
+	// use a split-horizon DNS server. DialContext takes precedence
+	// over Resolver, both are ignored when Transport or HTTPClient
+	// provide the transport.
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+	Resolver    *net.Resolver
+
+	// RequestPayer sends x-amz-request-payer: requester with every
+	// request, which is required to access the objects of requester
+	// pays buckets. The operation options have their own RequestPayer
+	// to enable it for single requests only.
+	RequestPayer bool
+
+	// DefaultUserMetadata is added to the UserMetadata of every
+	// PutObject and FPutObject call, for example to tag all the
+	// objects uploaded by an application. The UserMetadata of a call
+	// takes precedence for the keys it sets.
+	DefaultUserMetadata map[string]string
+
+	// Expect100Continue sends Expect: 100-continue with PUT requests,
+	// the body is only sent once the server accepted the request so a
+	// rejected upload, for example for bad credentials, does not waste
+	// bandwidth. The transport waits up to its ExpectContinueTimeout
+	// for the server to answer, one second for an *http.Transport
+	// without timeout.
+	Expect100Continue bool
+
+	// MaxClockSkew is the largest difference between the local clock
+	// and the clock of the server the client makes up for. A request
+	// rejected with RequestTimeTooSkewed is retried once signed with
+	// the server time from the Date header of the response, and the
+	// offset is kept for the following requests. 24 hours if zero, a
+	// negative value disables the correction.
+	MaxClockSkew time.Duration
+
+	// MaxUploadBufferBytes bounds the memory used for the part buffers
+	// of all the multipart uploads of the client. Reading the next part
+	// of a stream waits for the buffers of uploaded parts to be released
+	// once the limit is reached, so the limit rather than NumThreads may
+	// bound the parts uploaded in parallel. A single part larger than
+	// the limit is buffered alone. No limit if zero.
+	MaxUploadBufferBytes int64
 }
 
 // Global constants.
@@ -191,7 +323,7 @@ func New(endpoint string, opts *Options) (*Client, error) {
 		clnt.overrideSignerType = credentials.SignatureV4
 		// Amazon S3 endpoints are resolved into dual-stack endpoints by default
 		// for backwards compatibility.
-		clnt.s3DualstackEnabled = true
+		clnt.s3DualstackEnabled = !opts.DisableDualStack
 	}
 
 	return clnt, nil
@@ -203,6 +335,27 @@ func (c *Client) EndpointURL() *url.URL {
 	return &endpoint
 }
 
+// activeEndpointURL returns the endpoint currently used for requests,
+// the primary endpoint unless a fallback endpoint took over.
+func (c *Client) activeEndpointURL() *url.URL {
+	if len(c.endpoints) == 0 {
+		return c.endpointURL
+	}
+	return c.endpoints[atomic.LoadInt32(&c.endpointIndex)]
+}
+
+// failoverEndpoint switches to the endpoint following the one at index
+// failed, returns false if no fallback endpoints are configured. The
+// switch is skipped when a concurrent request already moved on.
+func (c *Client) failoverEndpoint(failed int32) bool {
+	if len(c.endpoints) == 0 {
+		return false
+	}
+	next := (failed + 1) % int32(len(c.endpoints))
+	atomic.CompareAndSwapInt32(&c.endpointIndex, failed, next)
+	return true
+}
+
 // lockedRandSource provides protected rand source, implements rand.Source interface.
 type lockedRandSource struct {
 	lk  sync.Mutex
@@ -242,8 +395,11 @@ func privateNew(endpoint string, opts *Options) (*Client, error) {
 	// instantiate new Client.
 	clnt := new(Client)
 
-	// Save the credentials.
+	// Save the credentials, requests are not signed without credentials.
 	clnt.credsProvider = opts.Creds
+	if clnt.credsProvider == nil {
+		clnt.credsProvider = credentials.NewAnonymous()
+	}
 
 	// Remember whether we are using https or not
 	clnt.secure = opts.Secure
@@ -251,12 +407,45 @@ func privateNew(endpoint string, opts *Options) (*Client, error) {
 	// Save endpoint URL, user agent for future uses.
 	clnt.endpointURL = endpointURL
 
+	if len(opts.FallbackEndpoints) > 0 {
+		clnt.endpoints = append(clnt.endpoints, endpointURL)
+		for _, endpoint := range opts.FallbackEndpoints {
+			u, err := getEndpointURL(endpoint, opts.Secure)
+			if err != nil {
+				return nil, err
+			}
+			clnt.endpoints = append(clnt.endpoints, u)
+		}
+	}
+
 	transport := opts.Transport
+	if opts.HTTPClient != nil && opts.HTTPClient.Transport != nil {
+		transport = opts.HTTPClient.Transport
+	}
 	if transport == nil {
-		transport, err = DefaultTransport(opts.Secure)
+		tr, err := DefaultTransport(opts.Secure)
 		if err != nil {
 			return nil, err
 		}
+		if opts.DialContext != nil {
+			tr.DialContext = opts.DialContext
+		} else if opts.Resolver != nil {
+			tr.DialContext = (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+				Resolver:  opts.Resolver,
+			}).DialContext
+		}
+		transport = tr
+	}
+	if opts.Expect100Continue {
+		// Without timeout the transport sends the body right away.
+		if tr, ok := transport.(*http.Transport); ok && tr.ExpectContinueTimeout <= 0 {
+			tr = tr.Clone()
+			tr.ExpectContinueTimeout = time.Second
+			transport = tr
+		}
+		clnt.expect100Continue = true
 	}
 
 	clnt.httpTrace = opts.Trace
@@ -269,6 +458,17 @@ func privateNew(endpoint string, opts *Options) (*Client, error) {
 			return http.ErrUseLastResponse
 		},
 	}
+	if opts.HTTPClient != nil {
+		httpClient := *opts.HTTPClient
+		httpClient.Transport = transport
+		if httpClient.Jar == nil {
+			httpClient.Jar = jar
+		}
+		if httpClient.CheckRedirect == nil {
+			httpClient.CheckRedirect = clnt.httpClient.CheckRedirect
+		}
+		clnt.httpClient = &httpClient
+	}
 
 	// Sets custom region, if region is empty bucket location cache is used automatically.
 	if opts.Region == "" {
@@ -302,10 +502,41 @@ func privateNew(endpoint string, opts *Options) (*Client, error) {
 	// by the SDK. When Auto is specified, DNS lookup is used for Amazon/Google cloud endpoints and Path for all other endpoints.
 	clnt.lookup = opts.BucketLookup
 	clnt.lookupFn = opts.BucketLookupViaURL
+	clnt.regionResolver = opts.RegionResolver
 
 	// healthcheck is not initialized
 	clnt.healthStatus = unknown
 
+	clnt.checksumCache = &supportedChecksumsCache{}
+	clnt.resumableUploads = &resumableUploads{uploads: make(map[string]resumableUpload)}
+
+	if len(opts.StorageClasses) > 0 {
+		clnt.storageClasses = make(map[string]struct{}, len(opts.StorageClasses))
+		for _, class := range opts.StorageClasses {
+			clnt.storageClasses[class] = struct{}{}
+		}
+	}
+
+	clnt.allowInsecureSSEC = opts.AllowInsecureSSEC
+	clnt.requestPayer = opts.RequestPayer
+
+	clnt.uploadBuffers = newPartBufferPool(opts.MaxUploadBufferBytes)
+
+	clnt.maxClockSkew = opts.MaxClockSkew
+	if clnt.maxClockSkew == 0 {
+		clnt.maxClockSkew = defaultMaxClockSkew
+	}
+
+	if len(opts.DefaultUserMetadata) > 0 {
+		if err = validateUserMetadata(opts.DefaultUserMetadata); err != nil {
+			return nil, err
+		}
+		clnt.defaultUserMetadata = make(map[string]string, len(opts.DefaultUserMetadata))
+		for k, v := range opts.DefaultUserMetadata {
+			clnt.defaultUserMetadata[k] = v
+		}
+	}
+
 	clnt.maxRetries = MaxRetry
 	if opts.MaxRetries > 0 {
 		clnt.maxRetries = opts.MaxRetries
@@ -324,6 +555,22 @@ func (c *Client) SetAppInfo(appName, appVersion string) {
 	}
 }
 
+// AppendAppInfo - append an additional "name/version" entry to the user
+// agent after the one set by SetAppInfo. Libraries built on this client
+// use it to add their own identifier without replacing the application
+// details, entries appear in the order they were appended. The name and
+// version must be non-empty HTTP tokens, without spaces or '/'.
+func (c *Client) AppendAppInfo(appName, appVersion string) error {
+	if !httpguts.ValidHeaderFieldName(appName) {
+		return errInvalidArgument("Invalid app name " + strconv.Quote(appName) + " for user agent.")
+	}
+	if !httpguts.ValidHeaderFieldName(appVersion) {
+		return errInvalidArgument("Invalid app version " + strconv.Quote(appVersion) + " for user agent.")
+	}
+	c.appInfoLayers = append(c.appInfoLayers, appName+"/"+appVersion)
+	return nil
+}
+
 // TraceOn - enable HTTP tracing.
 func (c *Client) TraceOn(outputStream io.Writer) {
 	// if outputStream is nil then default to os.Stdout.
@@ -335,6 +582,7 @@ func (c *Client) TraceOn(outputStream io.Writer) {
 
 	// Enable tracing.
 	c.isTraceEnabled = true
+	c.traceConfig = nil
 }
 
 // TraceErrorsOnlyOn - same as TraceOn, but only errors will be traced.
@@ -354,6 +602,7 @@ func (c *Client) TraceOff() {
 	// Disable tracing.
 	c.isTraceEnabled = false
 	c.traceErrorsOnly = false
+	c.traceConfig = nil
 }
 
 // SetS3TransferAccelerate - turns s3 accelerated endpoint on or off for all your
@@ -428,9 +677,58 @@ func (c *Client) IsOffline() bool {
 	return atomic.LoadInt32(&c.healthStatus) == offline
 }
 
+// minioLivenessPath is the unauthenticated liveness endpoint of MinIO servers.
+const minioLivenessPath = "/minio/health/live"
+
+// EndpointHealth checks whether the endpoint is up with a single
+// request to the MinIO liveness endpoint, other S3 servers which do
+// not serve it are checked with a HEAD request on the endpoint, any
+// response other than a server error is healthy. Returns nil when
+// the endpoint is healthy.
+func (c *Client) EndpointHealth(ctx context.Context) error {
+	resp, err := c.healthRequest(ctx, http.MethodGet, minioLivenessPath)
+	if err != nil {
+		return err
+	}
+	defer closeResponse(resp)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		// Not a MinIO server, fall back to a HEAD on the endpoint.
+		closeResponse(resp)
+		if resp, err = c.healthRequest(ctx, http.MethodHead, "/"); err != nil {
+			return err
+		}
+		defer closeResponse(resp)
+		if resp.StatusCode < http.StatusInternalServerError {
+			return nil
+		}
+	}
+	return httpRespToErrorResponse(resp, "", "")
+}
+
+// healthRequest - sends an unsigned health request, bypassing the
+// offline state of the client.
+func (c *Client) healthRequest(ctx context.Context, method, urlPath string) (*http.Response, error) {
+	u := *c.activeEndpointURL()
+	u.Path = urlPath
+	u.RawQuery = ""
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setUserAgent(req)
+	return c.do(req)
+}
+
 // HealthCheck starts a healthcheck to see if endpoint is up.
 // Returns a context cancellation function, to stop the health check,
 // and an error if health check is already started.
+//
+// The endpoint is probed with EndpointHealth every hcDuration and
+// IsOnline reports the result of the last probe, failed requests
+// mark the client offline until the next successful probe.
 func (c *Client) HealthCheck(hcDuration time.Duration) (context.CancelFunc, error) {
 	if atomic.LoadInt32(&c.healthStatus) != unknown {
 		return nil, fmt.Errorf("health check is running")
@@ -438,21 +736,23 @@ func (c *Client) HealthCheck(hcDuration time.Duration) (context.CancelFunc, erro
 	if hcDuration < 1*time.Second {
 		return nil, fmt.Errorf("health check duration should be at least 1 second")
 	}
-	probeBucketName := randString(60, rand.NewSource(time.Now().UnixNano()), "probe-health-")
 	ctx, cancelFn := context.WithCancel(context.Background())
 	atomic.StoreInt32(&c.healthStatus, offline)
-	{
-		// Change to online, if we can connect.
+	probe := func() {
 		gctx, gcancel := context.WithTimeout(ctx, 3*time.Second)
-		_, err := c.getBucketLocation(gctx, probeBucketName)
+		err := c.EndpointHealth(gctx)
 		gcancel()
-		if !IsNetworkOrHostDown(err, false) {
-			switch ToErrorResponse(err).Code {
-			case "NoSuchBucket", "AccessDenied", "":
-				atomic.CompareAndSwapInt32(&c.healthStatus, offline, online)
-			}
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			atomic.CompareAndSwapInt32(&c.healthStatus, offline, online)
+		} else {
+			atomic.CompareAndSwapInt32(&c.healthStatus, online, offline)
 		}
 	}
+	// Change to online, if we can connect.
+	probe()
 
 	go func(duration time.Duration) {
 		timer := time.NewTimer(duration)
@@ -463,19 +763,7 @@ func (c *Client) HealthCheck(hcDuration time.Duration) (context.CancelFunc, erro
 				atomic.StoreInt32(&c.healthStatus, unknown)
 				return
 			case <-timer.C:
-				// Do health check the first time and ONLY if the connection is marked offline
-				if c.IsOffline() {
-					gctx, gcancel := context.WithTimeout(context.Background(), 3*time.Second)
-					_, err := c.getBucketLocation(gctx, probeBucketName)
-					gcancel()
-					if !IsNetworkOrHostDown(err, false) {
-						switch ToErrorResponse(err).Code {
-						case "NoSuchBucket", "AccessDenied", "":
-							atomic.CompareAndSwapInt32(&c.healthStatus, offline, online)
-						}
-					}
-				}
-
+				probe()
 				timer.Reset(duration)
 			}
 		}
@@ -495,6 +783,7 @@ type requestMetadata struct {
 	customHeader       http.Header
 	extraPresignHeader http.Header
 	expires            int64
+	presignTime        time.Time // defaults to the current time if not set.
 
 	// Generated by our internal code.
 	bucketLocation   string
@@ -575,7 +864,14 @@ func (c *Client) do(req *http.Request) (resp *http.Response, err error) {
 		}
 	}()
 
+	start := time.Now()
 	resp, err = c.httpClient.Do(req)
+	if c.traceConfig != nil {
+		if traceErr := c.traceRequest(req, resp, err, start); traceErr != nil && err == nil {
+			closeResponse(resp)
+			return nil, traceErr
+		}
+	}
 	if err != nil {
 		// Handle this specifically for now until future Golang versions fix this issue properly.
 		if urlErr, ok := err.(*url.Error); ok {
@@ -615,17 +911,67 @@ var successStatus = []int{
 	http.StatusPartialContent,
 }
 
+// defaultMaxClockSkew is the largest clock offset corrected by default.
+const defaultMaxClockSkew = 24 * time.Hour
+
+// now returns the current time corrected by the clock offset of the
+// server, see Options.MaxClockSkew.
+func (c *Client) now() time.Time {
+	return time.Now().Add(time.Duration(atomic.LoadInt64(&c.clockOffset)))
+}
+
+// updateClockOffset saves the offset of the server clock from the Date
+// header of a RequestTimeTooSkewed response, it returns false when the
+// request was not rejected for its time or the offset is unknown or
+// larger than allowed.
+func (c *Client) updateClockOffset(method string, res *http.Response, code string) bool {
+	if c.maxClockSkew < 0 {
+		return false
+	}
+	serverTime, err := http.ParseTime(res.Header.Get("Date"))
+	if err != nil {
+		return false
+	}
+	offset := time.Until(serverTime)
+	if code != "RequestTimeTooSkewed" {
+		// HEAD responses have no body with the error code, a request
+		// denied by a server whose clock is off by more than the 15
+		// minutes allowed by S3 is taken for a skewed request.
+		if method != http.MethodHead || res.StatusCode != http.StatusForbidden || offset.Abs() <= 15*time.Minute {
+			return false
+		}
+	}
+	if offset.Abs() > c.maxClockSkew {
+		return false
+	}
+	atomic.StoreInt64(&c.clockOffset, int64(offset))
+	return true
+}
+
+// isRegionError returns true for the errors of a request sent to the
+// wrong region, the response then names the region of the bucket.
+func isRegionError(statusCode int, code string) bool {
+	switch code {
+	case "AuthorizationHeaderMalformed", "InvalidRegion", "AccessDenied", "PermanentRedirect", "TemporaryRedirect":
+		return true
+	}
+	// Responses to HEAD requests have no body to read the code from.
+	return statusCode == http.StatusMovedPermanently || statusCode == http.StatusTemporaryRedirect
+}
+
 // executeMethod - instantiates a given method, and retries the
 // request upon any error up to maxRetries attempts in a binomially
 // delayed manner using a standard back off algorithm.
 func (c *Client) executeMethod(ctx context.Context, method string, metadata requestMetadata) (res *http.Response, err error) {
 	if c.IsOffline() {
-		return nil, errors.New(c.endpointURL.String() + " is offline.")
+		return nil, errors.New(c.activeEndpointURL().String() + " is offline.")
 	}
 
 	var retryable bool       // Indicates if request can be retried.
 	var bodySeeker io.Seeker // Extracted seeker from io.Reader.
 	reqRetry := c.maxRetries // Indicates how many times we can retry the request
+	var regionRetried bool   // Indicates the request was retried in the region of the bucket.
+	var skewRetried bool     // Indicates the request was retried with the server time.
 
 	if metadata.contentBody != nil {
 		// Check if body is seekable then it is retryable.
@@ -679,6 +1025,9 @@ func (c *Client) executeMethod(ctx context.Context, method string, metadata requ
 			}
 		}
 
+		// Remember the endpoint the request is sent to.
+		endpointIndex := atomic.LoadInt32(&c.endpointIndex)
+
 		// Instantiate a new request.
 		var req *http.Request
 		req, err = c.newRequest(ctx, method, metadata)
@@ -691,9 +1040,19 @@ func (c *Client) executeMethod(ctx context.Context, method string, metadata requ
 			return nil, err
 		}
 
+		// Expect: 100-continue is left out of the signature, proxies
+		// answering the expectation may drop it.
+		if c.expect100Continue && method == http.MethodPut && req.ContentLength != 0 {
+			req.Header.Set("Expect", "100-continue")
+		}
+
 		// Initiate the request.
 		res, err = c.do(req)
 		if err != nil {
+			// Endpoint is unreachable, retry with the next one.
+			if isDialError(err) && c.failoverEndpoint(endpointIndex) {
+				continue
+			}
 			if isRequestErrorRetryable(ctx, err) {
 				// Retry the request
 				continue
@@ -704,6 +1063,9 @@ func (c *Client) executeMethod(ctx context.Context, method string, metadata requ
 		// For any known successful http status, return quickly.
 		for _, httpStatus := range successStatus {
 			if httpStatus == res.StatusCode {
+				if method == http.MethodPut {
+					c.checksumCache.observe(req.Header, res.Header)
+				}
 				return res, nil
 			}
 		}
@@ -728,28 +1090,25 @@ func (c *Client) executeMethod(ctx context.Context, method string, metadata requ
 		res.Body = io.NopCloser(errBodySeeker)
 
 		// Bucket region if set in error response and the error
-		// code dictates invalid region, we can retry the request
+		// code dictates invalid region, or the server redirects to
+		// the region of the bucket, we can retry the request once
 		// with the new region.
 		//
 		// Additionally, we should only retry if bucketLocation and custom
 		// region is empty.
-		if c.region == "" {
-			switch errResponse.Code {
-			case "AuthorizationHeaderMalformed":
-				fallthrough
-			case "InvalidRegion":
-				fallthrough
-			case "AccessDenied":
-				if errResponse.Region == "" {
-					// Region is empty we simply return the error.
-					return res, err
-				}
-				// Region is not empty figure out a way to
-				// handle this appropriately.
+		if c.region == "" && isRegionError(res.StatusCode, errResponse.Code) {
+			if errResponse.Region == "" {
+				// Region is empty we simply return the error.
+				return res, err
+			}
+			// Region is not empty figure out a way to
+			// handle this appropriately.
+			if !regionRetried {
 				if metadata.bucketName != "" {
 					// Gather Cached location only if bucketName is present.
 					if location, cachedOk := c.bucketLocCache.Get(metadata.bucketName); cachedOk && location != errResponse.Region {
 						c.bucketLocCache.Set(metadata.bucketName, errResponse.Region)
+						regionRetried = true
 						continue // Retry.
 					}
 				} else {
@@ -758,12 +1117,19 @@ func (c *Client) executeMethod(ctx context.Context, method string, metadata requ
 						// Retry if the error response has a different region
 						// than the request we just made.
 						metadata.bucketLocation = errResponse.Region
+						regionRetried = true
 						continue // Retry
 					}
 				}
 			}
 		}
 
+		// The local clock is off, retry once with the server time.
+		if !skewRetried && c.updateClockOffset(method, res, errResponse.Code) {
+			skewRetried = true
+			continue // Retry.
+		}
+
 		// Verify if error response code is retryable.
 		if isS3CodeRetryable(errResponse.Code) {
 			continue // Retry.
@@ -793,6 +1159,12 @@ func (c *Client) newRequest(ctx context.Context, method string, metadata request
 		method = http.MethodPost
 	}
 
+	// Never send SSE-C keys in clear text.
+	if !c.allowInsecureSSEC && c.activeEndpointURL().Scheme != "https" &&
+		(hasSSECKey(metadata.customHeader) || hasSSECKey(metadata.extraPresignHeader)) {
+		return nil, errInsecureSSEC(metadata.bucketName, metadata.objectName)
+	}
+
 	location := metadata.bucketLocation
 	if location == "" {
 		if metadata.bucketName != "" {
@@ -803,7 +1175,7 @@ func (c *Client) newRequest(ctx context.Context, method string, metadata request
 			}
 		}
 		if location == "" {
-			location = getDefaultLocation(*c.endpointURL, c.region)
+			location = getDefaultLocation(*c.activeEndpointURL(), c.region)
 		}
 	}
 
@@ -811,7 +1183,7 @@ func (c *Client) newRequest(ctx context.Context, method string, metadata request
 	// We explicitly disallow MakeBucket calls to not use virtual DNS style,
 	// since the resolution may fail.
 	isMakeBucket := (metadata.objectName == "" && method == http.MethodPut && len(metadata.queryValues) == 0)
-	isVirtualHost := c.isVirtualHostStyleRequest(*c.endpointURL, metadata.bucketName) && !isMakeBucket
+	isVirtualHost := c.isVirtualHostStyleRequest(*c.activeEndpointURL(), metadata.bucketName) && !isMakeBucket
 
 	// Construct a new target URL.
 	targetURL, err := c.makeTargetURL(metadata.bucketName, metadata.objectName, location,
@@ -837,28 +1209,20 @@ func (c *Client) newRequest(ctx context.Context, method string, metadata request
 	}
 
 	var (
-		signerType      = value.SignerType
+		signerType      = c.signerType(value)
 		accessKeyID     = value.AccessKeyID
 		secretAccessKey = value.SecretAccessKey
 		sessionToken    = value.SessionToken
 	)
 
-	// Custom signer set then override the behavior.
-	if c.overrideSignerType != credentials.SignatureDefault {
-		signerType = c.overrideSignerType
-	}
-
-	// If signerType returned by credentials helper is anonymous,
-	// then do not sign regardless of signerType override.
-	if value.SignerType == credentials.SignatureAnonymous {
-		signerType = credentials.SignatureAnonymous
-	}
-
 	// Generate presign url if needed, return right here.
 	if metadata.expires != 0 && metadata.presignURL {
 		if signerType.IsAnonymous() {
 			return nil, errInvalidArgument("Presigned URLs cannot be generated with anonymous credentials.")
 		}
+		if err = isValidExpiry(time.Duration(metadata.expires)*time.Second, signerType); err != nil {
+			return nil, err
+		}
 		if metadata.extraPresignHeader != nil {
 			if signerType.IsV2() {
 				return nil, errInvalidArgument("Extra signed headers for Presign with Signature V2 is not supported.")
@@ -867,12 +1231,16 @@ func (c *Client) newRequest(ctx context.Context, method string, metadata request
 				req.Header.Set(k, v[0])
 			}
 		}
+		presignTime := metadata.presignTime
+		if presignTime.IsZero() {
+			presignTime = c.now()
+		}
 		if signerType.IsV2() {
 			// Presign URL with signature v2.
-			req = signer.PreSignV2(*req, accessKeyID, secretAccessKey, metadata.expires, isVirtualHost)
+			req = signer.PreSignV2WithTime(*req, accessKeyID, secretAccessKey, metadata.expires, isVirtualHost, presignTime)
 		} else if signerType.IsV4() {
 			// Presign URL with signature v4.
-			req = signer.PreSignV4(*req, accessKeyID, secretAccessKey, sessionToken, location, metadata.expires)
+			req = signer.PreSignV4WithTime(*req, accessKeyID, secretAccessKey, sessionToken, location, metadata.expires, presignTime)
 		}
 		return req, nil
 	}
@@ -880,6 +1248,10 @@ func (c *Client) newRequest(ctx context.Context, method string, metadata request
 	// Set 'User-Agent' header for the request.
 	c.setUserAgent(req)
 
+	if c.requestPayer {
+		req.Header.Set(amzRequestPayer, requester)
+	}
+
 	// Set all headers.
 	for k, v := range metadata.customHeader {
 		req.Header.Set(k, v[0])
@@ -915,6 +1287,9 @@ func (c *Client) newRequest(ctx context.Context, method string, metadata request
 	switch {
 	case signerType.IsV2():
 		// Add signature version '2' authorization header.
+		if req.Header.Get("Date") == "" {
+			req.Header.Set("Date", c.now().UTC().Format(http.TimeFormat))
+		}
 		req = signer.SignV2(*req, accessKeyID, secretAccessKey, isVirtualHost)
 	case metadata.streamSha256 && !c.secure:
 		if len(metadata.trailer) > 0 {
@@ -924,7 +1299,7 @@ func (c *Client) newRequest(ctx context.Context, method string, metadata request
 		// Additionally, we also look if the initialized client is secure,
 		// if yes then we don't need to perform streaming signature.
 		req = signer.StreamingSignV4(req, accessKeyID,
-			secretAccessKey, sessionToken, location, metadata.contentLength, time.Now().UTC(), c.sha256Hasher())
+			secretAccessKey, sessionToken, location, metadata.contentLength, c.now().UTC(), c.sha256Hasher())
 	default:
 		// Set sha256 sum for signature calculation only with signature version '4'.
 		shaHeader := unsignedPayload
@@ -940,26 +1315,44 @@ func (c *Client) newRequest(ctx context.Context, method string, metadata request
 		req.Header.Set("X-Amz-Content-Sha256", shaHeader)
 
 		// Add signature version '4' authorization header.
-		req = signer.SignV4Trailer(*req, accessKeyID, secretAccessKey, sessionToken, location, metadata.trailer)
+		req = signer.SignV4TrailerWithTime(*req, accessKeyID, secretAccessKey, sessionToken, location, metadata.trailer, c.now())
 	}
 
 	// Return request.
 	return req, nil
 }
 
+// signerType returns the signature used for requests signed with the
+// given credentials, anonymous credentials are never signed regardless
+// of the signature override.
+func (c *Client) signerType(value credentials.Value) credentials.SignatureType {
+	if value.SignerType == credentials.SignatureAnonymous {
+		return credentials.SignatureAnonymous
+	}
+	if c.overrideSignerType != credentials.SignatureDefault {
+		return c.overrideSignerType
+	}
+	return value.SignerType
+}
+
 // set User agent.
 func (c *Client) setUserAgent(req *http.Request) {
-	req.Header.Set("User-Agent", libraryUserAgent)
+	userAgent := libraryUserAgent
 	if c.appInfo.appName != "" && c.appInfo.appVersion != "" {
-		req.Header.Set("User-Agent", libraryUserAgent+" "+c.appInfo.appName+"/"+c.appInfo.appVersion)
+		userAgent += " " + c.appInfo.appName + "/" + c.appInfo.appVersion
 	}
+	for _, appInfo := range c.appInfoLayers {
+		userAgent += " " + appInfo
+	}
+	req.Header.Set("User-Agent", userAgent)
 }
 
 // makeTargetURL make a new target url.
 func (c *Client) makeTargetURL(bucketName, objectName, bucketLocation string, isVirtualHostStyle bool, queryValues url.Values) (*url.URL, error) {
-	host := c.endpointURL.Host
+	endpointURL := c.activeEndpointURL()
+	host := endpointURL.Host
 	// For Amazon S3 endpoint, try to fetch location based endpoint.
-	if s3utils.IsAmazonEndpoint(*c.endpointURL) {
+	if s3utils.IsAmazonEndpoint(*endpointURL) {
 		if c.s3AccelerateEndpoint != "" && bucketName != "" {
 			// http://docs.aws.amazon.com/AmazonS3/latest/dev/transfer-acceleration.html
 			// Disable transfer acceleration for non-compliant bucket names.
@@ -972,7 +1365,7 @@ func (c *Client) makeTargetURL(bucketName, objectName, bucketLocation string, is
 			host = c.s3AccelerateEndpoint
 		} else {
 			// Do not change the host if the endpoint URL is a FIPS S3 endpoint or a S3 PrivateLink interface endpoint
-			if !s3utils.IsAmazonFIPSEndpoint(*c.endpointURL) && !s3utils.IsAmazonPrivateLinkEndpoint(*c.endpointURL) {
+			if !s3utils.IsAmazonFIPSEndpoint(*endpointURL) && !s3utils.IsAmazonPrivateLinkEndpoint(*endpointURL) {
 				// Fetch new host based on the bucket location.
 				host = getS3Endpoint(bucketLocation, c.s3DualstackEnabled)
 			}
@@ -980,7 +1373,7 @@ func (c *Client) makeTargetURL(bucketName, objectName, bucketLocation string, is
 	}
 
 	// Save scheme.
-	scheme := c.endpointURL.Scheme
+	scheme := endpointURL.Scheme
 
 	// Strip port 80 and 443 so we won't send these ports in Host header.
 	// The reason is that browsers and curl automatically remove :80 and :443
@@ -1035,7 +1428,7 @@ func (c *Client) isVirtualHostStyleRequest(url url.URL, bucketName string) bool
 			return false
 		}
 		// if its auto then we fallback to default detection.
-		return s3utils.IsVirtualHostSupported(url, bucketName)
+		return isAutoVirtualHostStyle(url, bucketName)
 	}
 
 	if bucketName == "" {
@@ -1052,9 +1445,27 @@ func (c *Client) isVirtualHostStyleRequest(url url.URL, bucketName string) bool
 
 	// default to virtual only for Amazon/Google storage. In all other cases use
 	// path style requests
+	return isAutoVirtualHostStyle(url, bucketName)
+}
+
+// isAutoVirtualHostStyle returns true if BucketLookupAuto addresses the
+// bucket as a sub-domain of the endpoint. IP address and localhost
+// endpoints always use path style, an explicit BucketLookupDNS is still
+// honoured for servers serving buckets as sub-domains of localhost.
+func isAutoVirtualHostStyle(url url.URL, bucketName string) bool {
+	if isPathStyleOnlyHost(url.Hostname()) {
+		return false
+	}
 	return s3utils.IsVirtualHostSupported(url, bucketName)
 }
 
+// isPathStyleOnlyHost returns true for the hosts buckets cannot be
+// sub-domains of by default, IP addresses and localhost.
+func isPathStyleOnlyHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	return net.ParseIP(host) != nil || host == "localhost" || strings.HasSuffix(host, ".localhost")
+}
+
 // CredContext returns the context for fetching credentials
 func (c *Client) CredContext() *credentials.CredContext {
 	httpClient := c.httpClient
@@ -1063,6 +1474,6 @@ func (c *Client) CredContext() *credentials.CredContext {
 	}
 	return &credentials.CredContext{
 		Client:   httpClient,
-		Endpoint: c.endpointURL.String(),
+		Endpoint: c.activeEndpointURL().String(),
 	}
 }
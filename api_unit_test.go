@@ -18,8 +18,19 @@
 package minio
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/minio/minio-go/v7/pkg/policy"
@@ -228,3 +239,611 @@ func TestMakeTargetURL(t *testing.T) {
 		}
 	}
 }
+
+// Tests that requests fail over to a fallback endpoint when the
+// primary endpoint is unreachable.
+func TestFallbackEndpoints(t *testing.T) {
+	// Reserve an address and close it so that connections are refused.
+	down := httptest.NewServer(http.NotFoundHandler())
+	downAddr := down.Listener.Addr().String()
+	down.Close()
+
+	var (
+		requests int32
+		srvAddr  string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		// The signature must be computed for the endpoint actually used.
+		if r.Host != srvAddr || !strings.Contains(r.Header.Get("Authorization"), "SignedHeaders=host;") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if _, ok := r.URL.Query()["location"]; ok {
+			w.Write([]byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-west-2</LocationConstraint>`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	srvAddr = srv.Listener.Addr().String()
+	defer srv.Close()
+
+	// Without a region the bucket location lookup must fail over too.
+	for _, region := range []string{"us-east-1", ""} {
+		atomic.StoreInt32(&requests, 0)
+		clnt, err := New(downAddr, &Options{
+			Creds:             credentials.NewStaticV4("accessKey", "secretKey", ""),
+			Region:            region,
+			FallbackEndpoints: []string{srvAddr},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 2; i++ {
+			found, err := clnt.BucketExists(context.Background(), "bucket")
+			if err != nil {
+				t.Fatalf("Region %q, request %d: %v", region, i+1, err)
+			}
+			if !found {
+				t.Fatalf("Region %q, request %d: expected bucket to exist", region, i+1)
+			}
+		}
+		want := int32(2)
+		if region == "" {
+			// The location is looked up once and then cached.
+			want = 3
+		}
+		if n := atomic.LoadInt32(&requests); n != want {
+			t.Fatalf("Region %q: expected %d requests on the fallback endpoint, got %d", region, want, n)
+		}
+		if u := clnt.activeEndpointURL(); u.Host != srvAddr {
+			t.Fatalf("Region %q: expected the fallback endpoint to be used, got %s", region, u.Host)
+		}
+		if ep := clnt.CredContext().Endpoint; !strings.Contains(ep, srvAddr) {
+			t.Fatalf("Region %q: expected credentials context for the fallback endpoint, got %s", region, ep)
+		}
+	}
+
+	if _, err := New(downAddr, &Options{FallbackEndpoints: []string{"http://invalid/path"}}); err == nil {
+		t.Fatal("Expected an invalid fallback endpoint to be rejected")
+	}
+}
+
+// recordingTransport records the method and path of every request.
+type recordingTransport struct {
+	mu       sync.Mutex
+	requests []string
+	next     http.RoundTripper
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	r.requests = append(r.requests, req.Method+" "+req.URL.Path)
+	r.mu.Unlock()
+	return r.next.RoundTrip(req)
+}
+
+func TestCustomTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		switch {
+		case r.Method == http.MethodPut:
+			w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+		case r.Method == http.MethodGet && r.URL.Path == "/bucket/":
+			w.Write([]byte(`<ListBucketResult><Name>bucket</Name><Contents><Key>object</Key><Size>5</Size></Contents></ListBucketResult>`))
+		case r.Method == http.MethodGet:
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+			w.Write([]byte("hello"))
+		}
+	}))
+	defer srv.Close()
+
+	testCases := []struct {
+		name string
+		opts func(rt http.RoundTripper) *Options
+	}{
+		{"Transport", func(rt http.RoundTripper) *Options {
+			return &Options{Transport: rt}
+		}},
+		{"HTTPClient", func(rt http.RoundTripper) *Options {
+			return &Options{HTTPClient: &http.Client{Transport: rt, Timeout: time.Minute}}
+		}},
+	}
+	for _, testCase := range testCases {
+		rt := &recordingTransport{next: http.DefaultTransport}
+		opts := testCase.opts(rt)
+		opts.Creds = credentials.NewStaticV4("accessKey", "secretKey", "")
+		opts.Region = "us-east-1"
+		clnt, err := New(srv.Listener.Addr().String(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := context.Background()
+		if _, err = clnt.PutObject(ctx, "bucket", "object", bytes.NewReader([]byte("hello")), 5, PutObjectOptions{}); err != nil {
+			t.Fatalf("%s: %v", testCase.name, err)
+		}
+		obj, err := clnt.GetObject(ctx, "bucket", "object", GetObjectOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.name, err)
+		}
+		if _, err = io.ReadAll(obj); err != nil {
+			t.Fatalf("%s: %v", testCase.name, err)
+		}
+		obj.Close()
+		for object := range clnt.ListObjects(ctx, "bucket", ListObjectsOptions{}) {
+			if object.Err != nil {
+				t.Fatalf("%s: %v", testCase.name, object.Err)
+			}
+		}
+		// Presigning does not send any request.
+		if _, err = clnt.PresignedGetObject(ctx, "bucket", "object", time.Hour, nil); err != nil {
+			t.Fatalf("%s: %v", testCase.name, err)
+		}
+
+		expected := []string{"PUT /bucket/object", "GET /bucket/object", "GET /bucket/"}
+		rt.mu.Lock()
+		if strings.Join(rt.requests, ",") != strings.Join(expected, ",") {
+			t.Fatalf("%s: expected requests %v, got %v", testCase.name, expected, rt.requests)
+		}
+		rt.mu.Unlock()
+	}
+}
+
+func TestAppendAppInfo(t *testing.T) {
+	var userAgent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = clnt.AppendAppInfo("lib", "2.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	clnt.SetAppInfo("app", "1.0")
+	if err = clnt.AppendAppInfo("plugin", "0.1-rc1"); err != nil {
+		t.Fatal(err)
+	}
+	for _, invalid := range [][2]string{{"", "1.0"}, {"my lib", "1.0"}, {"lib", ""}, {"lib", "1.0 beta"}, {"lib/x", "1.0"}} {
+		if err = clnt.AppendAppInfo(invalid[0], invalid[1]); err == nil {
+			t.Fatalf("Expected %q %q to be rejected", invalid[0], invalid[1])
+		}
+	}
+
+	if _, err = clnt.BucketExists(context.Background(), "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	expected := libraryUserAgent + " app/1.0 lib/2.0.1 plugin/0.1-rc1"
+	if userAgent != expected {
+		t.Fatalf("Expected User-Agent %q, got %q", expected, userAgent)
+	}
+}
+
+func TestDualStackDialer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	testCases := []struct {
+		disableDualStack bool
+		expectedAddr     string
+	}{
+		{false, "bucket.s3.dualstack.us-west-2.amazonaws.com:80"},
+		{true, "bucket.s3.us-west-2.amazonaws.com:80"},
+	}
+	for _, testCase := range testCases {
+		var (
+			mu    sync.Mutex
+			dials []string
+		)
+		clnt, err := New("s3.amazonaws.com", &Options{
+			Creds:            credentials.NewStaticV4("accessKey", "secretKey", ""),
+			Region:           "us-west-2",
+			DisableDualStack: testCase.disableDualStack,
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				mu.Lock()
+				dials = append(dials, addr)
+				mu.Unlock()
+				// Connect to the test server whatever the address.
+				var d net.Dialer
+				return d.DialContext(ctx, network, srv.Listener.Addr().String())
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = clnt.BucketExists(context.Background(), "bucket"); err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		if len(dials) != 1 || dials[0] != testCase.expectedAddr {
+			t.Fatalf("Expected a single dial to %s, got %v", testCase.expectedAddr, dials)
+		}
+		mu.Unlock()
+	}
+}
+
+func TestCustomResolver(t *testing.T) {
+	var resolved atomic.Bool
+	clnt, err := New("s3.amazonaws.com", &Options{
+		Creds:      credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region:     "us-west-2",
+		MaxRetries: 1,
+		Resolver: &net.Resolver{
+			PreferGo: true,
+			Dial: func(_ context.Context, _, _ string) (net.Conn, error) {
+				resolved.Store(true)
+				return nil, errors.New("no DNS server")
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = clnt.BucketExists(context.Background(), "bucket"); err == nil {
+		t.Fatal("Expected the request to fail without a DNS server")
+	}
+	if !resolved.Load() {
+		t.Fatal("Expected the custom resolver to be used")
+	}
+}
+
+func TestRequestPayer(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		payers = make(map[string]string) // method => x-amz-request-payer
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		payers[r.Method] = r.Header.Get("X-Amz-Request-Payer")
+		mu.Unlock()
+		if r.Header.Get("X-Amz-Request-Payer") == "requester" {
+			w.Header().Set("X-Amz-Request-Charged", "requester")
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/bucket/":
+			io.WriteString(w, `<ListBucketResult><Name>bucket</Name><KeyCount>1</KeyCount><Contents><Key>object</Key><Size>5</Size></Contents></ListBucketResult>`)
+		case r.Method == http.MethodGet || r.Method == http.MethodHead:
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			w.Header().Set("Content-Length", "5")
+			if r.Method == http.MethodGet {
+				io.WriteString(w, "hello")
+			}
+		case r.Method == http.MethodPut:
+			w.Header().Set("ETag", `"etag"`)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	// Runs all operations, with their RequestPayer option set to payer,
+	// and returns whether the requester was charged for stat and put.
+	run := func(clnt *Client, payer bool) (statCharged, putCharged bool) {
+		ctx := context.Background()
+		info, err := clnt.StatObject(ctx, "bucket", "object", StatObjectOptions{RequestPayer: payer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		obj, err := clnt.GetObject(ctx, "bucket", "object", GetObjectOptions{RequestPayer: payer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = io.ReadAll(obj); err != nil {
+			t.Fatal(err)
+		}
+		obj.Close()
+		upload, err := clnt.PutObject(ctx, "bucket", "object", strings.NewReader("hello"), 5, PutObjectOptions{RequestPayer: payer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for object := range clnt.ListObjects(ctx, "bucket", ListObjectsOptions{RequestPayer: payer}) {
+			if object.Err != nil {
+				t.Fatal(object.Err)
+			}
+		}
+		if err = clnt.RemoveObject(ctx, "bucket", "object", RemoveObjectOptions{RequestPayer: payer}); err != nil {
+			t.Fatal(err)
+		}
+		return info.RequestCharged, upload.RequestCharged
+	}
+
+	testCases := []struct {
+		clientPayer, payer bool
+		expected           string
+	}{
+		{false, false, ""},
+		{false, true, "requester"},
+		{true, false, "requester"},
+	}
+	for i, testCase := range testCases {
+		clnt, err := New(srv.Listener.Addr().String(), &Options{
+			Region:       "us-east-1",
+			MaxRetries:   1,
+			RequestPayer: testCase.clientPayer,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		payers = make(map[string]string)
+		statCharged, putCharged := run(clnt, testCase.payer)
+		for _, method := range []string{http.MethodHead, http.MethodGet, http.MethodPut, http.MethodDelete} {
+			if payers[method] != testCase.expected {
+				t.Fatalf("Test %d: expected %s with request payer %q, got %q", i+1, method, testCase.expected, payers[method])
+			}
+		}
+		charged := testCase.expected != ""
+		if statCharged != charged || putCharged != charged {
+			t.Fatalf("Test %d: expected request charged %t, got %t for stat and %t for put", i+1, charged, statCharged, putCharged)
+		}
+	}
+}
+
+// countingConn counts the bytes written to a connection.
+type countingConn struct {
+	net.Conn
+	written *atomic.Int64
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.written.Add(int64(n))
+	return n, err
+}
+
+func TestExpect100Continue(t *testing.T) {
+	var expect atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expect.Store(r.Header.Get("Expect"))
+		// Reject the upload without reading the body.
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, `<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>`)
+	}))
+	defer srv.Close()
+
+	var written atomic.Int64
+	dialer := &net.Dialer{}
+	data := bytes.Repeat([]byte("a"), 4<<20)
+	for _, expect100Continue := range []bool{true, false} {
+		// The transport sends the body right away without ExpectContinueTimeout.
+		clnt, err := New(srv.Listener.Addr().String(), &Options{
+			Region:     "us-east-1",
+			MaxRetries: 1,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+					conn, err := dialer.DialContext(ctx, network, addr)
+					if err != nil {
+						return nil, err
+					}
+					return &countingConn{Conn: conn, written: &written}, nil
+				},
+			},
+			Expect100Continue: expect100Continue,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		written.Store(0)
+		_, err = clnt.PutObject(context.Background(), "bucket", "object", bytes.NewReader(data), int64(len(data)), PutObjectOptions{})
+		if ToErrorResponse(err).Code != "AccessDenied" {
+			t.Fatalf("Expected AccessDenied, got %v", err)
+		}
+		if !expect100Continue {
+			if got := expect.Load(); got != "" {
+				t.Fatalf("Expected no Expect header, got %q", got)
+			}
+			continue
+		}
+		if got := expect.Load(); got != "100-continue" {
+			t.Fatalf("Expected Expect: 100-continue, got %q", got)
+		}
+		if n := written.Load(); n >= 64<<10 {
+			t.Fatalf("Expected the body not to be sent, the client wrote %d bytes", n)
+		}
+	}
+}
+
+func TestRegionRedirect(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		region   = "eu-west-1"
+		pingPong bool
+		requests []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Query().Has("location") {
+			io.WriteString(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`)
+			return
+		}
+		requests = append(requests, r.Method)
+		if pingPong {
+			region = map[string]string{"ap-south-1": "us-west-2", "us-west-2": "ap-south-1"}[region]
+		}
+		if !strings.Contains(r.Header.Get("Authorization"), "/"+region+"/s3/") {
+			w.Header().Set("x-amz-bucket-region", region)
+			w.WriteHeader(http.StatusMovedPermanently)
+			if r.Method != http.MethodHead {
+				io.WriteString(w, `<Error><Code>PermanentRedirect</Code><Message>The bucket you are attempting to access must be addressed using the specified endpoint.</Message></Error>`)
+			}
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", "5")
+		io.WriteString(w, "hello")
+	}))
+	defer srv.Close()
+
+	newClient := func() *Client {
+		clnt, err := New(srv.Listener.Addr().String(), &Options{
+			Creds:      credentials.NewStaticV4("access", "secret", ""),
+			MaxRetries: 3,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return clnt
+	}
+
+	clnt := newClient()
+	obj, err := clnt.GetObject(context.Background(), "bucket", "object", GetObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello" {
+		t.Fatalf("Expected hello, got %q", data)
+	}
+	if location, _ := clnt.bucketLocCache.Get("bucket"); location != "eu-west-1" {
+		t.Fatalf("Expected the bucket region to be cached, got %q", location)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected a single retry, got %v", requests)
+	}
+
+	// HEAD responses only carry the region in the header.
+	requests = nil
+	clnt = newClient()
+	if _, err = clnt.StatObject(context.Background(), "bucket", "object", StatObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected a single retry, got %v", requests)
+	}
+
+	// A server redirecting again is not followed a second time.
+	requests = nil
+	clnt = newClient()
+	clnt.bucketLocCache.Set("bucket", "us-west-2")
+	mu.Lock()
+	region, pingPong = "us-west-2", true
+	mu.Unlock()
+	_, err = clnt.StatObject(context.Background(), "bucket", "object", StatObjectOptions{})
+	if ToErrorResponse(err).StatusCode != http.StatusMovedPermanently {
+		t.Fatalf("Expected the redirect error, got %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected a single retry, got %v", requests)
+	}
+}
+
+func TestClockSkewRetry(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		// The server clock is two hours ahead.
+		serverTime := time.Now().Add(2 * time.Hour).UTC()
+		w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+		requestTime, err := time.Parse(iso8601DateFormat, r.Header.Get("X-Amz-Date"))
+		if err != nil || serverTime.Sub(requestTime).Abs() > 15*time.Minute {
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `<Error><Code>RequestTimeTooSkewed</Code><Message>The difference between the request time and the server's time is too large.</Message></Error>`)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", serverTime.Format(http.TimeFormat))
+		switch {
+		case r.URL.Query().Has("tagging"):
+			io.WriteString(w, `<Tagging><TagSet></TagSet></Tagging>`)
+		case r.URL.Query().Has("location"):
+			io.WriteString(w, `<LocationConstraint>us-east-1</LocationConstraint>`)
+		}
+	}))
+	defer srv.Close()
+
+	newClient := func(region string, maxClockSkew time.Duration) *Client {
+		clnt, err := New(srv.Listener.Addr().String(), &Options{
+			Region:       region,
+			Creds:        credentials.NewStaticV4("access", "secret", ""),
+			MaxRetries:   2,
+			MaxClockSkew: maxClockSkew,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return clnt
+	}
+
+	clnt := newClient("us-east-1", 0)
+	if _, err := clnt.StatObject(context.Background(), "bucket", "object", StatObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Fatalf("Expected a single retry, got %d requests", requests)
+	}
+	// The offset is kept for the following requests.
+	requests = 0
+	if _, err := clnt.StatObject(context.Background(), "bucket", "object", StatObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Fatalf("Expected no retry, got %d requests", requests)
+	}
+
+	// The error code is read from the body of other requests.
+	requests = 0
+	clnt = newClient("us-east-1", 0)
+	if _, err := clnt.GetObjectTagging(context.Background(), "bucket", "object", GetObjectTaggingOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Fatalf("Expected a single retry, got %d requests", requests)
+	}
+
+	// Without a region the bucket location request is retried, the
+	// following request is then signed with the server time.
+	requests = 0
+	clnt = newClient("", 0)
+	if _, err := clnt.StatObject(context.Background(), "bucket", "object", StatObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Fatalf("Expected a single retry of the location request, got %d requests", requests)
+	}
+
+	// Presigned POST policies are dated with the server time too.
+	policy := NewPostPolicy()
+	policy.SetBucket("bucket")
+	policy.SetKey("object")
+	policy.SetExpires(time.Now().Add(time.Hour))
+	_, formData, err := clnt.PresignedPostPolicy(context.Background(), policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyTime, err := time.Parse(iso8601DateFormat, formData["x-amz-date"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Until(policyTime); d < time.Hour {
+		t.Fatalf("Expected the policy to be dated with the server time, got %v", policyTime)
+	}
+
+	for _, maxClockSkew := range []time.Duration{-1, time.Hour} {
+		clnt = newClient("us-east-1", maxClockSkew)
+		_, err := clnt.GetObjectTagging(context.Background(), "bucket", "object", GetObjectTaggingOptions{})
+		if ToErrorResponse(err).Code != "RequestTimeTooSkewed" {
+			t.Fatalf("Expected RequestTimeTooSkewed with MaxClockSkew %v, got %v", maxClockSkew, err)
+		}
+	}
+}
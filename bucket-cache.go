@@ -19,11 +19,13 @@ package minio
 
 import (
 	"context"
+	"errors"
 	"net"
 	"net/http"
 	"net/url"
 	"path"
 	"sync"
+	"sync/atomic"
 
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/minio/minio-go/v7/pkg/s3utils"
@@ -39,6 +41,17 @@ type bucketLocationCache struct {
 
 	// items holds the cached bucket locations.
 	items map[string]string
+
+	// inflight holds the lookups in progress, concurrent callers
+	// for the same bucket wait for a single request.
+	inflight map[string]*bucketLocationCall
+}
+
+// bucketLocationCall - a bucket location lookup in progress.
+type bucketLocationCall struct {
+	done     chan struct{}
+	location string
+	err      error
 }
 
 // newBucketLocationCache - Provides a new bucket location cache to be
@@ -71,8 +84,52 @@ func (r *bucketLocationCache) Delete(bucketName string) {
 	delete(r.items, bucketName)
 }
 
+// lookup - Returns the cached location of the bucket, if not cached fetch
+// is called once for all concurrent callers and a successful result is
+// persisted into cache. Callers waiting on a lookup stop when ctx is done,
+// and fetch again themselves when the lookup was cancelled by its caller.
+func (r *bucketLocationCache) lookup(ctx context.Context, bucketName string, fetch func() (string, error)) (string, error) {
+	r.Lock()
+	if location, ok := r.items[bucketName]; ok {
+		r.Unlock()
+		return location, nil
+	}
+	if call, ok := r.inflight[bucketName]; ok {
+		r.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		// The context of another caller is not ours, retry the lookup.
+		if errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded) {
+			return r.lookup(ctx, bucketName, fetch)
+		}
+		return call.location, call.err
+	}
+	if r.inflight == nil {
+		r.inflight = make(map[string]*bucketLocationCall)
+	}
+	call := &bucketLocationCall{done: make(chan struct{})}
+	r.inflight[bucketName] = call
+	r.Unlock()
+
+	call.location, call.err = fetch()
+
+	r.Lock()
+	if call.err == nil {
+		r.items[bucketName] = call.location
+	}
+	delete(r.inflight, bucketName)
+	r.Unlock()
+	close(call.done)
+	return call.location, call.err
+}
+
 // GetBucketLocation - get location for the bucket name from location cache, if not
-// fetch freshly by making a new request.
+// fetch freshly by making a new request. Returned locations are normalized, an
+// empty location is 'us-east-1' and 'EU' is 'eu-west-1'. Concurrent callers for
+// the same bucket share a single request.
 func (c *Client) GetBucketLocation(ctx context.Context, bucketName string) (string, error) {
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
 		return "", err
@@ -92,28 +149,51 @@ func (c *Client) getBucketLocation(ctx context.Context, bucketName string) (stri
 		return c.region, nil
 	}
 
-	if location, ok := c.bucketLocCache.Get(bucketName); ok {
-		return location, nil
+	// Region resolved by the caller then no need to fetch bucket location.
+	if c.regionResolver != nil {
+		if region, ok := c.regionResolver(bucketName); ok && region != "" {
+			return region, nil
+		}
 	}
 
-	// Initialize a new request.
-	req, err := c.getBucketLocationRequest(ctx, bucketName)
-	if err != nil {
-		return "", err
-	}
+	return c.bucketLocCache.lookup(ctx, bucketName, func() (string, error) {
+		return c.fetchBucketLocation(ctx, bucketName)
+	})
+}
 
-	// Initiate the request.
-	resp, err := c.do(req)
-	defer closeResponse(resp)
-	if err != nil {
-		return "", err
-	}
-	location, err := processBucketLocationResponse(resp, bucketName)
-	if err != nil {
-		return "", err
+// fetchBucketLocation - sends the getBucketLocation request, failing over
+// to the next endpoint as long as the current one is unreachable and
+// retrying once with the server time when the local clock is off.
+func (c *Client) fetchBucketLocation(ctx context.Context, bucketName string) (string, error) {
+	var skewRetried bool
+	for attempt := 0; ; attempt++ {
+		// Remember the endpoint the request is sent to.
+		endpointIndex := atomic.LoadInt32(&c.endpointIndex)
+
+		// Initialize a new request.
+		req, err := c.getBucketLocationRequest(ctx, bucketName)
+		if err != nil {
+			return "", err
+		}
+
+		// Initiate the request.
+		resp, err := c.do(req)
+		if err != nil {
+			closeResponse(resp)
+			// Endpoint is unreachable, try the next one.
+			if isDialError(err) && attempt < len(c.endpoints)-1 && c.failoverEndpoint(endpointIndex) {
+				continue
+			}
+			return "", err
+		}
+		location, err := processBucketLocationResponse(resp, bucketName)
+		closeResponse(resp)
+		if err != nil && !skewRetried && c.updateClockOffset(http.MethodGet, resp, ToErrorResponse(err).Code) {
+			skewRetried = true
+			continue
+		}
+		return location, err
 	}
-	c.bucketLocCache.Set(bucketName, location)
-	return location, nil
 }
 
 // processes the getBucketLocation http response from the server.
@@ -178,7 +258,7 @@ func (c *Client) getBucketLocationRequest(ctx context.Context, bucketName string
 	urlValues.Set("location", "")
 
 	// Set get bucket location always as path style.
-	targetURL := *c.endpointURL
+	targetURL := *c.activeEndpointURL()
 
 	// as it works in makeTargetURL method from api.go file
 	if h, p, err := net.SplitHostPort(targetURL.Host); err == nil {
@@ -195,7 +275,7 @@ func (c *Client) getBucketLocationRequest(ctx context.Context, bucketName string
 	var urlStr string
 
 	if isVirtualStyle {
-		urlStr = c.endpointURL.Scheme + "://" + bucketName + "." + targetURL.Host + "/?location"
+		urlStr = targetURL.Scheme + "://" + bucketName + "." + targetURL.Host + "/?location"
 	} else {
 		targetURL.Path = path.Join(bucketName, "") + "/"
 		targetURL.RawQuery = urlValues.Encode()
@@ -240,6 +320,7 @@ func (c *Client) getBucketLocationRequest(ctx context.Context, bucketName string
 	}
 
 	if signerType.IsV2() {
+		req.Header.Set("Date", c.now().UTC().Format(http.TimeFormat))
 		req = signer.SignV2(*req, accessKeyID, secretAccessKey, isVirtualStyle)
 		return req, nil
 	}
@@ -251,6 +332,6 @@ func (c *Client) getBucketLocationRequest(ctx context.Context, bucketName string
 	}
 
 	req.Header.Set("X-Amz-Content-Sha256", contentSha256)
-	req = signer.SignV4(*req, accessKeyID, secretAccessKey, sessionToken, "us-east-1")
+	req = signer.SignV4TrailerWithTime(*req, accessKeyID, secretAccessKey, sessionToken, "us-east-1", nil, c.now())
 	return req, nil
 }
@@ -21,12 +21,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/xml"
+	"errors"
 	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"path"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/minio/minio-go/v7/pkg/signer"
@@ -358,3 +364,353 @@ func TestProcessBucketLocationResponse(t *testing.T) {
 		}
 	}
 }
+
+func TestGetBucketLocationCached(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+		<-release
+		io.WriteString(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">EU</LocationConstraint>`)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	locations := make([]string, 8)
+	errs := make([]error, len(locations))
+	for i := range locations {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			locations[i], errs[i] = clnt.GetBucketLocation(context.Background(), "bucket")
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for i := range locations {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if locations[i] != "eu-west-1" {
+			t.Fatalf("Expected eu-west-1, got %s", locations[i])
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("Expected concurrent callers to share 1 request, got %d", n)
+	}
+
+	if location, ok := clnt.bucketLocCache.Get("bucket"); !ok || location != "eu-west-1" {
+		t.Fatalf("Expected cached location eu-west-1, got %q", location)
+	}
+	location, err := clnt.GetBucketLocation(context.Background(), "bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location != "eu-west-1" {
+		t.Fatalf("Expected eu-west-1, got %s", location)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("Expected cached location to be used, got %d requests", n)
+	}
+}
+
+func TestGetBucketLocationCancelledLeader(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Stall the first request until its caller gives up.
+		if atomic.AddInt32(&requests, 1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		io.WriteString(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-west-2</LocationConstraint>`)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{MaxRetries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := clnt.GetBucketLocation(leaderCtx, "bucket")
+		leaderErr <- err
+	}()
+	for atomic.LoadInt32(&requests) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A waiter gives up with its own context.
+	waiterCtx, cancelWaiter := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelWaiter()
+	if _, err = clnt.GetBucketLocation(waiterCtx, "bucket"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the waiter to stop on its deadline, got %v", err)
+	}
+
+	// A live waiter is not handed the cancellation of the leader.
+	type result struct {
+		location string
+		err      error
+	}
+	waiter := make(chan result, 1)
+	go func() {
+		location, err := clnt.GetBucketLocation(context.Background(), "bucket")
+		waiter <- result{location, err}
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancelLeader()
+	if err = <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the leader to be cancelled, got %v", err)
+	}
+	res := <-waiter
+	if res.err != nil {
+		t.Fatalf("Expected the waiter to fetch the location, got %v", res.err)
+	}
+	if res.location != "us-west-2" {
+		t.Fatalf("Expected us-west-2, got %s", res.location)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Fatalf("Expected the waiter to send a second request, got %d", n)
+	}
+}
+
+// stubTransport answers every request with 200 OK and records it,
+// location requests get us-west-2.
+type stubTransport struct {
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+	var body []byte
+	if _, ok := req.URL.Query()["location"]; ok {
+		body = []byte(`<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-west-2</LocationConstraint>`)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestRegionResolver(t *testing.T) {
+	tr := &stubTransport{}
+	clnt, err := New("s3.amazonaws.com", &Options{
+		Creds:     credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Secure:    true,
+		Transport: tr,
+		RegionResolver: func(bucketName string) (string, bool) {
+			if bucketName == "bucket" {
+				return "eu-central-1", true
+			}
+			return "", false
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = clnt.BucketExists(context.Background(), "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.requests) != 1 {
+		t.Fatalf("Expected the resolver to skip the location request, got %d requests", len(tr.requests))
+	}
+	req := tr.requests[0]
+	if req.Method != http.MethodHead || req.URL.Host != "bucket.s3.dualstack.eu-central-1.amazonaws.com" {
+		t.Fatalf("Unexpected request %s %s", req.Method, req.URL)
+	}
+	if auth := req.Header.Get("Authorization"); !bytes.Contains([]byte(auth), []byte("/eu-central-1/s3/aws4_request")) {
+		t.Fatalf("Expected request signed for eu-central-1, got %s", auth)
+	}
+	if _, ok := clnt.bucketLocCache.Get("bucket"); ok {
+		t.Fatal("Expected resolved region not to be cached")
+	}
+
+	// Buckets unknown to the resolver fall back to the location request.
+	tr.requests = nil
+	if _, err = clnt.BucketExists(context.Background(), "other"); err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.requests) != 2 {
+		t.Fatalf("Expected a location request, got %d requests", len(tr.requests))
+	}
+	if _, ok := tr.requests[0].URL.Query()["location"]; !ok {
+		t.Fatalf("Expected a location request, got %s", tr.requests[0].URL)
+	}
+}
+
+func TestBucketLookupPath(t *testing.T) {
+	testCases := []struct {
+		lookup       BucketLookupType
+		bucketName   string
+		expectedHost string
+		expectedPath string
+	}{
+		{BucketLookupAuto, "bucket", "bucket.s3.dualstack.us-east-1.amazonaws.com", "/"},
+		// Buckets with dots cannot use virtual host style over TLS.
+		{BucketLookupAuto, "my.bucket", "s3.dualstack.us-east-1.amazonaws.com", "/my.bucket/"},
+		{BucketLookupPath, "bucket", "s3.dualstack.us-east-1.amazonaws.com", "/bucket/"},
+	}
+	for i, testCase := range testCases {
+		tr := &stubTransport{}
+		clnt, err := New("s3.amazonaws.com", &Options{
+			Creds:        credentials.NewStaticV4("accessKey", "secretKey", ""),
+			Secure:       true,
+			Transport:    tr,
+			Region:       "us-east-1",
+			BucketLookup: testCase.lookup,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = clnt.BucketExists(context.Background(), testCase.bucketName); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if len(tr.requests) != 1 {
+			t.Fatalf("Test %d: expected 1 request, got %d", i+1, len(tr.requests))
+		}
+		u := tr.requests[0].URL
+		if u.Host != testCase.expectedHost || u.Path != testCase.expectedPath {
+			t.Fatalf("Test %d: expected %s%s, got %s%s", i+1, testCase.expectedHost, testCase.expectedPath, u.Host, u.Path)
+		}
+	}
+}
+
+func TestBucketLookupStyle(t *testing.T) {
+	testCases := []struct {
+		endpoint     string
+		secure       bool
+		lookup       BucketLookupType
+		bucketName   string
+		expectedHost string
+		expectedPath string
+	}{
+		// IP endpoints use path style by default.
+		{"127.0.0.1:9000", false, BucketLookupAuto, "bucket", "127.0.0.1:9000", "/bucket/"},
+		{"[::1]:9000", true, BucketLookupAuto, "bucket", "[::1]:9000", "/bucket/"},
+		// So do localhost endpoints, with or without TLS.
+		{"localhost:9000", true, BucketLookupAuto, "bucket", "localhost:9000", "/bucket/"},
+		{"localhost:9000", false, BucketLookupAuto, "bucket", "localhost:9000", "/bucket/"},
+		{"minio.localhost:9000", true, BucketLookupAuto, "bucket", "minio.localhost:9000", "/bucket/"},
+		// An explicit DNS lookup is honoured, for MINIO_DOMAIN=localhost.
+		{"localhost:9000", false, BucketLookupDNS, "bucket", "bucket.localhost:9000", "/"},
+		// Other host:port endpoints follow the lookup type.
+		{"minio.example.com:9000", true, BucketLookupAuto, "bucket", "minio.example.com:9000", "/bucket/"},
+		{"minio.example.com:9000", true, BucketLookupDNS, "bucket", "bucket.minio.example.com:9000", "/"},
+		// Custom endpoints default to path style.
+		{"minio.example.com", true, BucketLookupAuto, "bucket", "minio.example.com", "/bucket/"},
+		{"minio.example.com", true, BucketLookupDNS, "bucket", "bucket.minio.example.com", "/"},
+		// Buckets with dots cannot use virtual host style over TLS.
+		{"s3.amazonaws.com", true, BucketLookupAuto, "my.bucket", "s3.dualstack.us-east-1.amazonaws.com", "/my.bucket/"},
+		{"s3.amazonaws.com", false, BucketLookupAuto, "my.bucket", "my.bucket.s3.dualstack.us-east-1.amazonaws.com", "/"},
+		{"s3.amazonaws.com", true, BucketLookupPath, "bucket", "s3.dualstack.us-east-1.amazonaws.com", "/bucket/"},
+		{"s3.amazonaws.com", true, BucketLookupDNS, "bucket", "bucket.s3.dualstack.us-east-1.amazonaws.com", "/"},
+	}
+	for i, testCase := range testCases {
+		tr := &stubTransport{}
+		clnt, err := New(testCase.endpoint, &Options{
+			Creds:        credentials.NewStaticV4("accessKey", "secretKey", ""),
+			Secure:       testCase.secure,
+			Transport:    tr,
+			Region:       "us-east-1",
+			BucketLookup: testCase.lookup,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = clnt.BucketExists(context.Background(), testCase.bucketName); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if len(tr.requests) != 1 {
+			t.Fatalf("Test %d: expected 1 request, got %d", i+1, len(tr.requests))
+		}
+		u := tr.requests[0].URL
+		if u.Host != testCase.expectedHost || u.Path != testCase.expectedPath {
+			t.Fatalf("Test %d: expected %s%s, got %s%s", i+1, testCase.expectedHost, testCase.expectedPath, u.Host, u.Path)
+		}
+		if expected := map[bool]string{true: "https", false: "http"}[testCase.secure]; u.Scheme != expected {
+			t.Fatalf("Test %d: expected scheme %s, got %s", i+1, expected, u.Scheme)
+		}
+	}
+}
+
+func TestLocalhostTrailingHeaders(t *testing.T) {
+	type request struct {
+		host, path, contentSha256, trailer string
+	}
+	var (
+		mu   sync.Mutex
+		reqs []request
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		reqs = append(reqs, request{r.Host, r.URL.Path, r.Header.Get("X-Amz-Content-Sha256"), r.Header.Get("X-Amz-Trailer")})
+		mu.Unlock()
+		w.Header().Set("ETag", `"etag"`)
+	})
+	tlsSrv := httptest.NewTLSServer(handler)
+	defer tlsSrv.Close()
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	// The test certificate does not cover localhost.
+	tlsTransport := tlsSrv.Client().Transport.(*http.Transport).Clone()
+	tlsTransport.TLSClientConfig.ServerName = "example.com"
+
+	testCases := []struct {
+		srv           *httptest.Server
+		secure        bool
+		transport     http.RoundTripper
+		contentSha256 string
+		trailer       string
+	}{
+		// Over TLS the payload is unsigned and the checksum trails it.
+		{tlsSrv, true, tlsTransport, unsignedPayloadTrailer, "x-amz-checksum-crc32c"},
+		// Over plain HTTP signed chunks protect the payload instead.
+		{srv, false, nil, "STREAMING-AWS4-HMAC-SHA256-PAYLOAD", ""},
+	}
+	for i, testCase := range testCases {
+		_, port, _ := net.SplitHostPort(testCase.srv.Listener.Addr().String())
+		endpoint := "localhost:" + port
+		clnt, err := New(endpoint, &Options{
+			Creds:           credentials.NewStaticV4("accessKey", "secretKey", ""),
+			Secure:          testCase.secure,
+			Transport:       testCase.transport,
+			Region:          "us-east-1",
+			TrailingHeaders: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		reqs = nil
+		mu.Unlock()
+		if _, err = clnt.PutObject(context.Background(), "bucket", "object", bytes.NewReader([]byte("hello")), 5, PutObjectOptions{}); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		mu.Lock()
+		got := reqs
+		mu.Unlock()
+		expected := []request{{endpoint, "/bucket/object", testCase.contentSha256, testCase.trailer}}
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("Test %d: expected %+v, got %+v", i+1, expected, got)
+		}
+	}
+}
@@ -97,6 +97,20 @@ const (
 	amzReplicationStatus = "X-Amz-Replication-Status"
 	amzDeleteMarker      = "X-Amz-Delete-Marker"
 
+	// Requester pays headers, the request payer is always "requester".
+	amzRequestPayer   = "X-Amz-Request-Payer"
+	amzRequestCharged = "X-Amz-Request-Charged"
+	requester         = "requester"
+
+	// Asks listings for object attributes left out by default.
+	amzOptionalObjectAttributes = "X-Amz-Optional-Object-Attributes"
+
+	// Logical object size headers, set when the stored size differs
+	// from the size of the object before encryption or compression.
+	amzObjectSize                   = "X-Amz-Object-Size"
+	amzMetaUnencryptedContentLength = "X-Amz-Meta-X-Amz-Unencrypted-Content-Length"
+	minIOActualObjectSize           = "X-Minio-Actual-Object-Size"
+
 	// Object legal hold header
 	amzLegalHoldHeader = "X-Amz-Object-Lock-Legal-Hold"
 
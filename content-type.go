@@ -0,0 +1,96 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Content types registered with AddContentTypeMapping, by lower case
+// extension including the leading dot.
+var (
+	contentTypesMu sync.RWMutex
+	contentTypes   = make(map[string]string)
+)
+
+// AddContentTypeMapping registers the Content-Type of files and objects
+// with the extension ext, such as ".parquet", which takes precedence
+// over the types known to the mime package. The extension is matched
+// case insensitively, an empty contentType removes the mapping.
+func AddContentTypeMapping(ext, contentType string) {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	contentTypesMu.Lock()
+	defer contentTypesMu.Unlock()
+	if contentType == "" {
+		delete(contentTypes, ext)
+		return
+	}
+	contentTypes[ext] = contentType
+}
+
+// contentTypeByExtension returns the Content-Type of the extension of
+// name, or "" if it is unknown.
+func contentTypeByExtension(name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	if ext == "" {
+		return ""
+	}
+	contentTypesMu.RLock()
+	contentType, ok := contentTypes[ext]
+	contentTypesMu.RUnlock()
+	if ok {
+		return contentType
+	}
+	return mime.TypeByExtension(ext)
+}
+
+// sniffContentType detects the Content-Type from the first 512 bytes of
+// reader with http.DetectContentType. Seekable readers are rewound,
+// the bytes read from any other reader are put back in front of the
+// returned reader.
+func sniffContentType(reader io.Reader) (string, io.Reader, error) {
+	head := make([]byte, 512)
+	if seeker, ok := reader.(io.ReadSeeker); ok {
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return "", nil, err
+		}
+		n, err := readFull(seeker, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return "", nil, err
+		}
+		if _, err = seeker.Seek(offset, io.SeekStart); err != nil {
+			return "", nil, err
+		}
+		return http.DetectContentType(head[:n]), reader, nil
+	}
+	n, err := readFull(reader, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+	return http.DetectContentType(head[:n]), io.MultiReader(bytes.NewReader(head[:n]), reader), nil
+}
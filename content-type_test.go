@@ -0,0 +1,119 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDetectContentType(t *testing.T) {
+	AddContentTypeMapping(".ndjson", "application/x-ndjson")
+	AddContentTypeMapping("PARQUET", "application/vnd.apache.parquet")
+	defer AddContentTypeMapping(".ndjson", "")
+	defer AddContentTypeMapping(".parquet", "")
+
+	var (
+		mu          sync.Mutex
+		contentType string
+		body        string
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		contentType, body = r.Header.Get("Content-Type"), string(data)
+		mu.Unlock()
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		html = "<html><body>hello</body></html>"
+		pdf  = "%PDF-1.4 hello"
+	)
+	testCases := []struct {
+		objectName string
+		reader     io.Reader
+		content    string
+		detect     bool
+		expected   string
+	}{
+		{"data.NDJSON", strings.NewReader(`{"a":1}`), `{"a":1}`, true, "application/x-ndjson"},
+		{"data.parquet", strings.NewReader("PAR1"), "PAR1", true, "application/vnd.apache.parquet"},
+		{"page.json", strings.NewReader(html), html, true, "application/json"},
+		{"page", strings.NewReader(html), html, true, "text/html; charset=utf-8"},
+		{"page", io.MultiReader(strings.NewReader(html)), html, true, "text/html; charset=utf-8"},
+		{"page", strings.NewReader(html), html, false, "application/octet-stream"},
+		{"data.ndjson", strings.NewReader(`{"a":1}`), `{"a":1}`, false, "application/octet-stream"},
+	}
+	for i, testCase := range testCases {
+		_, err = clnt.PutObject(context.Background(), "bucket", testCase.objectName, testCase.reader, int64(len(testCase.content)),
+			PutObjectOptions{DetectContentType: testCase.detect})
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if contentType != testCase.expected {
+			t.Fatalf("Test %d: expected Content-Type %q, got %q", i+1, testCase.expected, contentType)
+		}
+		if body != testCase.content {
+			t.Fatalf("Test %d: expected body %q, got %q", i+1, testCase.content, body)
+		}
+	}
+
+	dir := t.TempDir()
+	fileCases := []struct {
+		fileName, content string
+		detect            bool
+		expected          string
+	}{
+		{"data.parquet", "PAR1", false, "application/vnd.apache.parquet"},
+		{"report.unknownext", pdf, true, "application/pdf"},
+		{"report.unknownext", pdf, false, "application/octet-stream"},
+	}
+	for i, testCase := range fileCases {
+		path := filepath.Join(dir, testCase.fileName)
+		if err = os.WriteFile(path, []byte(testCase.content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = clnt.FPutObject(context.Background(), "bucket", "object", path, PutObjectOptions{DetectContentType: testCase.detect}); err != nil {
+			t.Fatalf("File test %d: %v", i+1, err)
+		}
+		if contentType != testCase.expected {
+			t.Fatalf("File test %d: expected Content-Type %q, got %q", i+1, testCase.expected, contentType)
+		}
+		if body != testCase.content {
+			t.Fatalf("File test %d: expected body %q, got %q", i+1, testCase.content, body)
+		}
+	}
+}
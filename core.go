@@ -19,6 +19,10 @@ package minio
 
 import (
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"io"
 	"net/http"
 
@@ -61,7 +65,11 @@ func (c Core) CopyObject(ctx context.Context, sourceBucket, sourceObject, destBu
 }
 
 // CopyObjectPart - creates a part in a multipart upload by copying (a
-// part of) an existing object.
+// part of) an existing object. The part copies length bytes starting at
+// startOffset of the source, sent as the inclusive range header
+// 'x-amz-copy-source-range: bytes=startOffset-(startOffset+length-1)'.
+// A negative length copies the whole source object. partID must be
+// between 1 and 10000, all parts but the last must be at least 5MiB.
 func (c Core) CopyObjectPart(ctx context.Context, srcBucket, srcObject, destBucket, destObject, uploadID string,
 	partID int, startOffset, length int64, metadata map[string]string,
 ) (p CompletePart, err error) {
@@ -94,10 +102,23 @@ type PutObjectPartOptions struct {
 	DisableContentSha256  bool
 }
 
-// PutObjectPart - Upload an object part.
+// PutObjectPart - Upload an object part, partID is between 1 and 10000
+// and the part is at most 5GiB. Md5Base64 and Sha256Hex are sent as the
+// precomputed Content-MD5 and content SHA256 of the data when set. The
+// returned part holds the ETag and checksums to complete the upload with.
 func (c Core) PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int,
 	data io.Reader, size int64, opts PutObjectPartOptions,
 ) (ObjectPart, error) {
+	if opts.Md5Base64 != "" {
+		if md5Sum, err := base64.StdEncoding.DecodeString(opts.Md5Base64); err != nil || len(md5Sum) != md5.Size {
+			return ObjectPart{}, errInvalidArgument("Md5Base64 must be a base64 encoded MD5 sum.")
+		}
+	}
+	if opts.Sha256Hex != "" {
+		if sha256Sum, err := hex.DecodeString(opts.Sha256Hex); err != nil || len(sha256Sum) != sha256.Size {
+			return ObjectPart{}, errInvalidArgument("Sha256Hex must be a hex encoded SHA256 sum.")
+		}
+	}
 	p := uploadPartParams{
 		bucketName:   bucket,
 		objectName:   object,
@@ -115,7 +136,10 @@ func (c Core) PutObjectPart(ctx context.Context, bucket, object, uploadID string
 	return c.uploadPart(ctx, p)
 }
 
-// ListObjectParts - List uploaded parts of an incomplete upload.x
+// ListObjectParts - List uploaded parts of an incomplete upload. Parts
+// after partNumberMarker are returned with their ETag and size, up to
+// maxParts (1000 if <= 0) at a time. If the result IsTruncated, list the
+// next page with NextPartNumberMarker as partNumberMarker.
 func (c Core) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker, maxParts int) (result ListObjectPartsResult, err error) {
 	return c.listObjectPartsQuery(ctx, bucket, object, uploadID, partNumberMarker, maxParts)
 }
@@ -128,7 +152,9 @@ func (c Core) CompleteMultipartUpload(ctx context.Context, bucket, object, uploa
 	return res, err
 }
 
-// AbortMultipartUpload - Abort an incomplete upload.
+// AbortMultipartUpload - Abort an incomplete upload, all uploaded parts
+// are deleted. A NoSuchUpload error is returned if the upload does not
+// exist (anymore).
 func (c Core) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) error {
 	return c.abortMultipartUpload(ctx, bucket, object, uploadID)
 }
@@ -20,11 +20,21 @@ package minio
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"hash/crc32"
 	"io"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -896,3 +906,315 @@ func TestCoreMultipartUpload(t *testing.T) {
 		t.Fatal("Error: ", err)
 	}
 }
+
+func TestCoreCopyObjectPartCompose(t *testing.T) {
+	objects := map[string]string{
+		"first":  "hello ",
+		"second": "brave new world",
+	}
+	var (
+		mu     sync.Mutex
+		parts  = make(map[int]string)
+		result string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>composed</Key><UploadId>upload-id</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == http.MethodPut && q.Get("uploadId") == "upload-id":
+			data, ok := objects[strings.TrimPrefix(r.Header.Get("x-amz-copy-source"), "bucket/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if rng := r.Header.Get("x-amz-copy-source-range"); rng != "" {
+				var start, end int
+				if _, err := fmt.Sscanf(rng, "bytes=%d-%d", &start, &end); err != nil || end >= len(data) {
+					w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+					return
+				}
+				data = data[start : end+1]
+			}
+			partNumber, _ := strconv.Atoi(q.Get("partNumber"))
+			parts[partNumber] = data
+			io.WriteString(w, `<CopyPartResult><ETag>"etag-`+q.Get("partNumber")+`"</ETag></CopyPartResult>`)
+		case r.Method == http.MethodPost && q.Get("uploadId") == "upload-id":
+			var complete completeMultipartUpload
+			if err := xml.NewDecoder(r.Body).Decode(&complete); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			for _, part := range complete.Parts {
+				if strings.Trim(part.ETag, `"`) != fmt.Sprintf("etag-%d", part.PartNumber) {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				result += parts[part.PartNumber]
+			}
+			io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>composed</Key><ETag>"final-2"</ETag></CompleteMultipartUploadResult>`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewCore(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	uploadID, err := c.NewMultipartUpload(ctx, "bucket", "composed", PutObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fstPart, err := c.CopyObjectPart(ctx, "bucket", "first", "bucket", "composed", uploadID, 1, 0, -1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sndPart, err := c.CopyObjectPart(ctx, "bucket", "second", "bucket", "composed", uploadID, 2, 6, 5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fstPart.PartNumber != 1 || sndPart.PartNumber != 2 {
+		t.Fatalf("Unexpected part numbers %d and %d", fstPart.PartNumber, sndPart.PartNumber)
+	}
+	info, err := c.CompleteMultipartUpload(ctx, "bucket", "composed", uploadID, []CompletePart{fstPart, sndPart}, PutObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ETag != "final-2" {
+		t.Fatalf("Unexpected ETag %s", info.ETag)
+	}
+	if result != "hello new w" {
+		t.Fatalf("Unexpected composed content %q", result)
+	}
+
+	for _, partID := range []int{0, -1, maxPartsCount + 1} {
+		if _, err = c.CopyObjectPart(ctx, "bucket", "first", "bucket", "composed", uploadID, partID, 0, -1, nil); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Fatalf("Part %d: expected InvalidArgument, got %v", partID, err)
+		}
+	}
+	if _, err = c.CopyObjectPart(ctx, "bucket", "first", "bucket", "composed", "", 1, 0, -1, nil); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Fatalf("Expected InvalidArgument for an empty upload id, got %v", err)
+	}
+}
+
+func TestCoreListObjectPartsAbort(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		parts  map[int]int
+		active bool
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		q := r.URL.Query()
+		if !q.Has("uploads") && (q.Get("uploadId") != "upload-id" || !active) {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `<Error><Code>NoSuchUpload</Code><Message>The specified multipart upload does not exist.</Message></Error>`)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost:
+			active, parts = true, make(map[int]int)
+			io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>upload-id</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == http.MethodPut:
+			partNumber, _ := strconv.Atoi(q.Get("partNumber"))
+			b, _ := io.ReadAll(r.Body)
+			parts[partNumber] = len(b)
+			w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, partNumber))
+		case r.Method == http.MethodGet:
+			marker, _ := strconv.Atoi(q.Get("part-number-marker"))
+			maxParts, _ := strconv.Atoi(q.Get("max-parts"))
+			var b strings.Builder
+			b.WriteString(`<ListPartsResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>upload-id</UploadId>`)
+			listed := 0
+			for partNumber := marker + 1; partNumber <= len(parts); partNumber++ {
+				if listed == maxParts {
+					fmt.Fprintf(&b, `<IsTruncated>true</IsTruncated><NextPartNumberMarker>%d</NextPartNumberMarker>`, partNumber-1)
+					break
+				}
+				fmt.Fprintf(&b, `<Part><PartNumber>%d</PartNumber><ETag>"etag-%d"</ETag><Size>%d</Size></Part>`, partNumber, partNumber, parts[partNumber])
+				listed++
+			}
+			b.WriteString(`</ListPartsResult>`)
+			io.WriteString(w, b.String())
+		case r.Method == http.MethodDelete:
+			active = false
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewCore(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	uploadID, err := c.NewMultipartUpload(ctx, "bucket", "object", PutObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for partID, data := range []string{"first part", "second"} {
+		if _, err = c.PutObjectPart(ctx, "bucket", "object", uploadID, partID+1, strings.NewReader(data), int64(len(data)), PutObjectPartOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var listed []ObjectPart
+	marker := 0
+	for pages := 0; ; pages++ {
+		if pages == 2 {
+			t.Fatal("Expected listing to end after 2 pages")
+		}
+		result, err := c.ListObjectParts(ctx, "bucket", "object", uploadID, marker, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		listed = append(listed, result.ObjectParts...)
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+	if len(listed) != 2 {
+		t.Fatalf("Expected 2 parts, got %d", len(listed))
+	}
+	for i, size := range []int64{10, 6} {
+		if listed[i].PartNumber != i+1 || listed[i].Size != size || listed[i].ETag != fmt.Sprintf(`"etag-%d"`, i+1) {
+			t.Fatalf("Unexpected part %+v", listed[i])
+		}
+	}
+
+	if err = c.AbortMultipartUpload(ctx, "bucket", "object", uploadID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.ListObjectParts(ctx, "bucket", "object", uploadID, 0, 0); ToErrorResponse(err).Code != "NoSuchUpload" {
+		t.Fatalf("Expected NoSuchUpload after abort, got %v", err)
+	}
+	if err = c.AbortMultipartUpload(ctx, "bucket", "object", uploadID); ToErrorResponse(err).Code != "NoSuchUpload" {
+		t.Fatalf("Expected NoSuchUpload, got %v", err)
+	}
+	if err = c.AbortMultipartUpload(ctx, "bucket", "object", ""); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Fatalf("Expected InvalidArgument for an empty upload id, got %v", err)
+	}
+}
+
+func TestCorePutObjectPartPrecomputed(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		parts    = make(map[int][]byte)
+		complete completeMultipartUpload
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			io.WriteString(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>upload-id</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == http.MethodPut:
+			b, _ := io.ReadAll(r.Body)
+			md5Sum := md5.Sum(b)
+			sha256Sum := sha256.Sum256(b)
+			if r.Header.Get("Content-Md5") != base64.StdEncoding.EncodeToString(md5Sum[:]) ||
+				r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sha256Sum[:]) {
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, `<Error><Code>BadDigest</Code></Error>`)
+				return
+			}
+			partNumber, _ := strconv.Atoi(q.Get("partNumber"))
+			parts[partNumber] = b
+			crc := crc32.NewIEEE()
+			crc.Write(b)
+			w.Header().Set("ETag", `"`+hex.EncodeToString(md5Sum[:])+`"`)
+			w.Header().Set("X-Amz-Checksum-Crc32", base64.StdEncoding.EncodeToString(crc.Sum(nil)))
+		case r.Method == http.MethodPost && q.Get("uploadId") == "upload-id":
+			if err := xml.NewDecoder(r.Body).Decode(&complete); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			io.WriteString(w, `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>"final-2"</ETag></CompleteMultipartUploadResult>`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	core, err := NewCore(srv.Listener.Addr().String(), &Options{
+		Creds:      credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region:     "us-east-1",
+		Secure:     true,
+		Transport:  srv.Client().Transport,
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	uploadID, err := core.NewMultipartUpload(ctx, "bucket", "object", PutObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := [][]byte{bytes.Repeat([]byte("a"), 1024), []byte("last part")}
+	var completeParts []CompletePart
+	for i, b := range data {
+		md5Sum := md5.Sum(b)
+		sha256Sum := sha256.Sum256(b)
+		part, err := core.PutObjectPart(ctx, "bucket", "object", uploadID, i+1, bytes.NewReader(b), int64(len(b)), PutObjectPartOptions{
+			Md5Base64: base64.StdEncoding.EncodeToString(md5Sum[:]),
+			Sha256Hex: hex.EncodeToString(sha256Sum[:]),
+		})
+		if err != nil {
+			t.Fatalf("Part %d: %v", i+1, err)
+		}
+		if part.PartNumber != i+1 || part.Size != int64(len(b)) || part.ETag != hex.EncodeToString(md5Sum[:]) {
+			t.Fatalf("Part %d: unexpected part %+v", i+1, part)
+		}
+		if part.ChecksumCRC32 == "" {
+			t.Fatalf("Part %d: expected the server checksum", i+1)
+		}
+		completeParts = append(completeParts, CompletePart{PartNumber: part.PartNumber, ETag: part.ETag, ChecksumCRC32: part.ChecksumCRC32})
+	}
+
+	if _, err = core.CompleteMultipartUpload(ctx, "bucket", "object", uploadID, completeParts, PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(complete.Parts) != len(data) || complete.Parts[1].ChecksumCRC32 != completeParts[1].ChecksumCRC32 {
+		t.Fatalf("Unexpected completed parts %+v", complete.Parts)
+	}
+	if !bytes.Equal(parts[2], data[1]) {
+		t.Fatalf("Expected part 2 %q, got %q", data[1], parts[2])
+	}
+
+	invalid := []struct {
+		partID int
+		size   int64
+		opts   PutObjectPartOptions
+	}{
+		{0, 1, PutObjectPartOptions{}},
+		{10001, 1, PutObjectPartOptions{}},
+		{1, maxPartSize + 1, PutObjectPartOptions{}},
+		{1, 1, PutObjectPartOptions{Md5Base64: "not-md5"}},
+		{1, 1, PutObjectPartOptions{Sha256Hex: "abcd"}},
+	}
+	for i, testCase := range invalid {
+		_, err = core.PutObjectPart(ctx, "bucket", "object", uploadID, testCase.partID, strings.NewReader("a"), testCase.size, testCase.opts)
+		if code := ToErrorResponse(err).Code; code != "InvalidArgument" && code != "EntityTooLarge" {
+			t.Fatalf("Test %d: expected a validation error, got %v", i+1, err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ComputeMultipartETag returns the ETag S3 assigns to an object uploaded
+// in parts, the hex encoded MD5 of the concatenated part MD5s followed
+// by "-" and the number of parts. partSizes and partMD5s describe the
+// parts in order, parts may have different sizes, as with Core part
+// uploads or ComposeObject, but none can be empty.
+func ComputeMultipartETag(partSizes []int64, partMD5s [][]byte) (string, error) {
+	if len(partSizes) == 0 || len(partSizes) > maxPartsCount {
+		return "", errInvalidArgument(fmt.Sprintf("There must be between 1 and %d parts.", maxPartsCount))
+	}
+	if len(partSizes) != len(partMD5s) {
+		return "", errInvalidArgument(fmt.Sprintf("Got %d part sizes for %d part MD5s.", len(partSizes), len(partMD5s)))
+	}
+	h := md5.New()
+	for i, sum := range partMD5s {
+		if len(sum) != md5.Size {
+			return "", errInvalidArgument(fmt.Sprintf("MD5 of part %d is %d bytes, expected %d.", i+1, len(sum), md5.Size))
+		}
+		if partSizes[i] <= 0 {
+			return "", errInvalidArgument(fmt.Sprintf("Invalid size %d of part %d.", partSizes[i], i+1))
+		}
+		h.Write(sum)
+	}
+	return hex.EncodeToString(h.Sum(nil)) + "-" + strconv.Itoa(len(partMD5s)), nil
+}
+
+// IsMultipartETag returns true if the ETag of the object is the ETag
+// of a multipart upload, which is not the MD5 of the object content.
+func (o ObjectInfo) IsMultipartETag() bool {
+	return o.PartsCount() > 1 || strings.Contains(trimEtag(o.ETag), "-")
+}
+
+// PartsCount returns the number of parts of the object uploaded in
+// parts according to its ETag, or 1 for an object uploaded at once.
+func (o ObjectInfo) PartsCount() int {
+	etag := trimEtag(o.ETag)
+	i := strings.LastIndexByte(etag, '-')
+	if i < 0 {
+		return 1
+	}
+	n, err := strconv.Atoi(etag[i+1:])
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// VerifyObjectETag reports whether the local file has the content of
+// the object, by computing the ETag the file gets when uploaded in
+// parts of partSize bytes, or at once for a single part object. A zero
+// partSize stands for the part size PutObject picks by default. Only
+// meaningful for objects without SSE-C or SSE-KMS, whose ETag is not
+// derived from the MD5 of the content.
+func VerifyObjectETag(filePath string, partSize int64, objInfo ObjectInfo) (bool, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	if st.Size() != objInfo.Size {
+		return false, nil
+	}
+
+	etag := trimEtag(objInfo.ETag)
+	if !objInfo.IsMultipartETag() {
+		h := md5.New()
+		if _, err = io.Copy(h, f); err != nil {
+			return false, err
+		}
+		return hex.EncodeToString(h.Sum(nil)) == etag, nil
+	}
+
+	if partSize <= 0 {
+		if _, partSize, _, err = OptimalPartInfo(st.Size(), 0); err != nil {
+			return false, err
+		}
+	}
+	if (st.Size()+partSize-1)/partSize != int64(objInfo.PartsCount()) {
+		return false, nil
+	}
+	computed, err := readerMultipartETag(f, st.Size(), partSize)
+	if err != nil {
+		return false, err
+	}
+	return computed == etag, nil
+}
+
+// readerMultipartETag computes the multipart ETag of the size bytes of
+// r split in parts of partSize bytes.
+func readerMultipartETag(r io.Reader, size, partSize int64) (string, error) {
+	var (
+		partSizes []int64
+		partMD5s  [][]byte
+	)
+	for remaining := size; remaining > 0; remaining -= partSize {
+		n := min(partSize, remaining)
+		h := md5.New()
+		if _, err := io.CopyN(h, r, n); err != nil {
+			return "", err
+		}
+		partSizes = append(partSizes, n)
+		partMD5s = append(partMD5s, h.Sum(nil))
+	}
+	return ComputeMultipartETag(partSizes, partMD5s)
+}
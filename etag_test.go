@@ -0,0 +1,133 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"crypto/md5"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestComputeMultipartETag(t *testing.T) {
+	const mib = 1 << 20
+	parts := [][]byte{
+		bytes.Repeat([]byte("a"), 5*mib),
+		bytes.Repeat([]byte("b"), 5*mib),
+		bytes.Repeat([]byte("c"), mib),
+	}
+	var (
+		sizes []int64
+		sums  [][]byte
+	)
+	for _, part := range parts {
+		sum := md5.Sum(part)
+		sizes = append(sizes, int64(len(part)))
+		sums = append(sums, sum[:])
+	}
+	etag, err := ComputeMultipartETag(sizes, sums)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if etag != "7f636b2c1182136c010c5860a051b3e8-3" {
+		t.Fatalf("Unexpected ETag %s", etag)
+	}
+
+	// Only the part MD5s and count matter, parts may have any size.
+	if etag, err = ComputeMultipartETag([]int64{5 * mib, 6 * mib, 7 * mib}, sums); err != nil || etag != "7f636b2c1182136c010c5860a051b3e8-3" {
+		t.Fatalf("Unexpected ETag of uneven parts %s, %v", etag, err)
+	}
+
+	sum := md5.Sum([]byte("xxxxxxxxxx"))
+	if etag, err = ComputeMultipartETag([]int64{10}, [][]byte{sum[:]}); err != nil || etag != "cb8d7289925309a05b2db09b7709c264-1" {
+		t.Fatalf("Unexpected single part ETag %s, %v", etag, err)
+	}
+
+	invalid := []struct {
+		sizes []int64
+		sums  [][]byte
+	}{
+		{nil, nil},
+		{[]int64{5 * mib}, nil},
+		{[]int64{5 * mib}, [][]byte{sum[:8]}},
+		{[]int64{0}, sums[:1]},
+		{[]int64{5 * mib, -1}, sums[:2]},
+	}
+	for i, testCase := range invalid {
+		if _, err = ComputeMultipartETag(testCase.sizes, testCase.sums); err == nil {
+			t.Fatalf("Test %d: expected an error", i+1)
+		}
+	}
+}
+
+func TestObjectInfoPartsCount(t *testing.T) {
+	testCases := []struct {
+		etag      string
+		multipart bool
+		parts     int
+	}{
+		{`"fa890dba91752ab3464d86af1db11035"`, false, 1},
+		{"7f636b2c1182136c010c5860a051b3e8-3", true, 3},
+		{`"cb8d7289925309a05b2db09b7709c264-1"`, true, 1},
+		{"", false, 1},
+	}
+	for i, testCase := range testCases {
+		objInfo := ObjectInfo{ETag: testCase.etag}
+		if objInfo.IsMultipartETag() != testCase.multipart {
+			t.Fatalf("Test %d: expected multipart %t", i+1, testCase.multipart)
+		}
+		if n := objInfo.PartsCount(); n != testCase.parts {
+			t.Fatalf("Test %d: expected %d parts, got %d", i+1, testCase.parts, n)
+		}
+	}
+}
+
+func TestVerifyObjectETag(t *testing.T) {
+	const mib = 1 << 20
+	content := append(append(bytes.Repeat([]byte("a"), 5*mib), bytes.Repeat([]byte("b"), 5*mib)...), bytes.Repeat([]byte("c"), mib)...)
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	size := int64(len(content))
+
+	testCases := []struct {
+		partSize int64
+		objInfo  ObjectInfo
+		match    bool
+	}{
+		{5 * mib, ObjectInfo{Size: size, ETag: `"7f636b2c1182136c010c5860a051b3e8-3"`}, true},
+		{0, ObjectInfo{Size: size, ETag: "fa890dba91752ab3464d86af1db11035"}, true},
+		// A different part size gives a different layout.
+		{6 * mib, ObjectInfo{Size: size, ETag: "7f636b2c1182136c010c5860a051b3e8-3"}, false},
+		// The default part size of PutObject uploads the file in one part.
+		{0, ObjectInfo{Size: size, ETag: "7f636b2c1182136c010c5860a051b3e8-3"}, false},
+		{5 * mib, ObjectInfo{Size: size, ETag: "00000000000000000000000000000000-3"}, false},
+		{5 * mib, ObjectInfo{Size: size - 1, ETag: "7f636b2c1182136c010c5860a051b3e8-3"}, false},
+	}
+	for i, testCase := range testCases {
+		match, err := VerifyObjectETag(path, testCase.partSize, testCase.objInfo)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if match != testCase.match {
+			t.Fatalf("Test %d: expected match %t", i+1, testCase.match)
+		}
+	}
+}
@@ -1139,6 +1139,82 @@ func testPutObjectWithVersioning() {
 	logSuccess(testName, function, args, startTime)
 }
 
+func testListAllIncompleteUploads() {
+	// initialize logging params
+	startTime := time.Now()
+	testName := getFuncName()
+	function := "ListAllIncompleteUploads(bucketName)"
+	args := map[string]interface{}{}
+
+	c, err := NewClient(ClientConfig{})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "MinIO client object creation failed", err)
+		return
+	}
+	core := minio.Core{Client: c}
+
+	// Generate a new random bucket name.
+	bucketName := randString(60, rand.NewSource(time.Now().UnixNano()), "minio-go-test-")
+	args["bucketName"] = bucketName
+
+	// Make a new bucket.
+	ctx := context.Background()
+	err = c.MakeBucket(ctx, bucketName, minio.MakeBucketOptions{Region: "us-east-1"})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "Make bucket failed", err)
+		return
+	}
+	defer cleanupBucket(bucketName, c)
+
+	// Start several uploads concurrently, including more than one
+	// upload per key, spread over unrelated prefixes.
+	objectNames := []string{"a/object", "a/object", "b/c/object", "object", "object"}
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+	want := make(map[string]string)
+	for _, objectName := range objectNames {
+		wg.Add(1)
+		go func(objectName string) {
+			defer wg.Done()
+			uploadID, err := core.NewMultipartUpload(ctx, bucketName, objectName, minio.PutObjectOptions{})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			want[uploadID] = objectName
+		}(objectName)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		logError(testName, function, args, startTime, "", "NewMultipartUpload failed", errs[0])
+		return
+	}
+
+	got := make(map[string]string)
+	for upload := range c.ListAllIncompleteUploads(ctx, bucketName) {
+		if upload.Err != nil {
+			logError(testName, function, args, startTime, "", "ListAllIncompleteUploads failed", upload.Err)
+			return
+		}
+		if upload.Initiated.IsZero() {
+			logError(testName, function, args, startTime, "", "Upload "+upload.UploadID+" has no initiated time", nil)
+			return
+		}
+		got[upload.UploadID] = upload.Key
+	}
+	if !reflect.DeepEqual(got, want) {
+		logError(testName, function, args, startTime, "", fmt.Sprintf("Expected uploads %v, got %v", want, got), nil)
+		return
+	}
+
+	logSuccess(testName, function, args, startTime)
+}
+
 func testListMultipartUpload() {
 	// initialize logging params
 	startTime := time.Now()
@@ -1821,6 +1897,491 @@ func testRemoveObjectsWithVersioning() {
 	logSuccess(testName, function, args, startTime)
 }
 
+func testRemoveObjectsSpecificVersions() {
+	// initialize logging params
+	startTime := time.Now()
+	testName := getFuncName()
+	function := "RemoveObjectsWithResult(bucketName, objectsCh, opts)"
+	args := map[string]interface{}{}
+
+	c, err := NewClient(ClientConfig{})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "MinIO client object creation failed", err)
+		return
+	}
+
+	// Generate a new random bucket name.
+	bucketName := randString(60, rand.NewSource(time.Now().UnixNano()), "minio-go-test-")
+	args["bucketName"] = bucketName
+
+	// Make a new bucket.
+	err = c.MakeBucket(context.Background(), bucketName, minio.MakeBucketOptions{Region: "us-east-1", ObjectLocking: true})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "Make bucket failed", err)
+		return
+	}
+	defer cleanupVersionedBucket(bucketName, c)
+
+	err = c.EnableVersioning(context.Background(), bucketName)
+	if err != nil {
+		logError(testName, function, args, startTime, "", "Enable versioning failed", err)
+		return
+	}
+
+	// Upload three versions of several keys, then remove the
+	// oldest two versions of each key.
+	objectNames := []string{"object-a", "object-b", "prefix/object-c"}
+	var toRemove []minio.ObjectInfo
+	want := make(map[string]string)
+	for _, objectName := range objectNames {
+		for i := 0; i < 3; i++ {
+			info, err := c.PutObject(context.Background(), bucketName, objectName, strings.NewReader(strconv.Itoa(i)), -1, minio.PutObjectOptions{})
+			if err != nil {
+				logError(testName, function, args, startTime, "", "PutObject failed", err)
+				return
+			}
+			if i < 2 {
+				toRemove = append(toRemove, minio.ObjectInfo{Key: objectName, VersionID: info.VersionID})
+				continue
+			}
+			want[objectName] = info.VersionID
+		}
+	}
+
+	objectsCh := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objectsCh)
+		for _, obj := range toRemove {
+			objectsCh <- obj
+		}
+	}()
+
+	removed := make(map[string]bool)
+	for res := range c.RemoveObjectsWithResult(context.Background(), bucketName, objectsCh, minio.RemoveObjectsOptions{}) {
+		if res.Err != nil {
+			logError(testName, function, args, startTime, "", "Removing "+res.ObjectName+" ("+res.ObjectVersionID+") failed", res.Err)
+			return
+		}
+		if res.DeleteMarker {
+			logError(testName, function, args, startTime, "", "Removing a specific version should not create a delete marker", nil)
+			return
+		}
+		removed[res.ObjectName+"/"+res.ObjectVersionID] = true
+	}
+	for _, obj := range toRemove {
+		if !removed[obj.Key+"/"+obj.VersionID] {
+			logError(testName, function, args, startTime, "", "Missing remove result for "+obj.Key+" ("+obj.VersionID+")", nil)
+			return
+		}
+	}
+
+	got := make(map[string]string)
+	for info := range c.ListObjects(context.Background(), bucketName, minio.ListObjectsOptions{WithVersions: true, Recursive: true}) {
+		if info.Err != nil {
+			logError(testName, function, args, startTime, "", "Unexpected error during listing objects", info.Err)
+			return
+		}
+		if _, ok := got[info.Key]; ok {
+			logError(testName, function, args, startTime, "", "Unexpected remaining versions for "+info.Key, nil)
+			return
+		}
+		got[info.Key] = info.VersionID
+	}
+	if !reflect.DeepEqual(got, want) {
+		logError(testName, function, args, startTime, "", fmt.Sprintf("Expected remaining versions %v, got %v", want, got), nil)
+		return
+	}
+
+	logSuccess(testName, function, args, startTime)
+}
+
+func testKeepLastNVersions() {
+	// initialize logging params
+	startTime := time.Now()
+	testName := getFuncName()
+	function := "KeepLastNVersions()"
+	args := map[string]interface{}{}
+
+	c, err := NewClient(ClientConfig{})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "MinIO client object creation failed", err)
+		return
+	}
+
+	// Generate a new random bucket name.
+	bucketName := randString(60, rand.NewSource(time.Now().UnixNano()), "minio-go-test-")
+	args["bucketName"] = bucketName
+
+	// Make a new bucket.
+	err = c.MakeBucket(context.Background(), bucketName, minio.MakeBucketOptions{Region: "us-east-1", ObjectLocking: true})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "Make bucket failed", err)
+		return
+	}
+	defer cleanupVersionedBucket(bucketName, c)
+
+	err = c.EnableVersioning(context.Background(), bucketName)
+	if err != nil {
+		logError(testName, function, args, startTime, "", "Enable versioning failed", err)
+		return
+	}
+
+	objectName := randString(60, rand.NewSource(time.Now().UnixNano()), "")
+	args["objectName"] = objectName
+
+	const totalVersions = 8
+	const keepVersions = 3
+	args["n"] = keepVersions
+
+	var versions []string
+	for i := 0; i < totalVersions; i++ {
+		info, err := c.PutObject(context.Background(), bucketName, objectName, strings.NewReader(strconv.Itoa(i)), -1, minio.PutObjectOptions{})
+		if err != nil {
+			logError(testName, function, args, startTime, "", "PutObject failed", err)
+			return
+		}
+		versions = append(versions, info.VersionID)
+	}
+	// Another key sharing the same prefix must not be affected.
+	_, err = c.PutObject(context.Background(), bucketName, objectName+"-other", strings.NewReader("other"), -1, minio.PutObjectOptions{})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "PutObject failed", err)
+		return
+	}
+
+	if res := <-c.KeepLastNVersions(context.Background(), bucketName, objectName, 0, minio.KeepLastNVersionsOptions{}); res.Err == nil {
+		logError(testName, function, args, startTime, "", "KeepLastNVersions with n=0 should fail", nil)
+		return
+	}
+
+	removed := 0
+	for res := range c.KeepLastNVersions(context.Background(), bucketName, objectName, keepVersions, minio.KeepLastNVersionsOptions{GovernanceBypass: true}) {
+		if res.Err != nil {
+			logError(testName, function, args, startTime, "", "KeepLastNVersions failed", res.Err)
+			return
+		}
+		removed++
+	}
+	if removed != totalVersions-keepVersions {
+		logError(testName, function, args, startTime, "", fmt.Sprintf("Expected %d removed versions, got %d", totalVersions-keepVersions, removed), nil)
+		return
+	}
+
+	var remaining []string
+	otherFound := false
+	for info := range c.ListObjects(context.Background(), bucketName, minio.ListObjectsOptions{WithVersions: true, Recursive: true}) {
+		if info.Err != nil {
+			logError(testName, function, args, startTime, "", "Unexpected error during listing objects", info.Err)
+			return
+		}
+		if info.Key == objectName+"-other" {
+			otherFound = true
+			continue
+		}
+		remaining = append(remaining, info.VersionID)
+	}
+	if !otherFound {
+		logError(testName, function, args, startTime, "", "Object with a shared prefix was unexpectedly removed", nil)
+		return
+	}
+
+	sort.Strings(remaining)
+	expected := append([]string{}, versions[totalVersions-keepVersions:]...)
+	sort.Strings(expected)
+	if !reflect.DeepEqual(remaining, expected) {
+		logError(testName, function, args, startTime, "", fmt.Sprintf("Expected versions %v to remain, got %v", expected, remaining), nil)
+		return
+	}
+
+	logSuccess(testName, function, args, startTime)
+}
+
+func testObjectRetentionWithVersioning() {
+	// initialize logging params
+	startTime := time.Now()
+	testName := getFuncName()
+	function := "{Put,Get}ObjectRetention()"
+	args := map[string]interface{}{}
+
+	c, err := NewClient(ClientConfig{})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "MinIO client object creation failed", err)
+		return
+	}
+
+	// Generate a new random bucket name.
+	bucketName := randString(60, rand.NewSource(time.Now().UnixNano()), "minio-go-test-")
+	args["bucketName"] = bucketName
+
+	// Make a new bucket.
+	err = c.MakeBucket(context.Background(), bucketName, minio.MakeBucketOptions{Region: "us-east-1", ObjectLocking: true})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "Make bucket failed", err)
+		return
+	}
+	defer cleanupVersionedBucket(bucketName, c)
+
+	objectName := randString(60, rand.NewSource(time.Now().UnixNano()), "")
+	args["objectName"] = objectName
+
+	info, err := c.PutObject(context.Background(), bucketName, objectName, strings.NewReader("retention"), -1, minio.PutObjectOptions{})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "PutObject failed", err)
+		return
+	}
+	args["versionId"] = info.VersionID
+
+	mode := minio.Governance
+	until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	err = c.PutObjectRetention(context.Background(), bucketName, objectName, minio.PutObjectRetentionOptions{
+		Mode:            &mode,
+		RetainUntilDate: &until,
+		VersionID:       info.VersionID,
+	})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "PutObjectRetention failed", err)
+		return
+	}
+
+	gotMode, gotUntil, err := c.GetObjectRetention(context.Background(), bucketName, objectName, info.VersionID)
+	if err != nil {
+		logError(testName, function, args, startTime, "", "GetObjectRetention failed", err)
+		return
+	}
+	if gotMode == nil || *gotMode != minio.Governance || gotUntil == nil || !gotUntil.Equal(until) {
+		logError(testName, function, args, startTime, "", fmt.Sprintf("Unexpected retention %v until %v", gotMode, gotUntil), nil)
+		return
+	}
+
+	// Removing the locked version without bypass must fail.
+	err = c.RemoveObject(context.Background(), bucketName, objectName, minio.RemoveObjectOptions{VersionID: info.VersionID})
+	if err == nil {
+		logError(testName, function, args, startTime, "", "RemoveObject on a locked version should fail", nil)
+		return
+	}
+
+	// Shortening a governance retention without bypass must fail.
+	shorter := until.Add(-30 * time.Minute)
+	err = c.PutObjectRetention(context.Background(), bucketName, objectName, minio.PutObjectRetentionOptions{
+		Mode:            &mode,
+		RetainUntilDate: &shorter,
+		VersionID:       info.VersionID,
+	})
+	if err == nil {
+		logError(testName, function, args, startTime, "", "Shortening governance retention without bypass should fail", nil)
+		return
+	}
+
+	// Overwrite the retention with governance bypass.
+	err = c.PutObjectRetention(context.Background(), bucketName, objectName, minio.PutObjectRetentionOptions{
+		Mode:             &mode,
+		RetainUntilDate:  &shorter,
+		VersionID:        info.VersionID,
+		GovernanceBypass: true,
+	})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "PutObjectRetention with governance bypass failed", err)
+		return
+	}
+	_, gotUntil, err = c.GetObjectRetention(context.Background(), bucketName, objectName, info.VersionID)
+	if err != nil {
+		logError(testName, function, args, startTime, "", "GetObjectRetention failed", err)
+		return
+	}
+	if gotUntil == nil || !gotUntil.Equal(shorter) {
+		logError(testName, function, args, startTime, "", fmt.Sprintf("Expected retain until date %v, got %v", shorter, gotUntil), nil)
+		return
+	}
+
+	// Dates in the past are rejected by the client.
+	past := time.Now().Add(-time.Hour)
+	err = c.PutObjectRetention(context.Background(), bucketName, objectName, minio.PutObjectRetentionOptions{
+		Mode:            &mode,
+		RetainUntilDate: &past,
+		VersionID:       info.VersionID,
+	})
+	if err == nil {
+		logError(testName, function, args, startTime, "", "PutObjectRetention with a past date should fail", nil)
+		return
+	}
+
+	logSuccess(testName, function, args, startTime)
+}
+
+func testObjectLegalHoldWithVersioning() {
+	// initialize logging params
+	startTime := time.Now()
+	testName := getFuncName()
+	function := "{Put,Get}ObjectLegalHold()"
+	args := map[string]interface{}{}
+
+	c, err := NewClient(ClientConfig{})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "MinIO client object creation failed", err)
+		return
+	}
+
+	// Generate a new random bucket name.
+	bucketName := randString(60, rand.NewSource(time.Now().UnixNano()), "minio-go-test-")
+	args["bucketName"] = bucketName
+
+	// Make a new bucket.
+	err = c.MakeBucket(context.Background(), bucketName, minio.MakeBucketOptions{Region: "us-east-1", ObjectLocking: true})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "Make bucket failed", err)
+		return
+	}
+	defer cleanupVersionedBucket(bucketName, c)
+
+	objectName := randString(60, rand.NewSource(time.Now().UnixNano()), "")
+	args["objectName"] = objectName
+
+	info, err := c.PutObject(context.Background(), bucketName, objectName, strings.NewReader("legal-hold"), -1, minio.PutObjectOptions{})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "PutObject failed", err)
+		return
+	}
+	args["versionId"] = info.VersionID
+
+	on := minio.LegalHoldEnabled
+	err = c.PutObjectLegalHold(context.Background(), bucketName, objectName, minio.PutObjectLegalHoldOptions{Status: &on, VersionID: info.VersionID})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "PutObjectLegalHold failed", err)
+		return
+	}
+	status, err := c.GetObjectLegalHold(context.Background(), bucketName, objectName, minio.GetObjectLegalHoldOptions{VersionID: info.VersionID})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "GetObjectLegalHold failed", err)
+		return
+	}
+	if *status != minio.LegalHoldEnabled {
+		logError(testName, function, args, startTime, "", "Expected legal hold ON, got "+status.String(), nil)
+		return
+	}
+
+	// A version under legal hold cannot be deleted, not even with governance bypass.
+	err = c.RemoveObject(context.Background(), bucketName, objectName, minio.RemoveObjectOptions{VersionID: info.VersionID, GovernanceBypass: true})
+	if err == nil {
+		logError(testName, function, args, startTime, "", "RemoveObject on a version under legal hold should fail", nil)
+		return
+	}
+
+	off := minio.LegalHoldDisabled
+	err = c.PutObjectLegalHold(context.Background(), bucketName, objectName, minio.PutObjectLegalHoldOptions{Status: &off, VersionID: info.VersionID})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "PutObjectLegalHold failed", err)
+		return
+	}
+	status, err = c.GetObjectLegalHold(context.Background(), bucketName, objectName, minio.GetObjectLegalHoldOptions{VersionID: info.VersionID})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "GetObjectLegalHold failed", err)
+		return
+	}
+	if *status != minio.LegalHoldDisabled {
+		logError(testName, function, args, startTime, "", "Expected legal hold OFF, got "+status.String(), nil)
+		return
+	}
+
+	err = c.RemoveObject(context.Background(), bucketName, objectName, minio.RemoveObjectOptions{VersionID: info.VersionID})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "RemoveObject after clearing legal hold failed", err)
+		return
+	}
+
+	// Legal hold on a bucket without object lock returns a typed error.
+	unlockedBucket := randString(60, rand.NewSource(time.Now().UnixNano()), "minio-go-test-")
+	args["unlockedBucket"] = unlockedBucket
+	err = c.MakeBucket(context.Background(), unlockedBucket, minio.MakeBucketOptions{Region: "us-east-1"})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "Make bucket failed", err)
+		return
+	}
+	defer cleanupBucket(unlockedBucket, c)
+
+	_, err = c.PutObject(context.Background(), unlockedBucket, objectName, strings.NewReader("legal-hold"), -1, minio.PutObjectOptions{})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "PutObject failed", err)
+		return
+	}
+	err = c.PutObjectLegalHold(context.Background(), unlockedBucket, objectName, minio.PutObjectLegalHoldOptions{Status: &on})
+	if minio.ToErrorResponse(err).Code != "ObjectLockConfigurationNotFoundError" {
+		logError(testName, function, args, startTime, "", "Expected ObjectLockConfigurationNotFoundError", err)
+		return
+	}
+
+	logSuccess(testName, function, args, startTime)
+}
+
+func testObjectLockConfig() {
+	// initialize logging params
+	startTime := time.Now()
+	testName := getFuncName()
+	function := "{Set,Get}ObjectLockConfig()"
+	args := map[string]interface{}{}
+
+	c, err := NewClient(ClientConfig{})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "MinIO client object creation failed", err)
+		return
+	}
+
+	// Generate a new random bucket name.
+	bucketName := randString(60, rand.NewSource(time.Now().UnixNano()), "minio-go-test-")
+	args["bucketName"] = bucketName
+
+	// Make a new bucket.
+	err = c.MakeBucket(context.Background(), bucketName, minio.MakeBucketOptions{Region: "us-east-1", ObjectLocking: true})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "Make bucket failed", err)
+		return
+	}
+	defer cleanupVersionedBucket(bucketName, c)
+
+	mode := minio.Governance
+	validity := uint(30)
+	unit := minio.Days
+	err = c.SetObjectLockConfig(context.Background(), bucketName, &mode, &validity, &unit)
+	if err != nil {
+		logError(testName, function, args, startTime, "", "SetObjectLockConfig failed", err)
+		return
+	}
+
+	objectLock, gotMode, gotValidity, gotUnit, err := c.GetObjectLockConfig(context.Background(), bucketName)
+	if err != nil {
+		logError(testName, function, args, startTime, "", "GetObjectLockConfig failed", err)
+		return
+	}
+	if objectLock != "Enabled" || gotMode == nil || *gotMode != mode || gotValidity == nil || *gotValidity != validity || gotUnit == nil || *gotUnit != unit {
+		logError(testName, function, args, startTime, "", fmt.Sprintf("Unexpected object lock configuration %v %v %v %v", objectLock, gotMode, gotValidity, gotUnit), nil)
+		return
+	}
+
+	// Clear the default retention, so that cleanup can remove objects.
+	err = c.SetObjectLockConfig(context.Background(), bucketName, nil, nil, nil)
+	if err != nil {
+		logError(testName, function, args, startTime, "", "SetObjectLockConfig failed", err)
+		return
+	}
+
+	// Buckets created without object lock return a typed error.
+	unlockedBucket := randString(60, rand.NewSource(time.Now().UnixNano()), "minio-go-test-")
+	args["unlockedBucket"] = unlockedBucket
+	err = c.MakeBucket(context.Background(), unlockedBucket, minio.MakeBucketOptions{Region: "us-east-1"})
+	if err != nil {
+		logError(testName, function, args, startTime, "", "Make bucket failed", err)
+		return
+	}
+	defer cleanupBucket(unlockedBucket, c)
+
+	_, _, _, _, err = c.GetObjectLockConfig(context.Background(), unlockedBucket)
+	if minio.ToErrorResponse(err).Code != "ObjectLockConfigurationNotFoundError" {
+		logError(testName, function, args, startTime, "", "Expected ObjectLockConfigurationNotFoundError", err)
+		return
+	}
+
+	logSuccess(testName, function, args, startTime)
+}
+
 func testObjectTaggingWithVersioning() {
 	// initialize logging params
 	startTime := time.Now()
@@ -13790,6 +14351,7 @@ func main() {
 		testCorsSetGetDelete()
 		testCors()
 		testListMultipartUpload()
+		testListAllIncompleteUploads()
 		testGetObjectAttributes()
 		testGetObjectAttributesErrorCases()
 		testMakeBucketErrorV2()
@@ -13860,6 +14422,11 @@ func main() {
 		testComposeObjectWithVersioning()
 		testRemoveObjectWithVersioning()
 		testRemoveObjectsWithVersioning()
+		testRemoveObjectsSpecificVersions()
+		testKeepLastNVersions()
+		testObjectRetentionWithVersioning()
+		testObjectLegalHoldWithVersioning()
+		testObjectLockConfig()
 		testObjectTaggingWithVersioning()
 		testTrailingChecksums()
 		testPutObjectWithAutomaticChecksums()
@@ -19,9 +19,11 @@ package minio
 
 import (
 	"context"
+	"io"
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -67,3 +69,113 @@ func TestHealthCheck(t *testing.T) {
 		t.Fatal("Expected online but found offline")
 	}
 }
+
+func TestHealthCheckToggle(t *testing.T) {
+	var healthy int32 = 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != minioLivenessPath {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if atomic.LoadInt32(&healthy) == 1 {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hcancel, err := clnt.HealthCheck(1 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hcancel()
+	if !clnt.IsOnline() {
+		t.Fatal("Expected online but found offline")
+	}
+
+	waitFor := func(online bool) {
+		deadline := time.Now().Add(5 * time.Second)
+		for clnt.IsOnline() != online {
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected online to be %v", online)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	atomic.StoreInt32(&healthy, 0)
+	waitFor(false)
+	if err = clnt.EndpointHealth(context.Background()); err == nil {
+		t.Fatal("Expected unhealthy endpoint")
+	}
+	atomic.StoreInt32(&healthy, 1)
+	waitFor(true)
+}
+
+func TestEndpointHealthGenericS3(t *testing.T) {
+	var status int32 = http.StatusForbidden
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.URL.Path != "/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	}))
+	defer srv.Close()
+
+	tr := &bodyTrackingTransport{next: http.DefaultTransport}
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Transport: tr,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = clnt.EndpointHealth(context.Background()); err != nil {
+		t.Fatalf("Expected healthy endpoint, got %v", err)
+	}
+	atomic.StoreInt32(&status, http.StatusServiceUnavailable)
+	if err = clnt.EndpointHealth(context.Background()); err == nil {
+		t.Fatal("Expected unhealthy endpoint")
+	}
+	if n := atomic.LoadInt32(&tr.open); n != 0 {
+		t.Fatalf("Expected every response to be closed, %d left open", n)
+	}
+}
+
+// bodyTrackingTransport counts the response bodies not closed yet.
+type bodyTrackingTransport struct {
+	next http.RoundTripper
+	open int32
+}
+
+func (b *bodyTrackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := b.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt32(&b.open, 1)
+	resp.Body = &trackedBody{ReadCloser: resp.Body, open: &b.open}
+	return resp, nil
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	open   *int32
+	closed int32
+}
+
+func (t *trackedBody) Close() error {
+	if atomic.CompareAndSwapInt32(&t.closed, 0, 1) {
+		atomic.AddInt32(t.open, -1)
+	}
+	return t.ReadCloser.Close()
+}
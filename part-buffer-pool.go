@@ -0,0 +1,97 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"sync"
+)
+
+// partBufferPool hands out the buffers parts are read into before
+// they are uploaded. Buffers are reused through a sync.Pool per part
+// size and, when limit is set, getting a buffer blocks while the bytes
+// handed out would exceed limit, see Options.MaxUploadBufferBytes.
+//
+// A nil *partBufferPool allocates every buffer.
+type partBufferPool struct {
+	limit int64
+
+	mu       sync.Mutex
+	used     int64
+	peak     int64         // High-water mark of used.
+	released chan struct{} // Closed when buffers are put back.
+	pools    map[int64]*sync.Pool
+}
+
+func newPartBufferPool(limit int64) *partBufferPool {
+	return &partBufferPool{
+		limit:    limit,
+		released: make(chan struct{}),
+		pools:    make(map[int64]*sync.Pool),
+	}
+}
+
+// get returns a buffer of size bytes. It waits for buffers to be put
+// back while the limit is reached, a single buffer larger than the
+// limit is handed out once no other buffer is in use.
+func (p *partBufferPool) get(ctx context.Context, size int64) ([]byte, error) {
+	if p == nil {
+		return make([]byte, size), nil
+	}
+	p.mu.Lock()
+	for p.limit > 0 && p.used > 0 && p.used+size > p.limit {
+		released := p.released
+		p.mu.Unlock()
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		p.mu.Lock()
+	}
+	p.used += size
+	if p.used > p.peak {
+		p.peak = p.used
+	}
+	pool, ok := p.pools[size]
+	if !ok {
+		pool = &sync.Pool{New: func() interface{} {
+			buf := make([]byte, size)
+			return &buf
+		}}
+		p.pools[size] = pool
+	}
+	p.mu.Unlock()
+	return *pool.Get().(*[]byte), nil
+}
+
+// put gives back a buffer returned by get, it must not be used
+// afterwards.
+func (p *partBufferPool) put(buf []byte) {
+	if p == nil {
+		return
+	}
+	size := int64(len(buf))
+	p.mu.Lock()
+	p.used -= size
+	pool := p.pools[size]
+	close(p.released)
+	p.released = make(chan struct{})
+	p.mu.Unlock()
+	pool.Put(&buf)
+}
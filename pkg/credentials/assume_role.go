@@ -104,6 +104,11 @@ type STSAssumeRoleOptions struct {
 	RoleARN         string
 	RoleSessionName string
 	ExternalID      string
+
+	// Optional, credentials are refreshed this long before they expire.
+	// Defaults to DefaultExpiryWindow, refreshing after 80% of their
+	// lifetime.
+	ExpiryWindow time.Duration
 }
 
 // NewSTSAssumeRole returns a pointer to a new
@@ -186,7 +191,7 @@ func getAssumeRoleCredentials(clnt *http.Client, endpoint string, opts STSAssume
 	}
 	req = signer.SignV4STS(*req, opts.AccessKey, opts.SecretKey, opts.Location)
 
-	resp, err := clnt.Do(req)
+	resp, err := doSTSRequest(clnt, req)
 	if err != nil {
 		return AssumeRoleResponse{}, err
 	}
@@ -245,8 +250,11 @@ func (m *STSAssumeRole) RetrieveWithCredContext(cc *CredContext) (Value, error)
 		return Value{}, err
 	}
 
-	// Expiry window is set to 10secs.
-	m.SetExpiration(a.Result.Credentials.Expiration, DefaultExpiryWindow)
+	window := m.Options.ExpiryWindow
+	if window == 0 {
+		window = DefaultExpiryWindow
+	}
+	m.SetExpiration(a.Result.Credentials.Expiration, window)
 
 	return Value{
 		AccessKeyID:     a.Result.Credentials.AccessKey,
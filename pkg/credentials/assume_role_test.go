@@ -0,0 +1,165 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package credentials
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const assumeRoleRespTmpl = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>accessKey%d</AccessKeyId>
+      <SecretAccessKey>secretKey%d</SecretAccessKey>
+      <SessionToken>token%d</SessionToken>
+      <Expiration>%s</Expiration>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`
+
+// newSTSServer returns a mock STS server issuing credentials valid for
+// lifetime, the first failures requests are answered with 503.
+func newSTSServer(t *testing.T, action, respTmpl string, lifetime time.Duration, failures int32) (*httptest.Server, *int32) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		if err := r.ParseForm(); err != nil || r.Form.Get("Action") != action {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if n <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		expiration := time.Now().UTC().Add(lifetime).Format(time.RFC3339)
+		fmt.Fprintf(w, respTmpl, n, n, n, expiration)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestAssumeRoleRefresh(t *testing.T) {
+	srv, requests := newSTSServer(t, "AssumeRole", assumeRoleRespTmpl, time.Hour, 0)
+
+	now := time.Now()
+	provider := &STSAssumeRole{
+		STSEndpoint: srv.URL,
+		Options: STSAssumeRoleOptions{
+			AccessKey:    "accessKey",
+			SecretKey:    "secretKey",
+			ExpiryWindow: 10 * time.Minute,
+		},
+	}
+	provider.CurrentTime = func() time.Time { return now }
+	creds := New(provider)
+
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.AccessKeyID != "accessKey1" || v.SessionToken != "token1" {
+		t.Fatalf("Unexpected credentials %+v", v)
+	}
+
+	// Still valid, served from the cache.
+	now = now.Add(45 * time.Minute)
+	if v, err = creds.Get(); err != nil {
+		t.Fatal(err)
+	}
+	if v.AccessKeyID != "accessKey1" || atomic.LoadInt32(requests) != 1 {
+		t.Fatalf("Expected cached credentials, got %s after %d requests", v.AccessKeyID, atomic.LoadInt32(requests))
+	}
+
+	// Within the expiry window, credentials are refreshed.
+	now = now.Add(10 * time.Minute)
+	if v, err = creds.Get(); err != nil {
+		t.Fatal(err)
+	}
+	if v.AccessKeyID != "accessKey2" || atomic.LoadInt32(requests) != 2 {
+		t.Fatalf("Expected refreshed credentials, got %s after %d requests", v.AccessKeyID, atomic.LoadInt32(requests))
+	}
+}
+
+func TestAssumeRoleRetry(t *testing.T) {
+	defer func(unit time.Duration) { stsRetryUnit = unit }(stsRetryUnit)
+	stsRetryUnit = time.Millisecond
+
+	srv, requests := newSTSServer(t, "AssumeRole", assumeRoleRespTmpl, time.Hour, 2)
+	creds, err := NewSTSAssumeRole(srv.URL, STSAssumeRoleOptions{
+		AccessKey: "accessKey",
+		SecretKey: "secretKey",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.AccessKeyID != "accessKey3" {
+		t.Fatalf("Expected credentials from the third attempt, got %s", v.AccessKeyID)
+	}
+
+	// Persistent failures are reported after the last attempt.
+	srv, requests = newSTSServer(t, "AssumeRole", assumeRoleRespTmpl, time.Hour, 10)
+	creds, err = NewSTSAssumeRole(srv.URL, STSAssumeRoleOptions{
+		AccessKey: "accessKey",
+		SecretKey: "secretKey",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = creds.Get(); err == nil {
+		t.Fatal("Expected an error when STS is unavailable")
+	}
+	if n := atomic.LoadInt32(requests); n != int32(stsMaxAttempts) {
+		t.Fatalf("Expected %d attempts, got %d", stsMaxAttempts, n)
+	}
+}
+
+func TestWebIdentityRefresh(t *testing.T) {
+	respTmpl := strings.NewReplacer("AssumeRoleResponse", "AssumeRoleWithWebIdentityResponse",
+		"AssumeRoleResult", "AssumeRoleWithWebIdentityResult").Replace(assumeRoleRespTmpl)
+	srv, requests := newSTSServer(t, "AssumeRoleWithWebIdentity", respTmpl, time.Hour, 0)
+
+	creds, err := NewSTSWebIdentity(srv.URL, func() (*WebIdentityToken, error) {
+		return &WebIdentityToken{Token: "token"}, nil
+	}, WithExpiryWindow(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 2; i++ {
+		v, err := creds.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The window covers the whole lifetime, every Get refreshes.
+		if want := fmt.Sprintf("accessKey%d", i); v.AccessKeyID != want {
+			t.Fatalf("Expected %s, got %s", want, v.AccessKeyID)
+		}
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Fatalf("Expected 2 requests, got %d", n)
+	}
+}
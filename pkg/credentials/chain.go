@@ -29,7 +29,9 @@ package credentials
 //
 // If a Provider is found which returns valid credentials Value ChainProvider
 // will cache that Provider for all calls to IsExpired(), until Retrieve is
-// called again after IsExpired() is true.
+// called again after IsExpired() is true. Providers returning an error are
+// skipped, the SignerType of the returned Value tells which signature
+// version the selected provider requires.
 //
 //	creds := credentials.NewChainCredentials(
 //	    []credentials.Provider{
@@ -58,9 +60,10 @@ func NewChainCredentials(providers []Provider) *Credentials {
 // RetrieveWithCredContext is like Retrieve with CredContext
 func (c *Chain) RetrieveWithCredContext(cc *CredContext) (Value, error) {
 	for _, p := range c.Providers {
-		creds, _ := p.RetrieveWithCredContext(cc)
-		// Always prioritize non-anonymous providers, if any.
-		if creds.AccessKeyID == "" && creds.SecretAccessKey == "" {
+		creds, err := p.RetrieveWithCredContext(cc)
+		// Fall through on errors and always prioritize
+		// non-anonymous providers, if any.
+		if err != nil || creds.AccessKeyID == "" && creds.SecretAccessKey == "" {
 			continue
 		}
 		c.curr = p
@@ -68,6 +71,7 @@ func (c *Chain) RetrieveWithCredContext(cc *CredContext) (Value, error) {
 	}
 	// At this point we have exhausted all the providers and
 	// are left without any credentials return anonymous.
+	c.curr = nil
 	return Value{
 		SignerType: SignatureAnonymous,
 	}, nil
@@ -80,9 +84,10 @@ func (c *Chain) RetrieveWithCredContext(cc *CredContext) (Value, error) {
 // to IsExpired() will return the expired state of the cached provider.
 func (c *Chain) Retrieve() (Value, error) {
 	for _, p := range c.Providers {
-		creds, _ := p.Retrieve()
-		// Always prioritize non-anonymous providers, if any.
-		if creds.AccessKeyID == "" && creds.SecretAccessKey == "" {
+		creds, err := p.Retrieve()
+		// Fall through on errors and always prioritize
+		// non-anonymous providers, if any.
+		if err != nil || creds.AccessKeyID == "" && creds.SecretAccessKey == "" {
 			continue
 		}
 		c.curr = p
@@ -90,6 +95,7 @@ func (c *Chain) Retrieve() (Value, error) {
 	}
 	// At this point we have exhausted all the providers and
 	// are left without any credentials return anonymous.
+	c.curr = nil
 	return Value{
 		SignerType: SignatureAnonymous,
 	}, nil
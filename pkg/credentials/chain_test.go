@@ -148,3 +148,52 @@ func TestChainProviderWithNoValidProvider(t *testing.T) {
 		}
 	}
 }
+
+func TestChainFallthrough(t *testing.T) {
+	first := &testCredProvider{
+		// Partial credentials returned along with an error must not be used.
+		creds: Value{AccessKeyID: "PARTIAL"},
+		err:   errors.New("FirstError"),
+	}
+	second := &testCredProvider{
+		creds: Value{
+			AccessKeyID:     "AKID",
+			SecretAccessKey: "SECRET",
+			SignerType:      SignatureV2,
+		},
+	}
+	creds := NewChainCredentials([]Provider{first, second})
+
+	v, err := creds.GetWithContext(defaultCredContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.AccessKeyID != "AKID" || !v.SignerType.IsV2() {
+		t.Fatalf("Expected V2 credentials of the second provider, got %+v", v)
+	}
+
+	// Once the active provider expires the chain is evaluated again.
+	first.err = nil
+	first.creds = Value{AccessKeyID: "AKIF", SecretAccessKey: "NOSECRET", SignerType: SignatureV4}
+	if v, _ = creds.GetWithContext(defaultCredContext); v.AccessKeyID != "AKID" {
+		t.Fatalf("Expected cached credentials, got %s", v.AccessKeyID)
+	}
+	second.expired = true
+	if v, err = creds.GetWithContext(defaultCredContext); err != nil {
+		t.Fatal(err)
+	}
+	if v.AccessKeyID != "AKIF" || !v.SignerType.IsV4() {
+		t.Fatalf("Expected V4 credentials of the first provider, got %+v", v)
+	}
+
+	// No provider left, fall back to anonymous access.
+	first.err = errors.New("FirstError")
+	second.err = errors.New("SecondError")
+	first.expired = true
+	if v, err = creds.GetWithContext(defaultCredContext); err != nil {
+		t.Fatal(err)
+	}
+	if !v.SignerType.IsAnonymous() {
+		t.Fatalf("Expected anonymous credentials, got %+v", v)
+	}
+}
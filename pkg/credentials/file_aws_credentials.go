@@ -20,9 +20,12 @@ package credentials
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -41,6 +44,12 @@ type externalProcessCredentials struct {
 // A FileAWSCredentials retrieves credentials from the current user's home
 // directory, and keeps track if those credentials are expired.
 //
+// Profiles are read from the shared credentials file and the shared config
+// file, where sections are named "[profile name]" except for "[default]".
+// Keys of the credentials file take precedence. Besides static keys a
+// profile may use credential_process, or role_arn with source_profile to
+// assume a role with the credentials of another profile.
+//
 // Profile ini file example: $HOME/.aws/credentials
 type FileAWSCredentials struct {
 	Expiry
@@ -53,11 +62,27 @@ type FileAWSCredentials struct {
 	// Windows:   "%USERPROFILE%\.aws\credentials"
 	Filename string
 
+	// Path to the shared config file, it is optional.
+	//
+	// If empty will look for "AWS_CONFIG_FILE" env variable. If the env
+	// value is empty will default to current user's home directory.
+	// Linux/OSX: "$HOME/.aws/config"
+	// Windows:   "%USERPROFILE%\.aws\config"
+	ConfigFilename string
+
 	// AWS Profile to extract credentials from the shared credentials file. If empty
 	// will default to environment variable "AWS_PROFILE" or "default" if
 	// environment variable is also not set.
 	Profile string
 
+	// Optional http Client to use when assuming a role
+	// (overrides default client in CredContext)
+	Client *http.Client
+
+	// Optional STS endpoint to assume roles with, defaults to the
+	// regional AWS STS endpoint of the profile.
+	STSEndpoint string
+
 	// retrieved states if the credentials have been successfully retrieved.
 	retrieved bool
 }
@@ -71,7 +96,7 @@ func NewFileAWSCredentials(filename, profile string) *Credentials {
 	})
 }
 
-func (p *FileAWSCredentials) retrieve() (Value, error) {
+func (p *FileAWSCredentials) retrieve(cc *CredContext) (Value, error) {
 	if p.Filename == "" {
 		p.Filename = os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
 		if p.Filename == "" {
@@ -82,6 +107,14 @@ func (p *FileAWSCredentials) retrieve() (Value, error) {
 			p.Filename = filepath.Join(homeDir, ".aws", "credentials")
 		}
 	}
+	if p.ConfigFilename == "" {
+		p.ConfigFilename = os.Getenv("AWS_CONFIG_FILE")
+		if p.ConfigFilename == "" {
+			if homeDir, err := os.UserHomeDir(); err == nil {
+				p.ConfigFilename = filepath.Join(homeDir, ".aws", "config")
+			}
+		}
+	}
 	if p.Profile == "" {
 		p.Profile = os.Getenv("AWS_PROFILE")
 		if p.Profile == "" {
@@ -90,52 +123,159 @@ func (p *FileAWSCredentials) retrieve() (Value, error) {
 	}
 
 	p.retrieved = false
+	// Static credentials are read again from the files on every call.
+	p.SetExpiration(time.Time{}, 0)
 
-	iniProfile, err := loadProfile(p.Filename, p.Profile)
+	files, err := loadAWSSharedFiles(p.Filename, p.ConfigFilename)
 	if err != nil {
 		return Value{}, err
 	}
 
-	// Default to empty string if not found.
-	id := iniProfile.Key("aws_access_key_id")
-	// Default to empty string if not found.
-	secret := iniProfile.Key("aws_secret_access_key")
-	// Default to empty string if not found.
-	token := iniProfile.Key("aws_session_token")
+	v, err := p.retrieveProfile(cc, files, p.Profile, map[string]bool{})
+	if err != nil {
+		return Value{}, err
+	}
+	p.retrieved = true
+	if !v.Expiration.IsZero() {
+		p.SetExpiration(v.Expiration, DefaultExpiryWindow)
+	}
+	return v, nil
+}
 
-	// If credential_process is defined, obtain credentials by executing
-	// the external process
-	credentialProcess := strings.TrimSpace(iniProfile.Key("credential_process").String())
-	if credentialProcess != "" {
-		args := strings.Fields(credentialProcess)
-		if len(args) <= 1 {
-			return Value{}, errors.New("invalid credential process args")
+// retrieveProfile returns the credentials of the named profile, visited
+// holds the profiles of the source_profile chain to detect loops.
+func (p *FileAWSCredentials) retrieveProfile(cc *CredContext, files awsSharedFiles, name string, visited map[string]bool) (Value, error) {
+	if visited[name] {
+		return Value{}, fmt.Errorf("source_profile loop detected at profile %s", name)
+	}
+	visited[name] = true
+
+	keys, err := files.profile(name)
+	if err != nil {
+		return Value{}, err
+	}
+
+	if roleARN := keys["role_arn"]; roleARN != "" {
+		source := keys["source_profile"]
+		if source == "" {
+			return Value{}, fmt.Errorf("profile %s: role_arn requires source_profile", name)
 		}
-		cmd := exec.Command(args[0], args[1:]...)
-		out, err := cmd.Output()
-		if err != nil {
+		var sourceCreds Value
+		if source == name {
+			// A profile may assume a role with its own static keys.
+			sourceCreds = staticProfileCredentials(keys)
+		} else if sourceCreds, err = p.retrieveProfile(cc, files, source, visited); err != nil {
 			return Value{}, err
 		}
-		var externalProcessCredentials externalProcessCredentials
-		err = json.Unmarshal([]byte(out), &externalProcessCredentials)
+		return p.assumeRole(cc, keys, sourceCreds)
+	}
+
+	// If credential_process is defined, obtain credentials by executing
+	// the external process
+	if credentialProcess := strings.TrimSpace(keys["credential_process"]); credentialProcess != "" {
+		return processCredentials(credentialProcess)
+	}
+
+	return staticProfileCredentials(keys), nil
+}
+
+// staticProfileCredentials returns the keys stored in the profile,
+// empty strings if not found.
+func staticProfileCredentials(keys map[string]string) Value {
+	return Value{
+		AccessKeyID:     keys["aws_access_key_id"],
+		SecretAccessKey: keys["aws_secret_access_key"],
+		SessionToken:    keys["aws_session_token"],
+		SignerType:      SignatureV4,
+	}
+}
+
+// processCredentials executes the credential_process of a profile.
+func processCredentials(credentialProcess string) (Value, error) {
+	args := strings.Fields(credentialProcess)
+	if len(args) <= 1 {
+		return Value{}, errors.New("invalid credential process args")
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	out, err := cmd.Output()
+	if err != nil {
+		return Value{}, err
+	}
+	var externalProcessCredentials externalProcessCredentials
+	err = json.Unmarshal([]byte(out), &externalProcessCredentials)
+	if err != nil {
+		return Value{}, err
+	}
+	return Value{
+		AccessKeyID:     externalProcessCredentials.AccessKeyID,
+		SecretAccessKey: externalProcessCredentials.SecretAccessKey,
+		SessionToken:    externalProcessCredentials.SessionToken,
+		Expiration:      externalProcessCredentials.Expiration,
+		SignerType:      SignatureV4,
+	}, nil
+}
+
+// assumeRole assumes the role_arn of the profile with the source credentials.
+func (p *FileAWSCredentials) assumeRole(cc *CredContext, keys map[string]string, sourceCreds Value) (Value, error) {
+	if cc == nil {
+		cc = defaultCredContext
+	}
+	client := p.Client
+	if client == nil {
+		client = cc.Client
+	}
+	if client == nil {
+		client = defaultCredContext.Client
+	}
+
+	region := keys["region"]
+	endpoint := p.STSEndpoint
+	if endpoint == "" {
+		switch {
+		case strings.HasPrefix(region, "cn-"):
+			endpoint = "https://sts." + region + ".amazonaws.com.cn"
+		case region != "":
+			endpoint = "https://sts." + region + ".amazonaws.com"
+		default:
+			endpoint = DefaultSTSRoleEndpoint
+		}
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	opts := STSAssumeRoleOptions{
+		AccessKey:       sourceCreds.AccessKeyID,
+		SecretKey:       sourceCreds.SecretAccessKey,
+		SessionToken:    sourceCreds.SessionToken,
+		Location:        region,
+		RoleARN:         keys["role_arn"],
+		RoleSessionName: keys["role_session_name"],
+		ExternalID:      keys["external_id"],
+	}
+	if opts.AccessKey == "" || opts.SecretKey == "" {
+		return Value{}, fmt.Errorf("no source credentials to assume role %s", opts.RoleARN)
+	}
+	if opts.RoleSessionName == "" {
+		opts.RoleSessionName = "minio-go-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	if duration := keys["duration_seconds"]; duration != "" {
+		seconds, err := strconv.Atoi(duration)
 		if err != nil {
-			return Value{}, err
+			return Value{}, fmt.Errorf("invalid duration_seconds %s: %w", duration, err)
 		}
-		p.retrieved = true
-		p.SetExpiration(externalProcessCredentials.Expiration, DefaultExpiryWindow)
-		return Value{
-			AccessKeyID:     externalProcessCredentials.AccessKeyID,
-			SecretAccessKey: externalProcessCredentials.SecretAccessKey,
-			SessionToken:    externalProcessCredentials.SessionToken,
-			Expiration:      externalProcessCredentials.Expiration,
-			SignerType:      SignatureV4,
-		}, nil
+		opts.DurationSeconds = seconds
+	}
+
+	a, err := getAssumeRoleCredentials(client, endpoint, opts)
+	if err != nil {
+		return Value{}, err
 	}
-	p.retrieved = true
 	return Value{
-		AccessKeyID:     id.String(),
-		SecretAccessKey: secret.String(),
-		SessionToken:    token.String(),
+		AccessKeyID:     a.Result.Credentials.AccessKey,
+		SecretAccessKey: a.Result.Credentials.SecretKey,
+		SessionToken:    a.Result.Credentials.SessionToken,
+		Expiration:      a.Result.Credentials.Expiration,
 		SignerType:      SignatureV4,
 	}, nil
 }
@@ -143,25 +283,68 @@ func (p *FileAWSCredentials) retrieve() (Value, error) {
 // Retrieve reads and extracts the shared credentials from the current
 // users home directory.
 func (p *FileAWSCredentials) Retrieve() (Value, error) {
-	return p.retrieve()
+	return p.retrieve(nil)
 }
 
-// RetrieveWithCredContext is like Retrieve(), cred context is no-op for File credentials
-func (p *FileAWSCredentials) RetrieveWithCredContext(_ *CredContext) (Value, error) {
-	return p.retrieve()
+// RetrieveWithCredContext is like Retrieve(), the cred context is only
+// used to assume roles configured in the profile.
+func (p *FileAWSCredentials) RetrieveWithCredContext(cc *CredContext) (Value, error) {
+	return p.retrieve(cc)
 }
 
-// loadProfiles loads from the file pointed to by shared credentials filename for profile.
-// The credentials retrieved from the profile will be returned or error. Error will be
-// returned if it fails to read from the file, or the data is invalid.
-func loadProfile(filename, profile string) (*ini.Section, error) {
-	config, err := ini.Load(filename)
-	if err != nil {
-		return nil, err
+// awsSharedFiles holds the parsed shared credentials and config files.
+type awsSharedFiles struct {
+	credentials *ini.File
+	config      *ini.File
+}
+
+// loadAWSSharedFiles loads the shared credentials file and the optional
+// shared config file. Error will be returned if it fails to read from the
+// credentials file, unless the config file exists.
+func loadAWSSharedFiles(credentialsFile, configFile string) (files awsSharedFiles, err error) {
+	files.credentials, err = ini.Load(credentialsFile)
+	if configFile != "" {
+		if config, cerr := ini.Load(configFile); cerr == nil {
+			files.config = config
+		} else if !os.IsNotExist(cerr) {
+			return files, cerr
+		}
 	}
-	iniProfile, err := config.GetSection(profile)
-	if err != nil {
-		return nil, err
+	if err != nil && (files.config == nil || !os.IsNotExist(err)) {
+		return files, err
+	}
+	return files, nil
+}
+
+// profile returns the keys of the named profile, keys of the credentials
+// file take precedence over the "[profile name]" section of the config file.
+func (f awsSharedFiles) profile(name string) (map[string]string, error) {
+	var sections []*ini.Section
+	if f.config != nil {
+		for _, sectionName := range []string{"profile " + name, name} {
+			if sectionName == name && name != "default" {
+				// Only the default profile is named without prefix.
+				continue
+			}
+			if section, err := f.config.GetSection(sectionName); err == nil {
+				sections = append(sections, section)
+				break
+			}
+		}
+	}
+	if f.credentials != nil {
+		if section, err := f.credentials.GetSection(name); err == nil {
+			sections = append(sections, section)
+		}
+	}
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("section %q does not exist", name)
+	}
+	keys := make(map[string]string)
+	for _, section := range sections {
+		for k, v := range section.KeysHash() {
+			keys[k] = v
+		}
 	}
-	return iniProfile, nil
+	return keys, nil
 }
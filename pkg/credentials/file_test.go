@@ -21,7 +21,10 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestFileAWS(t *testing.T) {
@@ -212,3 +215,115 @@ func TestFileMinioClient(t *testing.T) {
 		t.Error("Should be expired if not loaded")
 	}
 }
+
+func TestFileAWSConfig(t *testing.T) {
+	os.Clearenv()
+	dir := t.TempDir()
+	srv, requests := newSTSServer(t, "AssumeRole", assumeRoleRespTmpl, time.Hour, 0)
+
+	credentialsFile := filepath.Join(dir, "credentials")
+	if err := os.WriteFile(credentialsFile, []byte(`# static keys
+[base]
+aws_access_key_id = baseAccessKey
+aws_secret_access_key = baseSecret
+
+[session]
+aws_access_key_id = sessionAccessKey
+aws_secret_access_key = sessionSecret
+aws_session_token = sessionToken
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(dir, "config")
+	if err := os.WriteFile(configFile, []byte(`; profiles of the config file
+[default]
+aws_access_key_id = defaultAccessKey
+aws_secret_access_key = defaultSecret
+
+[profile static]
+# only defined in the config file
+aws_access_key_id = staticAccessKey
+aws_secret_access_key = staticSecret
+
+[profile session]
+aws_session_token = overriddenToken
+
+[profile role]
+role_arn = arn:aws:iam::123456789012:role/test
+source_profile = base
+role_session_name = test-session
+region = us-east-1
+
+[profile loop]
+role_arn = arn:aws:iam::123456789012:role/test
+source_profile = loop2
+
+[profile loop2]
+role_arn = arn:aws:iam::123456789012:role/test
+source_profile = loop
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		profile      string
+		accessKey    string
+		secretKey    string
+		sessionToken string
+	}{
+		{"default", "defaultAccessKey", "defaultSecret", ""},
+		{"static", "staticAccessKey", "staticSecret", ""},
+		{"session", "sessionAccessKey", "sessionSecret", "sessionToken"},
+		{"role", "accessKey1", "secretKey1", "token1"},
+	}
+	for _, testCase := range testCases {
+		provider := &FileAWSCredentials{
+			Filename:       credentialsFile,
+			ConfigFilename: configFile,
+			Profile:        testCase.profile,
+			STSEndpoint:    srv.URL,
+		}
+		creds := New(provider)
+		v, err := creds.GetWithContext(defaultCredContext)
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.profile, err)
+		}
+		if v.AccessKeyID != testCase.accessKey || v.SecretAccessKey != testCase.secretKey || v.SessionToken != testCase.sessionToken {
+			t.Errorf("%s: unexpected credentials %+v", testCase.profile, v)
+		}
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Fatalf("Expected 1 AssumeRole request, got %d", n)
+	}
+
+	os.Setenv("AWS_PROFILE", "role")
+	os.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+	os.Setenv("AWS_CONFIG_FILE", configFile)
+	provider := &FileAWSCredentials{STSEndpoint: srv.URL}
+	creds := New(provider)
+	v, err := creds.GetWithContext(defaultCredContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.AccessKeyID != "accessKey2" {
+		t.Fatalf("Expected assumed role credentials, got %+v", v)
+	}
+	if creds.IsExpired() {
+		t.Fatal("Assumed role credentials should not be expired")
+	}
+	provider.CurrentTime = func() time.Time { return time.Now().Add(55 * time.Minute) }
+	if !creds.IsExpired() {
+		t.Fatal("Assumed role credentials should expire before their expiration")
+	}
+
+	os.Clearenv()
+	creds = New(&FileAWSCredentials{
+		Filename:       credentialsFile,
+		ConfigFilename: configFile,
+		Profile:        "loop",
+		STSEndpoint:    srv.URL,
+	})
+	if _, err = creds.GetWithContext(defaultCredContext); err == nil || !strings.Contains(err.Error(), "loop") {
+		t.Fatalf("Expected source_profile loop error, got %v", err)
+	}
+}
@@ -108,7 +108,7 @@ func (m *IAM) RetrieveWithCredContext(cc *CredContext) (Value, error) {
 
 	tokenFile := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE")
 	if tokenFile == "" {
-		tokenFile = m.Container.AuthorizationToken
+		tokenFile = m.Container.AuthorizationTokenFile
 	}
 
 	relativeURI := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI")
@@ -361,7 +361,13 @@ func fetchIMDSToken(client *http.Client, endpoint string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	if resp.StatusCode != http.StatusOK {
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		// IMDSv2 is not supported by this metadata service,
+		// continue without a token using IMDSv1.
+		return "", nil
+	default:
 		return "", errors.New(resp.Status)
 	}
 	return string(data), nil
@@ -385,6 +391,10 @@ func getCredentials(client *http.Client, endpoint string) (ec2RoleCredRespBody,
 		// to rely on IMDSv1 behavior as a fallback, this check ensures that.
 		// Refer https://github.com/minio/minio-go/issues/1866
 		if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+			var opErr *net.OpError
+			if errors.As(err, &opErr) && opErr.Op == "dial" {
+				return ec2RoleCredRespBody{}, fmt.Errorf("instance metadata service %s is not reachable: %w", endpoint, err)
+			}
 			return ec2RoleCredRespBody{}, err
 		}
 	}
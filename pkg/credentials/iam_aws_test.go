@@ -25,7 +25,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -419,3 +422,128 @@ func TestIMDSv1Blocked(t *testing.T) {
 		t.Errorf("Unexpected IMDSv2 failure %s", err)
 	}
 }
+
+func TestIMDSv2Refresh(t *testing.T) {
+	var tokenRequests, credRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == TokenPath && r.Method == http.MethodPut {
+			if r.Header.Get(TokenRequestTTLHeader) != TokenTTL {
+				http.Error(w, "", http.StatusBadRequest)
+				return
+			}
+			atomic.AddInt32(&tokenRequests, 1)
+			w.Write([]byte("token"))
+			return
+		}
+		if r.Header.Get(TokenRequestHeader) != "token" {
+			http.Error(w, r.URL.Path, http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case DefaultIAMSecurityCredsPath:
+			fmt.Fprintln(w, "RoleName")
+		case DefaultIAMSecurityCredsPath + "RoleName":
+			atomic.AddInt32(&credRequests, 1)
+			fmt.Fprintf(w, credsRespTmpl, time.Now().UTC().Add(time.Hour).Format(time.RFC3339))
+		default:
+			http.Error(w, "bad request", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	p := &IAM{Endpoint: server.URL}
+	p.CurrentTime = func() time.Time { return now }
+	creds := New(p)
+
+	for i := 0; i < 2; i++ {
+		if _, err := creds.GetWithContext(defaultCredContext); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&credRequests); n != 1 {
+		t.Fatalf("Expected cached credentials, got %d credential requests", n)
+	}
+
+	// Past 80% of the lifetime the credentials are refreshed.
+	now = now.Add(50 * time.Minute)
+	if _, err := creds.GetWithContext(defaultCredContext); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&credRequests); n != 2 {
+		t.Fatalf("Expected refreshed credentials, got %d credential requests", n)
+	}
+	if n := atomic.LoadInt32(&tokenRequests); n != 2 {
+		t.Fatalf("Expected a token handshake per refresh, got %d", n)
+	}
+}
+
+func TestIMDSv2Unsupported(t *testing.T) {
+	testCases := []struct {
+		tokenStatus int
+		shouldPass  bool
+	}{
+		// IMDSv1 only services, fall back to requests without a token.
+		{http.StatusNotFound, true},
+		{http.StatusMethodNotAllowed, true},
+		// Metadata service is disabled.
+		{http.StatusForbidden, false},
+	}
+	for _, testCase := range testCases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case TokenPath:
+				w.WriteHeader(testCase.tokenStatus)
+			case DefaultIAMSecurityCredsPath:
+				fmt.Fprintln(w, "RoleName")
+			case DefaultIAMSecurityCredsPath + "RoleName":
+				fmt.Fprintf(w, credsRespTmpl, "2014-12-16T01:51:37Z")
+			}
+		}))
+		_, err := (&IAM{Endpoint: server.URL}).RetrieveWithCredContext(defaultCredContext)
+		server.Close()
+		if testCase.shouldPass != (err == nil) {
+			t.Errorf("Token status %d: expected shouldPass %t, got %v", testCase.tokenStatus, testCase.shouldPass, err)
+		}
+	}
+}
+
+func TestIMDSUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	endpoint := server.URL
+	server.Close()
+
+	_, err := (&IAM{Endpoint: endpoint}).RetrieveWithCredContext(defaultCredContext)
+	if err == nil || !strings.Contains(err.Error(), "not reachable") {
+		t.Fatalf("Expected an unreachable metadata service error, got %v", err)
+	}
+}
+
+func TestEcsTaskAuthorizationTokenFile(t *testing.T) {
+	for _, env := range []string{"AWS_CONTAINER_AUTHORIZATION_TOKEN", "AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "AWS_CONTAINER_CREDENTIALS_FULL_URI", "AWS_WEB_IDENTITY_TOKEN_FILE"} {
+		t.Setenv(env, "")
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "pod-token" {
+			http.Error(w, "", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, credsRespEcsTaskTmpl, "2014-12-16T01:51:37Z")
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("pod-token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p := &IAM{}
+	p.Container.AuthorizationTokenFile = tokenFile
+	p.Container.CredentialsFullURI = server.URL
+	v, err := p.RetrieveWithCredContext(defaultCredContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.AccessKeyID != "accessKey" || v.SessionToken != "token" {
+		t.Fatalf("Unexpected credentials %+v", v)
+	}
+}
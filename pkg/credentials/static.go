@@ -37,6 +37,12 @@ func NewStaticV4(id, secret, token string) *Credentials {
 	return NewStatic(id, secret, token, SignatureV4)
 }
 
+// NewAnonymous returns a pointer to a new Credentials object which
+// never signs requests, used to access public buckets and objects.
+func NewAnonymous() *Credentials {
+	return NewStatic("", "", "", SignatureAnonymous)
+}
+
 // NewStatic returns a pointer to a new Credentials object
 // wrapping a static credentials value provider.
 func NewStatic(id, secret, token string, signerType SignatureType) *Credentials {
@@ -66,3 +66,16 @@ func TestStaticGet(t *testing.T) {
 		t.Error("Static credentials should never expire")
 	}
 }
+
+func TestNewAnonymous(t *testing.T) {
+	credValues, err := NewAnonymous().GetWithContext(defaultCredContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !credValues.SignerType.IsAnonymous() {
+		t.Errorf("Expected 'Anonymous', got %s", credValues.SignerType)
+	}
+	if credValues.AccessKeyID != "" || credValues.SecretAccessKey != "" {
+		t.Errorf("Expected empty keys, got %q and %q", credValues.AccessKeyID, credValues.SecretAccessKey)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package credentials
+
+import (
+	"net/http"
+	"time"
+)
+
+// Number of attempts and the base back off delay for STS requests
+// failing with a transient error.
+var (
+	stsMaxAttempts = 3
+	stsRetryUnit   = 100 * time.Millisecond
+)
+
+// isSTSStatusRetryable returns true for responses reporting that
+// the STS service is throttling or temporarily unavailable.
+func isSTSStatusRetryable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// doSTSRequest sends the STS request, retrying connection errors
+// and transient error responses with an exponential back off. The
+// response of the last attempt is returned as is.
+func doSTSRequest(clnt *http.Client, req *http.Request) (resp *http.Response, err error) {
+	for attempt := 0; attempt < stsMaxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(stsRetryUnit << (attempt - 1))
+			if req.GetBody != nil {
+				if req.Body, err = req.GetBody(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		resp, err = clnt.Do(req)
+		last := attempt == stsMaxAttempts-1 || (req.Body != nil && req.GetBody == nil)
+		if err != nil {
+			if last {
+				return nil, err
+			}
+			continue
+		}
+		if !isSTSStatusRetryable(resp.StatusCode) || last {
+			return resp, nil
+		}
+		closeResponse(resp)
+	}
+	return resp, err
+}
@@ -91,6 +91,10 @@ type STSWebIdentity struct {
 	// Policy is the policy where the credentials should be limited too.
 	Policy string
 
+	// ExpiryWindow is how long before expiry the credentials are
+	// refreshed, defaults to DefaultExpiryWindow.
+	ExpiryWindow time.Duration
+
 	// roleSessionName is the identifier for the assumed role session.
 	roleSessionName string
 }
@@ -134,6 +138,14 @@ func WithPolicy(policy string) func(*STSWebIdentity) {
 	}
 }
 
+// WithExpiryWindow option refreshes the credentials the given
+// duration before they expire.
+func WithExpiryWindow(window time.Duration) func(*STSWebIdentity) {
+	return func(i *STSWebIdentity) {
+		i.ExpiryWindow = window
+	}
+}
+
 func getWebIdentityCredentials(clnt *http.Client, endpoint, roleARN, roleSessionName string, policy string,
 	getWebIDTokenExpiry func() (*WebIdentityToken, error),
 ) (AssumeRoleWithWebIdentityResponse, error) {
@@ -181,7 +193,7 @@ func getWebIdentityCredentials(clnt *http.Client, endpoint, roleARN, roleSession
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := clnt.Do(req)
+	resp, err := doSTSRequest(clnt, req)
 	if err != nil {
 		return AssumeRoleWithWebIdentityResponse{}, err
 	}
@@ -241,8 +253,11 @@ func (m *STSWebIdentity) RetrieveWithCredContext(cc *CredContext) (Value, error)
 		return Value{}, err
 	}
 
-	// Expiry window is set to 10secs.
-	m.SetExpiration(a.Result.Credentials.Expiration, DefaultExpiryWindow)
+	window := m.ExpiryWindow
+	if window == 0 {
+		window = DefaultExpiryWindow
+	}
+	m.SetExpiration(a.Result.Credentials.Expiration, window)
 
 	return Value{
 		AccessKeyID:     a.Result.Credentials.AccessKey,
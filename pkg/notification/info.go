@@ -17,6 +17,8 @@
 
 package notification
 
+import "strings"
+
 // Indentity represents the user id, this is a compliance field.
 type identity struct {
 	PrincipalID string `json:"principalId"`
@@ -76,3 +78,13 @@ type Info struct {
 	Records []Event
 	Err     error
 }
+
+// Type returns the EventType of the event. Amazon S3 reports event
+// names without the "s3:" prefix, which is added here so that the
+// value can be compared against the EventType constants.
+func (e Event) Type() EventType {
+	if e.EventName == "" || strings.HasPrefix(e.EventName, "s3:") {
+		return EventType(e.EventName)
+	}
+	return EventType("s3:" + e.EventName)
+}
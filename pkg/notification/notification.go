@@ -99,7 +99,8 @@ type Arn struct {
 	Resource  string
 }
 
-// NewArn creates new ARN based on the given partition, service, region, account id and resource
+// NewArn creates new ARN based on the given partition, service, region, account id and resource.
+// The components are not checked, use Validate to verify the resulting ARN.
 func NewArn(partition, service, region, accountID, resource string) Arn {
 	return Arn{
 		Partition: partition,
@@ -115,12 +116,17 @@ var (
 	ErrInvalidArnPrefix = errors.New("invalid ARN format, must start with 'arn:'")
 	// ErrInvalidArnFormat is returned when ARN string format is not valid
 	ErrInvalidArnFormat = errors.New("invalid ARN format, must be 'arn:<partition>:<service>:<region>:<accountID>:<resource>'")
+	// ErrInvalidFilterRuleName is returned when a filter rule is neither 'prefix' nor 'suffix'
+	ErrInvalidFilterRuleName = errors.New("invalid filter rule name, must be 'prefix' or 'suffix'")
+	// ErrDuplicateFilterRule is returned when a config has more than one prefix or suffix filter rule
+	ErrDuplicateFilterRule = errors.New("only one prefix and one suffix filter rule are allowed per notification config")
 )
 
 // NewArnFromString parses string representation of ARN into Arn object.
 // Returns an error if the string format is incorrect.
 func NewArnFromString(arn string) (Arn, error) {
-	parts := strings.Split(arn, ":")
+	// The resource may contain ':' as in Lambda function ARNs.
+	parts := strings.SplitN(arn, ":", 6)
 	if len(parts) != 6 {
 		return Arn{}, ErrInvalidArnFormat
 	}
@@ -128,7 +134,60 @@ func NewArnFromString(arn string) (Arn, error) {
 		return Arn{}, ErrInvalidArnPrefix
 	}
 
-	return NewArn(parts[1], parts[2], parts[3], parts[4], parts[5]), nil
+	newArn := NewArn(parts[1], parts[2], parts[3], parts[4], parts[5])
+	if err := newArn.Validate(); err != nil {
+		return Arn{}, err
+	}
+	return newArn, nil
+}
+
+// InvalidArnError is returned when one or more components of an ARN
+// are malformed, Components lists the names of the malformed parts.
+type InvalidArnError struct {
+	Arn        string
+	Components []string
+}
+
+func (e InvalidArnError) Error() string {
+	return fmt.Sprintf("invalid ARN '%s', malformed %s", e.Arn, strings.Join(e.Components, ", "))
+}
+
+// isArnIdentifier reports whether s only has lower case letters,
+// digits and hyphens, as used by ARN partitions, services and regions.
+func isArnIdentifier(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks the structure of the ARN, the partition, service and
+// resource are mandatory while region and account id may be empty as
+// in MinIO target ARNs like 'arn:minio:sqs::primary:webhook'. An
+// InvalidArnError listing the malformed components is returned.
+func (arn Arn) Validate() error {
+	var malformed []string
+	if arn.Partition == "" || !isArnIdentifier(arn.Partition) {
+		malformed = append(malformed, "partition")
+	}
+	if arn.Service == "" || !isArnIdentifier(arn.Service) {
+		malformed = append(malformed, "service")
+	}
+	if !isArnIdentifier(arn.Region) {
+		malformed = append(malformed, "region")
+	}
+	if strings.ContainsAny(arn.AccountID, ": \t\r\n") {
+		malformed = append(malformed, "account")
+	}
+	if arn.Resource == "" || strings.ContainsAny(arn.Resource, " \t\r\n") {
+		malformed = append(malformed, "resource")
+	}
+	if len(malformed) > 0 {
+		return InvalidArnError{Arn: arn.String(), Components: malformed}
+	}
+	return nil
 }
 
 // String returns the string format of the ARN
@@ -155,36 +214,78 @@ func (t *Config) AddEvents(events ...EventType) {
 	t.Events = append(t.Events, events...)
 }
 
-// AddFilterSuffix sets the suffix configuration to the current notification config
-func (t *Config) AddFilterSuffix(suffix string) {
+// AddFilterRule adds a prefix or suffix filter rule to the current
+// notification config. Unlike AddFilterPrefix and AddFilterSuffix which
+// overwrite an existing rule, an error wrapping ErrDuplicateFilterRule is
+// returned when a rule with the same name is already set.
+func (t *Config) AddFilterRule(name, value string) error {
+	if name != "prefix" && name != "suffix" {
+		return fmt.Errorf("filter rule '%s': %w", name, ErrInvalidFilterRuleName)
+	}
+	for _, rule := range t.FilterRules() {
+		if rule.Name == name {
+			return fmt.Errorf("%s filter rule is already set to '%s': %w", name, rule.Value, ErrDuplicateFilterRule)
+		}
+	}
 	if t.Filter == nil {
 		t.Filter = &Filter{}
 	}
-	newFilterRule := FilterRule{Name: "suffix", Value: suffix}
-	// Replace any suffix rule if existing and add to the list otherwise
-	for index := range t.Filter.S3Key.FilterRules {
-		if t.Filter.S3Key.FilterRules[index].Name == "suffix" {
-			t.Filter.S3Key.FilterRules[index] = newFilterRule
-			return
+	t.Filter.S3Key.FilterRules = append(t.Filter.S3Key.FilterRules, FilterRule{Name: name, Value: value})
+	return nil
+}
+
+// FilterRules returns a copy of the filter rules of the current notification config
+func (t *Config) FilterRules() []FilterRule {
+	if t.Filter == nil || len(t.Filter.S3Key.FilterRules) == 0 {
+		return nil
+	}
+	return append([]FilterRule(nil), t.Filter.S3Key.FilterRules...)
+}
+
+// validateFilterRules checks that the config has at most one prefix
+// and one suffix filter rule, as required by S3.
+func (t *Config) validateFilterRules() error {
+	seen := make(map[string]bool)
+	for _, rule := range t.FilterRules() {
+		if rule.Name != "prefix" && rule.Name != "suffix" {
+			return fmt.Errorf("filter rule '%s': %w", rule.Name, ErrInvalidFilterRuleName)
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("%s filter rule is set more than once: %w", rule.Name, ErrDuplicateFilterRule)
 		}
+		seen[rule.Name] = true
+	}
+	return nil
+}
+
+// AddFilterSuffix sets the suffix configuration to the current notification config,
+// an existing suffix rule is overwritten.
+func (t *Config) AddFilterSuffix(suffix string) {
+	if t.Filter == nil {
+		t.Filter = &Filter{}
 	}
-	t.Filter.S3Key.FilterRules = append(t.Filter.S3Key.FilterRules, newFilterRule)
+	t.Filter.S3Key.setFilterRule("suffix", suffix)
 }
 
-// AddFilterPrefix sets the prefix configuration to the current notification config
+// AddFilterPrefix sets the prefix configuration to the current notification config,
+// an existing prefix rule is overwritten.
 func (t *Config) AddFilterPrefix(prefix string) {
 	if t.Filter == nil {
 		t.Filter = &Filter{}
 	}
-	newFilterRule := FilterRule{Name: "prefix", Value: prefix}
-	// Replace any prefix rule if existing and add to the list otherwise
-	for index := range t.Filter.S3Key.FilterRules {
-		if t.Filter.S3Key.FilterRules[index].Name == "prefix" {
-			t.Filter.S3Key.FilterRules[index] = newFilterRule
-			return
+	t.Filter.S3Key.setFilterRule("prefix", prefix)
+}
+
+// setFilterRule replaces any rule with the given name, dropping
+// duplicates, and adds it to the list otherwise.
+func (k *S3Key) setFilterRule(name, value string) {
+	rules := k.FilterRules[:0]
+	for _, rule := range k.FilterRules {
+		if rule.Name != name {
+			rules = append(rules, rule)
 		}
 	}
-	t.Filter.S3Key.FilterRules = append(t.Filter.S3Key.FilterRules, newFilterRule)
+	k.FilterRules = append(rules, FilterRule{Name: name, Value: value})
 }
 
 // EqualEventTypeList tells whether a and b contain the same events
@@ -277,6 +378,37 @@ type Configuration struct {
 	QueueConfigs  []QueueConfig  `xml:"QueueConfiguration"`
 }
 
+// Validate checks the ARN and the filter rules of every topic, queue and
+// cloud function configuration, see Arn.Validate.
+func (b Configuration) Validate() error {
+	type arnConfig struct {
+		arn    string
+		config Config
+	}
+	var configs []arnConfig
+	for _, c := range b.LambdaConfigs {
+		configs = append(configs, arnConfig{c.Lambda, c.Config})
+	}
+	for _, c := range b.TopicConfigs {
+		configs = append(configs, arnConfig{c.Topic, c.Config})
+	}
+	for _, c := range b.QueueConfigs {
+		configs = append(configs, arnConfig{c.Queue, c.Config})
+	}
+	for _, c := range configs {
+		if _, err := NewArnFromString(c.arn); err != nil {
+			if err == ErrInvalidArnFormat || err == ErrInvalidArnPrefix {
+				return fmt.Errorf("invalid ARN '%s': %w", c.arn, err)
+			}
+			return err
+		}
+		if err := c.config.validateFilterRules(); err != nil {
+			return fmt.Errorf("notification config for '%s': %w", c.arn, err)
+		}
+	}
+	return nil
+}
+
 // AddTopic adds a given topic config to the general bucket notification config
 func (b *Configuration) AddTopic(topicConfig Config) bool {
 	newTopicConfig := TopicConfig{Config: topicConfig, Topic: topicConfig.Arn.String()}
@@ -19,6 +19,8 @@ package notification
 
 import (
 	"encoding/xml"
+	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -1433,3 +1435,133 @@ func TestNewArnFromString(t *testing.T) {
 		}
 	})
 }
+
+func TestArnValidate(t *testing.T) {
+	testCases := []struct {
+		arn        Arn
+		components []string
+	}{
+		{NewArn("aws", "sqs", "us-east-1", "444455556666", "queue1"), nil},
+		{NewArn("minio", "sqs", "", "primary", "webhook"), nil},
+		{NewArn("aws", "lambda", "us-east-1", "444455556666", "function:resize"), nil},
+		{NewArn("", "sqs", "us-east-1", "444455556666", "queue1"), []string{"partition"}},
+		{NewArn("aws", "SQS", "us east 1", "444455556666", "queue1"), []string{"service", "region"}},
+		{NewArn("aws", "sns", "us-east-1", "4444:5555", ""), []string{"account", "resource"}},
+		{NewArn("aws", "sns", "us-east-1", "444455556666", "my topic"), []string{"resource"}},
+	}
+	for i, testCase := range testCases {
+		err := testCase.arn.Validate()
+		if testCase.components == nil {
+			if err != nil {
+				t.Fatalf("Test %d: unexpected error %v", i+1, err)
+			}
+			continue
+		}
+		arnErr, ok := err.(InvalidArnError)
+		if !ok {
+			t.Fatalf("Test %d: expected InvalidArnError, got %v", i+1, err)
+		}
+		if !reflect.DeepEqual(arnErr.Components, testCase.components) {
+			t.Fatalf("Test %d: expected malformed %v, got %v", i+1, testCase.components, arnErr.Components)
+		}
+	}
+
+	if _, err := NewArnFromString("arn:aws:sqs:us-east-1:444455556666:"); err == nil {
+		t.Fatal("Expected ARN without resource to fail")
+	}
+	arn, err := NewArnFromString("arn:aws:lambda:us-east-1:444455556666:function:resize")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if arn.Resource != "function:resize" {
+		t.Fatalf("Expected resource function:resize, got %s", arn.Resource)
+	}
+}
+
+func TestConfigurationValidate(t *testing.T) {
+	config := Configuration{}
+	config.AddQueue(NewConfig(NewArn("minio", "sqs", "", "primary", "webhook")))
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	config.AddTopic(NewConfig(NewArn("aws", "sns", "us_east_1", "444455556666", "topic")))
+	err := config.Validate()
+	if _, ok := err.(InvalidArnError); !ok {
+		t.Fatalf("Expected InvalidArnError, got %v", err)
+	}
+	config = Configuration{QueueConfigs: []QueueConfig{{Queue: "sqs:webhook"}}}
+	if err = config.Validate(); !errors.Is(err, ErrInvalidArnFormat) {
+		t.Fatalf("Expected %v, got %v", ErrInvalidArnFormat, err)
+	}
+}
+
+func TestAddFilterDeduplicates(t *testing.T) {
+	config := NewConfig(NewArn("minio", "sqs", "", "primary", "webhook"))
+	config.Filter.S3Key.FilterRules = []FilterRule{
+		{Name: "prefix", Value: "a/"},
+		{Name: "prefix", Value: "b/"},
+		{Name: "suffix", Value: ".png"},
+	}
+	config.AddFilterPrefix("photos/")
+	config.AddFilterSuffix(".jpg")
+	config.AddFilterSuffix(".jpg")
+	expected := []FilterRule{
+		{Name: "prefix", Value: "photos/"},
+		{Name: "suffix", Value: ".jpg"},
+	}
+	if !reflect.DeepEqual(config.Filter.S3Key.FilterRules, expected) {
+		t.Fatalf("Expected %v, got %v", expected, config.Filter.S3Key.FilterRules)
+	}
+	if !config.Equal(nil, "photos/", ".jpg") {
+		t.Fatal("Expected config to match prefix and suffix")
+	}
+}
+
+func TestAddFilterRule(t *testing.T) {
+	config := NewConfig(NewArn("minio", "sqs", "", "primary", "webhook"))
+	if err := config.AddFilterRule("prefix", "photos/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.AddFilterRule("suffix", ".jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.AddFilterRule("prefix", "videos/"); !errors.Is(err, ErrDuplicateFilterRule) {
+		t.Fatalf("Expected %v, got %v", ErrDuplicateFilterRule, err)
+	}
+	if err := config.AddFilterRule("Prefix", "videos/"); !errors.Is(err, ErrInvalidFilterRuleName) {
+		t.Fatalf("Expected %v, got %v", ErrInvalidFilterRuleName, err)
+	}
+
+	// AddFilterPrefix keeps overwriting the existing rule.
+	config.AddFilterPrefix("videos/")
+	expected := []FilterRule{
+		{Name: "suffix", Value: ".jpg"},
+		{Name: "prefix", Value: "videos/"},
+	}
+	rules := config.FilterRules()
+	if !reflect.DeepEqual(rules, expected) {
+		t.Fatalf("Expected %v, got %v", expected, rules)
+	}
+	// The returned rules are a copy.
+	rules[0].Value = ".png"
+	if config.Filter.S3Key.FilterRules[0].Value != ".jpg" {
+		t.Fatal("Expected FilterRules to return a copy")
+	}
+
+	if rules = (&Config{}).FilterRules(); rules != nil {
+		t.Fatalf("Expected no filter rules, got %v", rules)
+	}
+}
+
+func TestConfigurationValidateFilterRules(t *testing.T) {
+	config := NewConfig(NewArn("minio", "sqs", "", "primary", "webhook"))
+	config.Filter.S3Key.FilterRules = []FilterRule{
+		{Name: "prefix", Value: "a/"},
+		{Name: "prefix", Value: "b/"},
+	}
+	notificationConfig := Configuration{}
+	notificationConfig.AddQueue(config)
+	if err := notificationConfig.Validate(); !errors.Is(err, ErrDuplicateFilterRule) {
+		t.Fatalf("Expected %v, got %v", ErrDuplicateFilterRule, err)
+	}
+}
@@ -17,7 +17,12 @@
 
 package policy
 
-import "github.com/minio/minio-go/v7/pkg/set"
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/minio/minio-go/v7/pkg/set"
+)
 
 // ConditionKeyMap - map of policy condition key and value.
 type ConditionKeyMap map[string]set.StringSet
@@ -92,6 +97,56 @@ func (cond ConditionMap) Remove(condKey string) {
 	delete(cond, condKey)
 }
 
+// MarshalJSON - converts to JSON data. Values of numeric conditions
+// such as NumericLessThan and of Bool and Null conditions are written
+// as JSON numbers and booleans, as they appear in the policy document.
+func (cond ConditionMap) MarshalJSON() ([]byte, error) {
+	out := make(map[string]map[string][]interface{}, len(cond))
+	for condKey, condKeyMap := range cond {
+		keyMap := make(map[string][]interface{}, len(condKeyMap))
+		for key, values := range condKeyMap {
+			list := make([]interface{}, 0, len(values))
+			for _, value := range values.ToSlice() {
+				list = append(list, conditionValue(condKey, value))
+			}
+			keyMap[key] = list
+		}
+		out[condKey] = keyMap
+	}
+	return json.Marshal(out)
+}
+
+// conditionValue - returns the typed JSON value of a condition value.
+func conditionValue(condKey, value string) interface{} {
+	// Strip set operator prefixes and the IfExists suffix,
+	// e.g. 'ForAnyValue:NumericLessThanIfExists'.
+	if i := strings.LastIndex(condKey, ":"); i >= 0 {
+		condKey = condKey[i+1:]
+	}
+	condKey = strings.TrimSuffix(condKey, "IfExists")
+
+	switch {
+	case strings.HasPrefix(condKey, "Numeric"):
+		if isJSONNumber(value) {
+			return json.Number(value)
+		}
+	case condKey == "Bool" || condKey == "Null":
+		switch value {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+	}
+	return value
+}
+
+// isJSONNumber - returns whether s is a valid JSON number literal.
+func isJSONNumber(s string) bool {
+	var f json.Number
+	return s != "" && json.Unmarshal([]byte(s), &f) == nil && string(f) == s
+}
+
 // mergeConditionMap - returns new ConditionMap which contains merged key/value of two ConditionMap.
 func mergeConditionMap(condMap1, condMap2 ConditionMap) ConditionMap {
 	out := make(ConditionMap)
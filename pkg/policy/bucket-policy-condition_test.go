@@ -288,3 +288,40 @@ func TestMergeConditionMap(t *testing.T) {
 		}
 	}
 }
+
+// ConditionMap is unmarshaled and marshaled back, typed values are validated.
+func TestConditionMapTypedValues(t *testing.T) {
+	testCases := []struct {
+		data           string
+		expectedResult string
+	}{
+		// TlsVersion numeric condition.
+		{`{"NumericLessThan":{"s3:TlsVersion":1.2}}`, `{"NumericLessThan":{"s3:TlsVersion":[1.2]}}`},
+		// Large numbers are kept as written.
+		{`{"NumericGreaterThanEquals":{"s3:max-keys":[12345678901234567890]}}`, `{"NumericGreaterThanEquals":{"s3:max-keys":[12345678901234567890]}}`},
+		// Set operator prefix and IfExists suffix.
+		{`{"ForAnyValue:NumericLessThanIfExists":{"s3:TlsVersion":[1.2]}}`, `{"ForAnyValue:NumericLessThanIfExists":{"s3:TlsVersion":[1.2]}}`},
+		// Null boolean condition.
+		{`{"Null":{"s3:x-amz-server-side-encryption":[true]}}`, `{"Null":{"s3:x-amz-server-side-encryption":[true]}}`},
+		// Bool condition written as a string.
+		{`{"Bool":{"aws:SecureTransport":"false"}}`, `{"Bool":{"aws:SecureTransport":[false]}}`},
+		// Non numeric values of numeric conditions stay strings.
+		{`{"NumericLessThan":{"s3:TlsVersion":["abc"]}}`, `{"NumericLessThan":{"s3:TlsVersion":["abc"]}}`},
+		// String conditions stay strings.
+		{`{"StringEquals":{"s3:prefix":["1.2","true"]}}`, `{"StringEquals":{"s3:prefix":["1.2","true"]}}`},
+	}
+
+	for i, testCase := range testCases {
+		var condMap ConditionMap
+		if err := json.Unmarshal([]byte(testCase.data), &condMap); err != nil {
+			t.Fatalf("Test %d: unable to unmarshal ConditionMap, %s", i+1, err)
+		}
+		data, err := json.Marshal(condMap)
+		if err != nil {
+			t.Fatalf("Test %d: unable to marshal ConditionMap to JSON, %s", i+1, err)
+		}
+		if string(data) != testCase.expectedResult {
+			t.Fatalf("Test %d: expected: %s, got: %s", i+1, testCase.expectedResult, string(data))
+		}
+	}
+}
@@ -2075,3 +2075,19 @@ func TestBucketPolicyResourceMatch(t *testing.T) {
 		}
 	}
 }
+
+// BucketAccessPolicy with numeric and boolean conditions is unmarshaled and marshaled back.
+func TestBucketAccessPolicyTypedConditions(t *testing.T) {
+	data := `{"Version":"2012-10-17","Statement":[{"Action":["s3:PutObject"],"Condition":{"NumericLessThan":{"s3:TlsVersion":[1.2]}},"Effect":"Deny","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::mybucket/*"],"Sid":""},{"Action":["s3:PutObject"],"Condition":{"Null":{"s3:x-amz-server-side-encryption":[true]}},"Effect":"Deny","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::mybucket/*"],"Sid":""}]}`
+	var policy BucketAccessPolicy
+	if err := json.Unmarshal([]byte(data), &policy); err != nil {
+		t.Fatal(err)
+	}
+	result, err := json.Marshal(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result) != data {
+		t.Fatalf("expected: %s, got: %s", data, string(result))
+	}
+}
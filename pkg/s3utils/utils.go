@@ -348,31 +348,57 @@ var (
 	ipAddress             = regexp.MustCompile(`^(\d+\.){3}\d+$`)
 )
 
+var (
+	// ErrInvalidBucketName is wrapped by the errors of bucket name validation.
+	ErrInvalidBucketName = errors.New("invalid bucket name")
+	// ErrInvalidObjectName is wrapped by the errors of object name validation.
+	ErrInvalidObjectName = errors.New("invalid object name")
+)
+
+// invalidNameError is a validation error with a descriptive message
+// which matches its sentinel error with errors.Is.
+type invalidNameError struct {
+	msg      string
+	sentinel error
+}
+
+func (e invalidNameError) Error() string { return e.msg }
+
+func (e invalidNameError) Unwrap() error { return e.sentinel }
+
+func invalidBucketName(msg string) error {
+	return invalidNameError{msg: msg, sentinel: ErrInvalidBucketName}
+}
+
+func invalidObjectName(msg string) error {
+	return invalidNameError{msg: msg, sentinel: ErrInvalidObjectName}
+}
+
 // Common checker for both stricter and basic validation.
 func checkBucketNameCommon(bucketName string, strict bool) (err error) {
 	if strings.TrimSpace(bucketName) == "" {
-		return errors.New("Bucket name cannot be empty")
+		return invalidBucketName("Bucket name cannot be empty")
 	}
 	if len(bucketName) < 3 {
-		return errors.New("Bucket name cannot be shorter than 3 characters")
+		return invalidBucketName("Bucket name cannot be shorter than 3 characters")
 	}
 	if len(bucketName) > 63 {
-		return errors.New("Bucket name cannot be longer than 63 characters")
+		return invalidBucketName("Bucket name cannot be longer than 63 characters")
 	}
 	if ipAddress.MatchString(bucketName) {
-		return errors.New("Bucket name cannot be an ip address")
+		return invalidBucketName("Bucket name cannot be an ip address")
 	}
 	if strings.Contains(bucketName, "..") || strings.Contains(bucketName, ".-") || strings.Contains(bucketName, "-.") {
-		return errors.New("Bucket name contains invalid characters")
+		return invalidBucketName("Bucket name contains invalid characters")
 	}
 	if strict {
 		if !validBucketNameStrict.MatchString(bucketName) {
-			err = errors.New("Bucket name contains invalid characters")
+			err = invalidBucketName("Bucket name contains invalid characters")
 		}
 		return err
 	}
 	if !validBucketName.MatchString(bucketName) {
-		err = errors.New("Bucket name contains invalid characters")
+		err = invalidBucketName("Bucket name contains invalid characters")
 	}
 	return err
 }
@@ -393,10 +419,10 @@ func CheckValidBucketNameStrict(bucketName string) (err error) {
 //   - http://docs.aws.amazon.com/AmazonS3/latest/dev/UsingMetadata.html
 func CheckValidObjectNamePrefix(objectName string) error {
 	if len(objectName) > 1024 {
-		return errors.New("Object name cannot be longer than 1024 characters")
+		return invalidObjectName("Object name cannot be longer than 1024 characters")
 	}
 	if !utf8.ValidString(objectName) {
-		return errors.New("Object name with non UTF-8 strings are not supported")
+		return invalidObjectName("Object name with non UTF-8 strings are not supported")
 	}
 	return nil
 }
@@ -405,7 +431,7 @@ func CheckValidObjectNamePrefix(objectName string) error {
 //   - http://docs.aws.amazon.com/AmazonS3/latest/dev/UsingMetadata.html
 func CheckValidObjectName(objectName string) error {
 	if strings.TrimSpace(objectName) == "" {
-		return errors.New("Object name cannot be empty")
+		return invalidObjectName("Object name cannot be empty")
 	}
 	return CheckValidObjectNamePrefix(objectName)
 }
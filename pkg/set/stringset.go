@@ -18,6 +18,7 @@
 package set
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 
@@ -147,17 +148,18 @@ func (set StringSet) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON - parses JSON data and creates new set with it.
+// Numbers are kept as written, without losing precision.
 func (set *StringSet) UnmarshalJSON(data []byte) error {
 	sl := []interface{}{}
 	var err error
-	if err = json.Unmarshal(data, &sl); err == nil {
+	if err = unmarshalUseNumber(data, &sl); err == nil {
 		*set = make(StringSet)
 		for _, s := range sl {
 			set.Add(fmt.Sprintf("%v", s))
 		}
 	} else {
 		var s interface{}
-		if err = json.Unmarshal(data, &s); err == nil {
+		if err = unmarshalUseNumber(data, &s); err == nil {
 			*set = make(StringSet)
 			set.Add(fmt.Sprintf("%v", s))
 		}
@@ -166,6 +168,13 @@ func (set *StringSet) UnmarshalJSON(data []byte) error {
 	return err
 }
 
+// unmarshalUseNumber - decodes JSON data, numbers are decoded as json.Number.
+func unmarshalUseNumber(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
 // String - returns printable string of the set.
 func (set StringSet) String() string {
 	return fmt.Sprintf("%s", set.ToSlice())
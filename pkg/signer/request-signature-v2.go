@@ -58,12 +58,18 @@ func encodeURL2Path(req *http.Request, virtualHost bool) (path string) {
 // PreSignV2 - presign the request in following style.
 // https://${S3_BUCKET}.s3.amazonaws.com/${S3_OBJECT}?AWSAccessKeyId=${S3_ACCESS_KEY}&Expires=${TIMESTAMP}&Signature=${SIGNATURE}.
 func PreSignV2(req http.Request, accessKeyID, secretAccessKey string, expires int64, virtualHost bool) *http.Request {
+	return PreSignV2WithTime(req, accessKeyID, secretAccessKey, expires, virtualHost, time.Now())
+}
+
+// PreSignV2WithTime is like PreSignV2 but computes the expiry relative
+// to the provided time instead of the current time.
+func PreSignV2WithTime(req http.Request, accessKeyID, secretAccessKey string, expires int64, virtualHost bool, signTime time.Time) *http.Request {
 	// Presign is not needed for anonymous credentials.
 	if accessKeyID == "" || secretAccessKey == "" {
 		return &req
 	}
 
-	d := time.Now().UTC()
+	d := signTime.UTC()
 	// Find epoch expires when the request will expire.
 	epochExpires := d.Unix() + expires
 
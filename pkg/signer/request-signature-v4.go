@@ -206,13 +206,19 @@ func getStringToSignV4(t time.Time, location, canonicalRequest, serviceType stri
 // PreSignV4 presign the request, in accordance with
 // http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-query-string-auth.html.
 func PreSignV4(req http.Request, accessKeyID, secretAccessKey, sessionToken, location string, expires int64) *http.Request {
+	return PreSignV4WithTime(req, accessKeyID, secretAccessKey, sessionToken, location, expires, time.Now())
+}
+
+// PreSignV4WithTime is like PreSignV4 but signs the request with the
+// provided time instead of the current time.
+func PreSignV4WithTime(req http.Request, accessKeyID, secretAccessKey, sessionToken, location string, expires int64, signTime time.Time) *http.Request {
 	// Presign is not needed for anonymous credentials.
 	if accessKeyID == "" || secretAccessKey == "" {
 		return &req
 	}
 
 	// Initial time.
-	t := time.Now().UTC()
+	t := signTime.UTC()
 
 	// Get credential string.
 	credential := GetCredential(accessKeyID, location, t, ServiceTypeS3)
@@ -263,18 +269,18 @@ func PostPresignSignatureV4(policyBase64 string, t time.Time, secretAccessKey, l
 
 // SignV4STS - signature v4 for STS request.
 func SignV4STS(req http.Request, accessKeyID, secretAccessKey, location string) *http.Request {
-	return signV4(req, accessKeyID, secretAccessKey, "", location, ServiceTypeSTS, nil)
+	return signV4(req, accessKeyID, secretAccessKey, "", location, ServiceTypeSTS, nil, time.Now())
 }
 
 // Internal function called for different service types.
-func signV4(req http.Request, accessKeyID, secretAccessKey, sessionToken, location, serviceType string, trailer http.Header) *http.Request {
+func signV4(req http.Request, accessKeyID, secretAccessKey, sessionToken, location, serviceType string, trailer http.Header, signTime time.Time) *http.Request {
 	// Signature calculation is not needed for anonymous credentials.
 	if accessKeyID == "" || secretAccessKey == "" {
 		return &req
 	}
 
 	// Initial time.
-	t := time.Now().UTC()
+	t := signTime.UTC()
 
 	// Set x-amz-date.
 	req.Header.Set("X-Amz-Date", t.Format(iso8601DateFormat))
@@ -333,7 +339,7 @@ func signV4(req http.Request, accessKeyID, secretAccessKey, sessionToken, locati
 	if len(trailer) > 0 {
 		// Use custom chunked encoding.
 		req.Trailer = trailer
-		return StreamingUnsignedV4(&req, sessionToken, req.ContentLength, time.Now().UTC())
+		return StreamingUnsignedV4(&req, sessionToken, req.ContentLength, t)
 	}
 	return &req
 }
@@ -341,11 +347,17 @@ func signV4(req http.Request, accessKeyID, secretAccessKey, sessionToken, locati
 // SignV4 sign the request before Do(), in accordance with
 // http://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html.
 func SignV4(req http.Request, accessKeyID, secretAccessKey, sessionToken, location string) *http.Request {
-	return signV4(req, accessKeyID, secretAccessKey, sessionToken, location, ServiceTypeS3, nil)
+	return signV4(req, accessKeyID, secretAccessKey, sessionToken, location, ServiceTypeS3, nil, time.Now())
 }
 
 // SignV4Trailer sign the request before Do(), in accordance with
 // http://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
 func SignV4Trailer(req http.Request, accessKeyID, secretAccessKey, sessionToken, location string, trailer http.Header) *http.Request {
-	return signV4(req, accessKeyID, secretAccessKey, sessionToken, location, ServiceTypeS3, trailer)
+	return signV4(req, accessKeyID, secretAccessKey, sessionToken, location, ServiceTypeS3, trailer, time.Now())
+}
+
+// SignV4TrailerWithTime - same as SignV4Trailer with the request time
+// given, for example to make up for a local clock off from the server.
+func SignV4TrailerWithTime(req http.Request, accessKeyID, secretAccessKey, sessionToken, location string, trailer http.Header, signTime time.Time) *http.Request {
+	return signV4(req, accessKeyID, secretAccessKey, sessionToken, location, ServiceTypeS3, trailer, signTime)
 }
@@ -17,7 +17,17 @@
 
 package sse
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"errors"
+)
+
+// Server side encryption algorithms of a default encryption rule.
+const (
+	AlgorithmAES256  = "AES256"
+	AlgorithmKMS     = "aws:kms"
+	AlgorithmKMSDSSE = "aws:kms:dsse"
+)
 
 // ApplySSEByDefault defines default encryption configuration, KMS or SSE. To activate
 // KMS, SSEAlgoritm needs to be set to "aws:kms"
@@ -27,9 +37,11 @@ type ApplySSEByDefault struct {
 	SSEAlgorithm   string `xml:"SSEAlgorithm"`
 }
 
-// Rule layer encapsulates default encryption configuration
+// Rule layer encapsulates default encryption configuration, BucketKeyEnabled
+// reduces the calls to the KMS by using a bucket level key for SSE-KMS.
 type Rule struct {
-	Apply ApplySSEByDefault `xml:"ApplyServerSideEncryptionByDefault"`
+	Apply            ApplySSEByDefault `xml:"ApplyServerSideEncryptionByDefault"`
+	BucketKeyEnabled bool              `xml:"BucketKeyEnabled,omitempty"`
 }
 
 // Configuration is the default encryption configuration structure
@@ -44,7 +56,7 @@ func NewConfigurationSSES3() *Configuration {
 		Rules: []Rule{
 			{
 				Apply: ApplySSEByDefault{
-					SSEAlgorithm: "AES256",
+					SSEAlgorithm: AlgorithmAES256,
 				},
 			},
 		},
@@ -58,9 +70,38 @@ func NewConfigurationSSEKMS(kmsMasterKey string) *Configuration {
 			{
 				Apply: ApplySSEByDefault{
 					KmsMasterKeyID: kmsMasterKey,
-					SSEAlgorithm:   "aws:kms",
+					SSEAlgorithm:   AlgorithmKMS,
 				},
 			},
 		},
 	}
 }
+
+// Validate checks the configuration has at least one rule and that every
+// rule uses a known algorithm. A KMS key ID is only valid for the KMS
+// algorithms, KMS rules without a key ID use the default key of the
+// server. The bucket key is only valid for the aws:kms algorithm,
+// DSSE-KMS does not support it.
+func (c *Configuration) Validate() error {
+	if len(c.Rules) == 0 {
+		return errors.New("encryption configuration must have at least one rule")
+	}
+	for _, rule := range c.Rules {
+		switch rule.Apply.SSEAlgorithm {
+		case AlgorithmAES256:
+			if rule.Apply.KmsMasterKeyID != "" {
+				return errors.New("KMS master key ID cannot be set for the AES256 algorithm")
+			}
+			if rule.BucketKeyEnabled {
+				return errors.New("bucket key can only be enabled for the KMS algorithms")
+			}
+		case AlgorithmKMS, AlgorithmKMSDSSE:
+			if rule.BucketKeyEnabled && rule.Apply.SSEAlgorithm == AlgorithmKMSDSSE {
+				return errors.New("bucket key cannot be enabled for the " + AlgorithmKMSDSSE + " algorithm")
+			}
+		default:
+			return errors.New("unsupported encryption algorithm " + rule.Apply.SSEAlgorithm)
+		}
+	}
+	return nil
+}
@@ -225,6 +225,10 @@ func (tags tagSet) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	for key, value := range tags.tagMap {
 		tagList.Tags = append(tagList.Tags, Tag{key, value})
 	}
+	// Sort by key, so the same tags always encode to the same document.
+	sort.Slice(tagList.Tags, func(i, j int) bool {
+		return tagList.Tags[i].Key < tagList.Tags[j].Key
+	})
 
 	return e.EncodeElement(tagList, start)
 }
@@ -18,7 +18,11 @@
 package tags
 
 import (
+	"bytes"
+	"encoding/xml"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -107,6 +111,98 @@ func TestParseTags(t *testing.T) {
 	}
 }
 
+func TestNewTags(t *testing.T) {
+	objectTags := make(map[string]string)
+	for i := 0; i < maxObjectTagCount+1; i++ {
+		objectTags[fmt.Sprintf("key%d", i)] = "value"
+	}
+
+	testCases := []struct {
+		tags        map[string]string
+		isObject    bool
+		expectedErr error
+	}{
+		{map[string]string{"key": ""}, true, nil},
+		{map[string]string{"a-+._:/@ =z": "A-+._:/@ =Z"}, true, nil},
+		{map[string]string{strings.Repeat("k", maxKeyLength): strings.Repeat("v", maxValueLength)}, true, nil},
+		{map[string]string{strings.Repeat("é", maxKeyLength): "value"}, true, errInvalidTagKey},
+		{objectTags, false, nil},
+		// Failure cases.
+		{map[string]string{"": "value"}, true, errInvalidTagKey},
+		{map[string]string{strings.Repeat("k", maxKeyLength+1): "value"}, true, errInvalidTagKey},
+		{map[string]string{"key": strings.Repeat("v", maxValueLength+1)}, true, errInvalidTagValue},
+		{map[string]string{"key#": "value"}, true, errInvalidTagKey},
+		{map[string]string{"key": "value?"}, true, errInvalidTagValue},
+		{objectTags, true, errTooManyObjectTags},
+	}
+	for i, testCase := range testCases {
+		_, err := NewTags(testCase.tags, testCase.isObject)
+		if err != testCase.expectedErr {
+			t.Errorf("Test %d: expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestTagsString(t *testing.T) {
+	testCases := []struct {
+		tags     map[string]string
+		expected string
+	}{
+		{map[string]string{}, ""},
+		{map[string]string{"key": ""}, "key="},
+		{map[string]string{"b": "2", "a": "1"}, "a=1&b=2"},
+		{map[string]string{"store forever": "a/b+c=d"}, "store+forever=a%2Fb%2Bc%3Dd"},
+	}
+	for i, testCase := range testCases {
+		tt, err := MapToObjectTags(testCase.tags)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if s := tt.String(); s != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, s)
+		}
+		parsed, err := ParseObjectTags(tt.String())
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(parsed.ToMap(), tt.ToMap()) {
+			t.Errorf("Test %d: expected %v after parsing, got %v", i+1, tt.ToMap(), parsed.ToMap())
+		}
+	}
+}
+
+func TestTagsXML(t *testing.T) {
+	tagMap := map[string]string{"b": "2", "a": "1"}
+	tt, err := MapToObjectTags(tagMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := xml.Marshal(tt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<Tagging><TagSet><Tag><Key>a</Key><Value>1</Value></Tag><Tag><Key>b</Key><Value>2</Value></Tag></TagSet></Tagging>`
+	if string(data) != expected {
+		t.Fatalf("Expected %s, got %s", expected, data)
+	}
+	parsed, err := ParseObjectXML(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed.ToMap(), tagMap) {
+		t.Fatalf("Expected %v, got %v", tagMap, parsed.ToMap())
+	}
+
+	for _, data := range []string{
+		`<Tagging><TagSet><Tag><Key>a</Key><Value>1</Value></Tag><Tag><Key>a</Key><Value>2</Value></Tag></TagSet></Tagging>`,
+		`<Tagging><TagSet><Tag><Key>a$</Key><Value>1</Value></Tag></TagSet></Tagging>`,
+	} {
+		if _, err = ParseObjectXML(strings.NewReader(data)); err == nil {
+			t.Errorf("Expected %s to be rejected", data)
+		}
+	}
+}
+
 func BenchmarkParseTags(b *testing.B) {
 	b.ResetTimer()
 	b.ReportAllocs()
@@ -19,14 +19,18 @@ package minio
 
 import (
 	"encoding/base64"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/minio/minio-go/v7/pkg/encrypt"
 	"github.com/minio/minio-go/v7/pkg/tags"
+	"golang.org/x/net/http/httpguts"
 )
 
 // expirationDateFormat date format for expiration key in json policy.
@@ -195,8 +199,10 @@ func (p *PostPolicy) SetContentType(contentType string) error {
 
 // SetContentTypeStartsWith - Sets what content-type of the object for this policy
 // based upload can start with.
-// Can use an empty value ("") to allow any content-type.
 func (p *PostPolicy) SetContentTypeStartsWith(contentTypeStartsWith string) error {
+	if strings.TrimSpace(contentTypeStartsWith) == "" {
+		return errInvalidArgument("No content type prefix specified.")
+	}
 	policyCond := policyCondition{
 		matchType: "starts-with",
 		condition: "$Content-Type",
@@ -266,6 +272,10 @@ func (p *PostPolicy) SetSuccessActionRedirect(redirect string) error {
 	if strings.TrimSpace(redirect) == "" {
 		return errInvalidArgument("Redirect is empty")
 	}
+	u, err := url.Parse(redirect)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errInvalidArgument(fmt.Sprintf("Redirect %q is not a valid http(s) URL", redirect))
+	}
 	policyCond := policyCondition{
 		matchType: "eq",
 		condition: "$success_action_redirect",
@@ -280,10 +290,29 @@ func (p *PostPolicy) SetSuccessActionRedirect(redirect string) error {
 
 // SetSuccessStatusAction - Sets the status success code of the object for this policy
 // based upload.
+//
+// Deprecated: use SetSuccessActionStatus, which validates the status code.
 func (p *PostPolicy) SetSuccessStatusAction(status string) error {
 	if strings.TrimSpace(status) == "" {
 		return errInvalidArgument("Status is empty")
 	}
+	return p.setSuccessActionStatus(status)
+}
+
+// SetSuccessActionStatus - Sets the http status code returned to the
+// client on a successful upload when no redirect is configured. Only
+// 200, 201 and 204 are accepted by S3.
+func (p *PostPolicy) SetSuccessActionStatus(status int) error {
+	switch status {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+	default:
+		return errInvalidArgument(fmt.Sprintf("Invalid success action status %d, must be one of 200, 201 or 204", status))
+	}
+	return p.setSuccessActionStatus(strconv.Itoa(status))
+}
+
+// setSuccessActionStatus - adds the success_action_status condition.
+func (p *PostPolicy) setSuccessActionStatus(status string) error {
 	policyCond := policyCondition{
 		matchType: "eq",
 		condition: "$success_action_status",
@@ -305,7 +334,10 @@ func (p *PostPolicy) SetUserMetadata(key, value string) error {
 	if strings.TrimSpace(value) == "" {
 		return errInvalidArgument("Value is empty")
 	}
-	headerName := fmt.Sprintf("x-amz-meta-%s", key)
+	headerName, err := userMetadataFormField(key)
+	if err != nil {
+		return err
+	}
 	policyCond := policyCondition{
 		matchType: "eq",
 		condition: fmt.Sprintf("$%s", headerName),
@@ -324,7 +356,10 @@ func (p *PostPolicy) SetUserMetadataStartsWith(key, value string) error {
 	if strings.TrimSpace(key) == "" {
 		return errInvalidArgument("Key is empty")
 	}
-	headerName := fmt.Sprintf("x-amz-meta-%s", key)
+	headerName, err := userMetadataFormField(key)
+	if err != nil {
+		return err
+	}
 	policyCond := policyCondition{
 		matchType: "starts-with",
 		condition: fmt.Sprintf("$%s", headerName),
@@ -337,6 +372,40 @@ func (p *PostPolicy) SetUserMetadataStartsWith(key, value string) error {
 	return nil
 }
 
+// userMetadataFormField - returns the form field name for a user metadata
+// key, keys are accepted with or without the "x-amz-meta-" prefix.
+func userMetadataFormField(key string) (string, error) {
+	if strings.HasPrefix(strings.ToLower(key), "x-amz-meta-") {
+		key = key[len("x-amz-meta-"):]
+	}
+	if strings.TrimSpace(key) == "" {
+		return "", errInvalidArgument("Key is empty")
+	}
+	if !httpguts.ValidHeaderFieldName(key) || isStandardHeader(key) || isSSEHeader(key) ||
+		isStorageClassHeader(key) || isMinioHeader(key) || strings.HasPrefix(strings.ToLower(key), "x-amz-") {
+		return "", errInvalidArgument(key + " unsupported user defined metadata name")
+	}
+	return "x-amz-meta-" + key, nil
+}
+
+// SetUserTags - Sets tagging for the object for this policy based upload
+// from a map of tags, the tags are validated against the object tagging
+// limits before being encoded.
+func (p *PostPolicy) SetUserTags(userTags map[string]string) error {
+	if len(userTags) == 0 {
+		return errInvalidArgument("No tags specified.")
+	}
+	t, err := tags.MapToObjectTags(userTags)
+	if err != nil {
+		return err
+	}
+	tagging, err := xml.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return p.SetTagging(string(tagging))
+}
+
 // SetChecksum sets the checksum of the request.
 func (p *PostPolicy) SetChecksum(c Checksum) error {
 	if c.IsSet() {
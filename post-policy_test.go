@@ -18,6 +18,7 @@
 package minio
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -306,6 +307,142 @@ func TestPostPolicySetTagging(t *testing.T) {
 	}
 }
 
+func TestPostPolicySetContentTypeStartsWith(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+		want    string
+	}{
+		{
+			name:  "valid content type prefix",
+			input: "image/",
+			want:  `["starts-with","$Content-Type","image/"]`,
+		},
+		{
+			name:    "empty prefix",
+			input:   "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pp := NewPostPolicy()
+
+			err := pp.SetContentTypeStartsWith(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("%s: want error: %v, got: %v", tt.name, tt.wantErr, err)
+			}
+			if tt.wantErr {
+				if len(pp.conditions) != 0 {
+					t.Errorf("%s: want no condition added, got: %v", tt.name, pp.conditions)
+				}
+				return
+			}
+
+			result := pp.String()
+			if !strings.Contains(result, tt.want) {
+				t.Errorf("%s: want result to contain: '%s', got: '%s'", tt.name, tt.want, result)
+			}
+			if pp.formData["Content-Type"] != tt.input {
+				t.Errorf("%s: want form data Content-Type: '%s', got: '%s'", tt.name, tt.input, pp.formData["Content-Type"])
+			}
+		})
+	}
+}
+
+func TestPostPolicySetSuccessActionRedirect(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantErr    bool
+		wantResult string
+	}{
+		{
+			name:       "valid redirect",
+			input:      "https://example.com/uploaded",
+			wantResult: `["eq","$success_action_redirect","https://example.com/uploaded"]`,
+		},
+		{
+			name:    "empty redirect",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "relative redirect",
+			input:   "/uploaded",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported scheme",
+			input:   "ftp://example.com/uploaded",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pp := NewPostPolicy()
+
+			err := pp.SetSuccessActionRedirect(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("%s: want error: %v, got: %v", tt.name, tt.wantErr, err)
+			}
+
+			if tt.wantResult != "" {
+				result := pp.String()
+				if !strings.Contains(result, tt.wantResult) {
+					t.Errorf("%s: want result to contain: '%s', got: '%s'", tt.name, tt.wantResult, result)
+				}
+				if pp.formData["success_action_redirect"] != tt.input {
+					t.Errorf("%s: want form data success_action_redirect: '%s', got: '%s'", tt.name, tt.input, pp.formData["success_action_redirect"])
+				}
+			}
+		})
+	}
+}
+
+func TestPostPolicySetSuccessActionStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      int
+		wantErr    bool
+		wantResult string
+	}{
+		{
+			name:       "status 201",
+			input:      201,
+			wantResult: `["eq","$success_action_status","201"]`,
+		},
+		{
+			name:       "status 204",
+			input:      204,
+			wantResult: `["eq","$success_action_status","204"]`,
+		},
+		{
+			name:    "invalid status",
+			input:   302,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pp := NewPostPolicy()
+
+			err := pp.SetSuccessActionStatus(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("%s: want error: %v, got: %v", tt.name, tt.wantErr, err)
+			}
+
+			if tt.wantResult != "" {
+				result := pp.String()
+				if !strings.Contains(result, tt.wantResult) {
+					t.Errorf("%s: want result to contain: '%s', got: '%s'", tt.name, tt.wantResult, result)
+				}
+			}
+		})
+	}
+}
+
 func TestPostPolicySetUserMetadata(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -338,6 +475,30 @@ func TestPostPolicySetUserMetadata(t *testing.T) {
 			value:   "somevalue",
 			wantErr: true,
 		},
+		{
+			name:       "prefixed key",
+			key:        "X-Amz-Meta-user-key",
+			value:      "user-value",
+			wantResult: `"eq","$x-amz-meta-user-key","user-value"`,
+		},
+		{
+			name:    "prefix only",
+			key:     "x-amz-meta-",
+			value:   "somevalue",
+			wantErr: true,
+		},
+		{
+			name:    "reserved header",
+			key:     "Content-Type",
+			value:   "text/plain",
+			wantErr: true,
+		},
+		{
+			name:    "reserved amz header",
+			key:     "x-amz-server-side-encryption",
+			value:   "AES256",
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -358,6 +519,52 @@ func TestPostPolicySetUserMetadata(t *testing.T) {
 	}
 }
 
+func TestPostPolicyUserMetadataAndTagsFormData(t *testing.T) {
+	pp := NewPostPolicy()
+	if err := pp.SetExpires(time.Date(2023, time.March, 2, 15, 4, 5, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if err := pp.SetUserMetadata("project", "apollo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pp.SetUserMetadata("x-amz-meta-stage", "prod"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pp.SetUserTags(map[string]string{"team": "storage"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pp.SetUserTags(map[string]string{"invalid key#": "value"}); err == nil {
+		t.Fatal("want error for invalid tag key, got nil")
+	}
+
+	var policy struct {
+		Conditions [][]interface{} `json:"conditions"`
+	}
+	if err := json.Unmarshal(pp.marshalJSON(), &policy); err != nil {
+		t.Fatal(err)
+	}
+	if len(policy.Conditions) != 3 {
+		t.Fatalf("want 3 conditions, got: %v", policy.Conditions)
+	}
+	for _, cond := range policy.Conditions {
+		field := strings.TrimPrefix(cond[1].(string), "$")
+		value, ok := pp.formData[field]
+		if !ok {
+			t.Errorf("condition %v has no matching form data field", cond)
+			continue
+		}
+		if value != cond[2].(string) {
+			t.Errorf("condition %v does not match form data value '%s'", cond, value)
+		}
+	}
+	if pp.formData["x-amz-meta-stage"] != "prod" {
+		t.Errorf("want x-amz-meta-stage to be normalized, got form data: %v", pp.formData)
+	}
+	if !strings.Contains(pp.formData["tagging"], "<Key>team</Key><Value>storage</Value>") {
+		t.Errorf("want tagging form data to contain the tag, got: '%s'", pp.formData["tagging"])
+	}
+}
+
 func TestPostPolicySetChecksum(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -0,0 +1,127 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// ProgressReader counts the bytes of a transfer to report its rate and
+// the estimated time remaining, it is safe to poll from other
+// goroutines while the transfer proceeds.
+//
+// Wrap the reader returned by GetObject to track a download, or pass a
+// ProgressReader without reader as PutObjectOptions.Progress to track
+// an upload, every Read then counts the bytes of the buffer. Writes are
+// counted as well, to use it with io.TeeReader or io.MultiWriter.
+type ProgressReader struct {
+	r     io.Reader
+	total int64
+
+	mu    sync.Mutex
+	now   func() time.Time
+	start time.Time
+	last  time.Time
+	n     int64
+}
+
+// NewProgressReader returns a ProgressReader reading from r, which may
+// be nil, for a transfer of total bytes, zero or negative if unknown.
+func NewProgressReader(r io.Reader, total int64) *ProgressReader {
+	return &ProgressReader{r: r, total: total, now: time.Now}
+}
+
+// Read reads from the wrapped reader and counts the bytes read, without
+// reader it counts len(b) bytes.
+func (p *ProgressReader) Read(b []byte) (n int, err error) {
+	n = len(b)
+	if p.r != nil {
+		n, err = p.r.Read(b)
+	}
+	p.add(n)
+	return n, err
+}
+
+// Write counts len(b) bytes.
+func (p *ProgressReader) Write(b []byte) (int, error) {
+	p.add(len(b))
+	return len(b), nil
+}
+
+func (p *ProgressReader) add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	if p.start.IsZero() {
+		p.start = now
+	}
+	p.n += int64(n)
+	p.last = now
+}
+
+// Bytes returns the number of bytes transferred so far.
+func (p *ProgressReader) Bytes() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.n
+}
+
+// Rate returns the average transfer rate in bytes per second since the
+// first byte, or 0 until some time elapsed.
+func (p *ProgressReader) Rate() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.rate()
+}
+
+func (p *ProgressReader) rate() float64 {
+	if p.start.IsZero() {
+		return 0
+	}
+	end := p.last
+	if p.total <= 0 || p.n < p.total {
+		// The transfer is ongoing, time passes until the next byte.
+		end = p.now()
+	}
+	elapsed := end.Sub(p.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(p.n) / elapsed
+}
+
+// ETA returns the estimated time until the total is transferred at the
+// current rate. It returns 0 once the transfer is complete and -1 when
+// the total or the rate is unknown.
+func (p *ProgressReader) ETA() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.total <= 0 {
+		return -1
+	}
+	if p.n >= p.total {
+		return 0
+	}
+	rate := p.rate()
+	if rate == 0 {
+		return -1
+	}
+	return time.Duration(float64(p.total-p.n) / rate * float64(time.Second))
+}
@@ -0,0 +1,157 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestProgressReaderRate(t *testing.T) {
+	clock := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	p := NewProgressReader(bytes.NewReader(make([]byte, 1000)), 1000)
+	p.now = func() time.Time { return clock }
+
+	if rate, eta := p.Rate(), p.ETA(); rate != 0 || eta != -1 {
+		t.Fatalf("Expected unknown rate and ETA before the first byte, got %f and %s", rate, eta)
+	}
+
+	buf := make([]byte, 100)
+	testCases := []struct {
+		reads int
+		rate  float64
+		eta   time.Duration
+	}{
+		{1, 0, -1},                // 100 bytes at the start
+		{1, 200, 4 * time.Second}, // 200 bytes after 1s
+		{3, 125, 4 * time.Second}, // 500 bytes after 4s
+		{5, 1000.0 / 9, 0},        // complete after 9s
+		{0, 1000.0 / 9, 0},        // the rate is kept once complete
+	}
+	for i, testCase := range testCases {
+		for j := 0; j < testCase.reads; j++ {
+			if i > 0 {
+				clock = clock.Add(time.Second)
+			}
+			if _, err := io.ReadFull(p, buf); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if i == len(testCases)-1 {
+			clock = clock.Add(time.Hour)
+		}
+		if rate := p.Rate(); rate != testCase.rate {
+			t.Fatalf("Test %d: expected rate %f, got %f", i+1, testCase.rate, rate)
+		}
+		if eta := p.ETA(); eta != testCase.eta {
+			t.Fatalf("Test %d: expected ETA %s, got %s", i+1, testCase.eta, eta)
+		}
+	}
+	if n := p.Bytes(); n != 1000 {
+		t.Fatalf("Expected 1000 bytes, got %d", n)
+	}
+
+	// Time passes until the next byte of an ongoing transfer.
+	p = NewProgressReader(nil, 0)
+	p.now = func() time.Time { return clock }
+	p.Write(make([]byte, 100))
+	clock = clock.Add(2 * time.Second)
+	if rate, eta := p.Rate(), p.ETA(); rate != 50 || eta != -1 {
+		t.Fatalf("Expected rate 50 and unknown ETA, got %f and %s", rate, eta)
+	}
+}
+
+func TestProgressReaderTransfer(t *testing.T) {
+	const content = "hello, world"
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		if r.Method == http.MethodGet {
+			io.WriteString(w, content)
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Poll the progress while transferring.
+	poll := func(p *ProgressReader) (stop func()) {
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					p.Rate()
+					p.ETA()
+					p.Bytes()
+				}
+			}
+		}()
+		return func() {
+			close(done)
+			wg.Wait()
+		}
+	}
+
+	upload := NewProgressReader(nil, int64(len(content)))
+	stop := poll(upload)
+	_, err = clnt.PutObject(context.Background(), "bucket", "object", strings.NewReader(content), int64(len(content)), PutObjectOptions{Progress: upload})
+	stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := upload.Bytes(); n != int64(len(content)) {
+		t.Fatalf("Expected %d uploaded bytes, got %d", len(content), n)
+	}
+
+	obj, err := clnt.GetObject(context.Background(), "bucket", "object", GetObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer obj.Close()
+	download := NewProgressReader(obj, int64(len(content)))
+	stop = poll(download)
+	data, err := io.ReadAll(download)
+	stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != content || download.Bytes() != int64(len(content)) || download.ETA() != 0 {
+		t.Fatalf("Unexpected download %q of %d bytes", data, download.Bytes())
+	}
+}
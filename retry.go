@@ -21,6 +21,7 @@ import (
 	"context"
 	"crypto/x509"
 	"errors"
+	"net"
 	"net/http"
 	"net/url"
 	"time"
@@ -149,3 +150,10 @@ func isRequestErrorRetryable(ctx context.Context, err error) bool {
 	}
 	return true
 }
+
+// isDialError - returns true if the connection to the endpoint could
+// not be established, the request has not reached the server.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
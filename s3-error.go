@@ -17,46 +17,84 @@
 
 package minio
 
+import "net/http"
+
 // Non exhaustive list of AWS S3 standard error responses -
 // http://docs.aws.amazon.com/AmazonS3/latest/API/ErrorResponses.html
 var s3ErrorResponseMap = map[string]string{
-	"AccessDenied":                      "Access Denied.",
-	"BadDigest":                         "The Content-Md5 you specified did not match what we received.",
-	"EntityTooSmall":                    "Your proposed upload is smaller than the minimum allowed object size.",
-	"EntityTooLarge":                    "Your proposed upload exceeds the maximum allowed object size.",
-	"IncompleteBody":                    "You did not provide the number of bytes specified by the Content-Length HTTP header.",
-	"InternalError":                     "We encountered an internal error, please try again.",
-	"InvalidAccessKeyId":                "The access key ID you provided does not exist in our records.",
-	"InvalidBucketName":                 "The specified bucket is not valid.",
-	"InvalidDigest":                     "The Content-Md5 you specified is not valid.",
-	"InvalidRange":                      "The requested range is not satisfiable",
-	"MalformedXML":                      "The XML you provided was not well-formed or did not validate against our published schema.",
-	"MissingContentLength":              "You must provide the Content-Length HTTP header.",
-	"MissingContentMD5":                 "Missing required header for this request: Content-Md5.",
-	"MissingRequestBodyError":           "Request body is empty.",
-	"NoSuchBucket":                      "The specified bucket does not exist.",
-	"NoSuchBucketPolicy":                "The bucket policy does not exist",
-	"NoSuchKey":                         "The specified key does not exist.",
-	"NoSuchUpload":                      "The specified multipart upload does not exist. The upload ID may be invalid, or the upload may have been aborted or completed.",
-	"NotImplemented":                    "A header you provided implies functionality that is not implemented",
-	"PreconditionFailed":                "At least one of the pre-conditions you specified did not hold",
-	"RequestTimeTooSkewed":              "The difference between the request time and the server's time is too large.",
-	"SignatureDoesNotMatch":             "The request signature we calculated does not match the signature you provided. Check your key and signing method.",
-	"MethodNotAllowed":                  "The specified method is not allowed against this resource.",
-	"InvalidPart":                       "One or more of the specified parts could not be found.",
-	"InvalidPartOrder":                  "The list of parts was not in ascending order. The parts list must be specified in order by part number.",
-	"InvalidObjectState":                "The operation is not valid for the current state of the object.",
-	"AuthorizationHeaderMalformed":      "The authorization header is malformed; the region is wrong.",
-	"MalformedPOSTRequest":              "The body of your POST request is not well-formed multipart/form-data.",
-	"BucketNotEmpty":                    "The bucket you tried to delete is not empty",
-	"AllAccessDisabled":                 "All access to this bucket has been disabled.",
-	"MalformedPolicy":                   "Policy has invalid resource.",
-	"MissingFields":                     "Missing fields in request.",
-	"AuthorizationQueryParametersError": "Error parsing the X-Amz-Credential parameter; the Credential is mal-formed; expecting \"<YOUR-AKID>/YYYYMMDD/REGION/SERVICE/aws4_request\".",
-	"MalformedDate":                     "Invalid date format header, expected to be in ISO8601, RFC1123 or RFC1123Z time format.",
-	"BucketAlreadyOwnedByYou":           "Your previous request to create the named bucket succeeded and you already own it.",
-	"InvalidDuration":                   "Duration provided in the request is invalid.",
-	"XAmzContentSHA256Mismatch":         "The provided 'x-amz-content-sha256' header does not match what was computed.",
-	"NoSuchCORSConfiguration":           "The specified bucket does not have a CORS configuration.",
+	"AccessDenied":                         "Access Denied.",
+	"BadDigest":                            "The Content-Md5 you specified did not match what we received.",
+	"EntityTooSmall":                       "Your proposed upload is smaller than the minimum allowed object size.",
+	"EntityTooLarge":                       "Your proposed upload exceeds the maximum allowed object size.",
+	"IncompleteBody":                       "You did not provide the number of bytes specified by the Content-Length HTTP header.",
+	"InternalError":                        "We encountered an internal error, please try again.",
+	"InvalidAccessKeyId":                   "The access key ID you provided does not exist in our records.",
+	"InvalidBucketName":                    "The specified bucket is not valid.",
+	"InvalidDigest":                        "The Content-Md5 you specified is not valid.",
+	"InvalidRange":                         "The requested range is not satisfiable",
+	"MalformedXML":                         "The XML you provided was not well-formed or did not validate against our published schema.",
+	"MissingContentLength":                 "You must provide the Content-Length HTTP header.",
+	"MissingContentMD5":                    "Missing required header for this request: Content-Md5.",
+	"MissingRequestBodyError":              "Request body is empty.",
+	"NoSuchBucket":                         "The specified bucket does not exist.",
+	"NoSuchBucketPolicy":                   "The bucket policy does not exist",
+	"NoSuchKey":                            "The specified key does not exist.",
+	"NoSuchUpload":                         "The specified multipart upload does not exist. The upload ID may be invalid, or the upload may have been aborted or completed.",
+	"NotImplemented":                       "A header you provided implies functionality that is not implemented",
+	"PreconditionFailed":                   "At least one of the pre-conditions you specified did not hold",
+	"NotModified":                          "The object was not modified according to the specified conditions.",
+	"RequestTimeTooSkewed":                 "The difference between the request time and the server's time is too large.",
+	"SignatureDoesNotMatch":                "The request signature we calculated does not match the signature you provided. Check your key and signing method.",
+	"MethodNotAllowed":                     "The specified method is not allowed against this resource.",
+	"InvalidPart":                          "One or more of the specified parts could not be found.",
+	"InvalidPartOrder":                     "The list of parts was not in ascending order. The parts list must be specified in order by part number.",
+	"InvalidObjectState":                   "The operation is not valid for the current state of the object.",
+	"AuthorizationHeaderMalformed":         "The authorization header is malformed; the region is wrong.",
+	"MalformedPOSTRequest":                 "The body of your POST request is not well-formed multipart/form-data.",
+	"BucketNotEmpty":                       "The bucket you tried to delete is not empty",
+	"AllAccessDisabled":                    "All access to this bucket has been disabled.",
+	"MalformedPolicy":                      "Policy has invalid resource.",
+	"MissingFields":                        "Missing fields in request.",
+	"AuthorizationQueryParametersError":    "Error parsing the X-Amz-Credential parameter; the Credential is mal-formed; expecting \"<YOUR-AKID>/YYYYMMDD/REGION/SERVICE/aws4_request\".",
+	"MalformedDate":                        "Invalid date format header, expected to be in ISO8601, RFC1123 or RFC1123Z time format.",
+	"BucketAlreadyOwnedByYou":              "Your previous request to create the named bucket succeeded and you already own it.",
+	"InvalidDuration":                      "Duration provided in the request is invalid.",
+	"XAmzContentSHA256Mismatch":            "The provided 'x-amz-content-sha256' header does not match what was computed.",
+	"NoSuchCORSConfiguration":              "The specified bucket does not have a CORS configuration.",
+	"ObjectLockConfigurationNotFoundError": "Object Lock configuration does not exist for this bucket.",
+	"RestoreAlreadyInProgress":             "Object restore is already in progress.",
 	// Add new API errors here.
 }
+
+// HTTP status codes of AWS S3 standard error responses, used when the
+// error was not returned with its own HTTP response, such as errors of
+// single objects in a multi-object delete.
+var s3ErrorResponseStatusMap = map[string]int{
+	"AccessDenied":             http.StatusForbidden,
+	"AllAccessDisabled":        http.StatusForbidden,
+	"BadDigest":                http.StatusBadRequest,
+	"BucketAlreadyOwnedByYou":  http.StatusConflict,
+	"BucketNotEmpty":           http.StatusConflict,
+	"EntityTooLarge":           http.StatusBadRequest,
+	"EntityTooSmall":           http.StatusBadRequest,
+	"InternalError":            http.StatusInternalServerError,
+	"InvalidAccessKeyId":       http.StatusForbidden,
+	"InvalidArgument":          http.StatusBadRequest,
+	"InvalidBucketName":        http.StatusBadRequest,
+	"InvalidObjectState":       http.StatusForbidden,
+	"InvalidRange":             http.StatusRequestedRangeNotSatisfiable,
+	"MethodNotAllowed":         http.StatusMethodNotAllowed,
+	"NoSuchBucket":             http.StatusNotFound,
+	"NoSuchBucketPolicy":       http.StatusNotFound,
+	"NoSuchKey":                http.StatusNotFound,
+	"NoSuchUpload":             http.StatusNotFound,
+	"NoSuchVersion":            http.StatusNotFound,
+	"NotImplemented":           http.StatusNotImplemented,
+	"NotModified":              http.StatusNotModified,
+	"PreconditionFailed":       http.StatusPreconditionFailed,
+	"RequestTimeTooSkewed":     http.StatusForbidden,
+	"RestoreAlreadyInProgress": http.StatusConflict,
+	"ServiceUnavailable":       http.StatusServiceUnavailable,
+	"SignatureDoesNotMatch":    http.StatusForbidden,
+	"SlowDown":                 http.StatusServiceUnavailable,
+}
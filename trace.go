@@ -0,0 +1,254 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// traceRedacted replaces the values of redacted headers and query parameters.
+const traceRedacted = "**REDACTED**"
+
+// traceBodyLimit is the default maximum number of response body bytes
+// captured in a trace record.
+const traceBodyLimit = 4 * 1024
+
+// Headers and query parameters which are always redacted in trace records.
+var traceDefaultRedact = []string{
+	"Authorization",
+	"X-Amz-Security-Token",
+	"X-Amz-Signature",
+	"X-Amz-Credential",
+	"X-Amz-Server-Side-Encryption-Customer-Key",
+	"X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key",
+}
+
+// TraceConfig configures structured HTTP tracing, see SetTraceConfig.
+//
+// Every request produces one TraceRecord which is written as a JSON
+// line to Writer and passed to Handler when set. Authorization,
+// credentials, signatures and SSE-C keys are always redacted, Redact
+// lists additional header or query parameter names to redact.
+//
+// By default the response body is only captured for errors, IncludeBody
+// captures it for all responses and HeadersOnly never captures it. At
+// most MaxBodySize bytes of a body are captured, 4KiB when not set.
+//
+// A record is written once its response body has been read to the end
+// or closed, so BytesReceived is the number of body bytes actually read,
+// also for chunked responses without a Content-Length.
+type TraceConfig struct {
+	Writer      io.Writer
+	Handler     func(TraceRecord)
+	IncludeBody bool
+	HeadersOnly bool
+	MaxBodySize int64
+	Redact      []string
+}
+
+// TraceRecord is the trace of a single HTTP request.
+type TraceRecord struct {
+	Time           time.Time     `json:"time"`
+	Method         string        `json:"method"`
+	URL            string        `json:"url"`
+	StatusCode     int           `json:"statusCode,omitempty"`
+	Duration       time.Duration `json:"duration"`
+	BytesSent      int64         `json:"bytesSent"`
+	BytesReceived  int64         `json:"bytesReceived"`
+	RequestHeader  http.Header   `json:"requestHeader,omitempty"`
+	ResponseHeader http.Header   `json:"responseHeader,omitempty"`
+	ResponseBody   string        `json:"responseBody,omitempty"`
+	Error          string        `json:"error,omitempty"`
+}
+
+// SetTraceConfig - enable structured HTTP tracing with the given config,
+// replacing any tracing enabled by TraceOn. Call TraceOff to disable it.
+func (c *Client) SetTraceConfig(config TraceConfig) {
+	if config.Writer == nil && config.Handler == nil {
+		config.Writer = os.Stdout
+	}
+	config.Redact = append(append([]string{}, traceDefaultRedact...), config.Redact...)
+	c.traceConfig = &config
+	c.isTraceEnabled = false
+	c.traceErrorsOnly = false
+}
+
+// isRedacted - returns whether the header or query parameter must be redacted.
+func (t *TraceConfig) isRedacted(name string) bool {
+	for _, r := range t.Redact {
+		if strings.EqualFold(r, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeader - returns a copy of h with redacted values replaced.
+func (t *TraceConfig) redactHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(http.Header, len(h))
+	for k, v := range h {
+		if t.isRedacted(k) {
+			out[k] = []string{traceRedacted}
+			continue
+		}
+		out[k] = append([]string{}, v...)
+	}
+	return out
+}
+
+// redactURL - returns u as string with redacted query values replaced.
+func (t *TraceConfig) redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	query := u.Query()
+	for k := range query {
+		if t.isRedacted(k) {
+			query[k] = []string{traceRedacted}
+		}
+	}
+	ru := *u
+	ru.User = nil
+	ru.RawQuery = query.Encode()
+	return ru.String()
+}
+
+// maxBodySize - returns the maximum number of body bytes to capture.
+func (t *TraceConfig) maxBodySize() int64 {
+	if t.MaxBodySize <= 0 {
+		return traceBodyLimit
+	}
+	return t.MaxBodySize
+}
+
+// write - passes the record to the handler and writes it as a JSON line.
+func (t *TraceConfig) write(record TraceRecord) error {
+	if t.Handler != nil {
+		t.Handler(record)
+	}
+	if t.Writer != nil {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		if _, err = t.Writer.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// traceBody counts the bytes read from a traced response body and
+// writes the trace record once the body is read to the end or closed.
+type traceBody struct {
+	io.ReadCloser
+	config   *TraceConfig
+	record   TraceRecord
+	received int64 // accessed atomically, Read and Close may race.
+	once     sync.Once
+	err      error
+}
+
+func (b *traceBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	atomic.AddInt64(&b.received, int64(n))
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *traceBody) Close() error {
+	err := b.ReadCloser.Close()
+	if ferr := b.finish(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// finish - writes the trace record exactly once.
+func (b *traceBody) finish() error {
+	b.once.Do(func() {
+		record := b.record
+		record.BytesReceived = atomic.LoadInt64(&b.received)
+		b.err = b.config.write(record)
+	})
+	return b.err
+}
+
+// traceRequest - traces a request. Without a response body the record is
+// written right away, otherwise the body is wrapped so the record is
+// written once the caller is done with it. A captured body prefix is put
+// back so the caller can still read it.
+func (c *Client) traceRequest(req *http.Request, resp *http.Response, reqErr error, start time.Time) error {
+	t := c.traceConfig
+	record := TraceRecord{
+		Time:          start.UTC(),
+		Method:        req.Method,
+		URL:           t.redactURL(req.URL),
+		Duration:      time.Since(start),
+		BytesSent:     req.ContentLength,
+		RequestHeader: t.redactHeader(req.Header),
+	}
+	if reqErr != nil {
+		record.Error = reqErr.Error()
+	}
+	if resp == nil || resp.Body == nil {
+		if resp != nil {
+			record.StatusCode = resp.StatusCode
+			record.ResponseHeader = t.redactHeader(resp.Header)
+		}
+		return t.write(record)
+	}
+
+	record.StatusCode = resp.StatusCode
+	record.ResponseHeader = t.redactHeader(resp.Header)
+	body := &traceBody{ReadCloser: resp.Body, config: t, record: record}
+
+	var captured []byte
+	isError := resp.StatusCode != http.StatusOK &&
+		resp.StatusCode != http.StatusPartialContent &&
+		resp.StatusCode != http.StatusNoContent
+	if !t.HeadersOnly && (t.IncludeBody || isError) {
+		var err error
+		captured, err = io.ReadAll(io.LimitReader(resp.Body, t.maxBodySize()))
+		if err != nil {
+			return err
+		}
+		body.record.ResponseBody = string(captured)
+		body.received = int64(len(captured))
+	}
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(captured), body), body}
+	return nil
+}
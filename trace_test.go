@@ -0,0 +1,200 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+)
+
+func TestSetTraceConfigRedacts(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		switch r.Method {
+		case http.MethodPut:
+			w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+			w.Header().Set("X-Secret-Token", "server-secret")
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>`))
+		}
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Creds:     credentials.NewStaticV4("accessKey", "secretKey", "sessionToken"),
+		Region:    "us-east-1",
+		Secure:    true,
+		Transport: srv.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		out     bytes.Buffer
+		records []TraceRecord
+	)
+	clnt.SetTraceConfig(TraceConfig{
+		Writer:  &out,
+		Handler: func(r TraceRecord) { records = append(records, r) },
+		Redact:  []string{"x-secret-token"},
+	})
+
+	key := []byte("32byteslongsecretkeymustprovided")
+	sse, err := encrypt.NewSSEC(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello")
+	_, err = clnt.PutObject(context.Background(), "bucket", "object", bytes.NewReader(data), int64(len(data)), PutObjectOptions{
+		ServerSideEncryption: sse,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = clnt.StatObject(context.Background(), "bucket", "object", StatObjectOptions{}); err == nil {
+		t.Fatal("Expected StatObject to fail")
+	}
+	_, policyErr := clnt.GetBucketPolicy(context.Background(), "bucket")
+	if policyErr == nil {
+		t.Fatal("Expected GetBucketPolicy to fail")
+	}
+
+	trace := out.String()
+	for _, secret := range []string{
+		base64.StdEncoding.EncodeToString(key),
+		"accessKey",
+		"sessionToken",
+		"server-secret",
+	} {
+		if strings.Contains(trace, secret) {
+			t.Fatalf("Expected %q to be redacted from trace output:\n%s", secret, trace)
+		}
+	}
+
+	var lines []TraceRecord
+	scanner := bufio.NewScanner(strings.NewReader(trace))
+	for scanner.Scan() {
+		var record TraceRecord
+		if err = json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, record)
+	}
+	if len(lines) != 3 || len(records) != 3 {
+		t.Fatalf("Expected 3 trace records, got %d lines and %d handler calls", len(lines), len(records))
+	}
+
+	put, stat, get := lines[0], lines[1], lines[2]
+	if put.Method != http.MethodPut || put.StatusCode != http.StatusOK || put.BytesSent != int64(len(data)) {
+		t.Fatalf("Unexpected PUT record %+v", put)
+	}
+	if !strings.HasSuffix(put.URL, "/bucket/object") || put.Duration <= 0 {
+		t.Fatalf("Unexpected PUT record %+v", put)
+	}
+	if v := put.RequestHeader.Get("X-Amz-Server-Side-Encryption-Customer-Key"); v != traceRedacted {
+		t.Fatalf("Expected SSE-C key to be redacted, got %q", v)
+	}
+	if v := put.ResponseHeader.Get("X-Secret-Token"); v != traceRedacted {
+		t.Fatalf("Expected custom header to be redacted, got %q", v)
+	}
+	if put.ResponseBody != "" {
+		t.Fatalf("Expected no body for successful request, got %q", put.ResponseBody)
+	}
+	if stat.Method != http.MethodHead || stat.StatusCode != http.StatusForbidden {
+		t.Fatalf("Unexpected HEAD record %+v", stat)
+	}
+	// The error body is captured and still parsed by the client.
+	if get.Method != http.MethodGet || !strings.Contains(get.ResponseBody, "AccessDenied") {
+		t.Fatalf("Unexpected GET record %+v", get)
+	}
+	if ToErrorResponse(policyErr).Code != "AccessDenied" {
+		t.Fatalf("Expected AccessDenied, got %v", policyErr)
+	}
+
+	out.Reset()
+	clnt.TraceOff()
+	clnt.GetBucketPolicy(context.Background(), "bucket")
+	if out.Len() != 0 {
+		t.Fatalf("Expected no trace output after TraceOff, got %s", out.String())
+	}
+}
+
+func TestSetTraceConfigChunkedBody(t *testing.T) {
+	payload := bytes.Repeat([]byte("0123456789"), 1000)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", "Mon, 2 Jan 2006 15:04:05 GMT")
+		w.Header().Set("ETag", `"etag"`)
+		// Flushing before writing the body forces a chunked response.
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		w.Write(payload)
+	}))
+	defer srv.Close()
+
+	clnt, err := New(srv.Listener.Addr().String(), &Options{
+		Creds:  credentials.NewStaticV4("accessKey", "secretKey", ""),
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var records []TraceRecord
+	clnt.SetTraceConfig(TraceConfig{
+		Handler:     func(r TraceRecord) { records = append(records, r) },
+		IncludeBody: true,
+		MaxBodySize: 100,
+	})
+
+	obj, err := clnt.GetObject(context.Background(), "bucket", "object", GetObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj.Close()
+	if !bytes.Equal(data, payload) {
+		t.Fatalf("expected %d bytes, got %d", len(payload), len(data))
+	}
+
+	if len(records) != 1 {
+		t.Fatalf("expected 1 trace record, got %d", len(records))
+	}
+	if records[0].BytesReceived != int64(len(payload)) {
+		t.Fatalf("expected %d bytes received, got %d", len(payload), records[0].BytesReceived)
+	}
+	if records[0].ResponseBody != string(payload[:100]) {
+		t.Fatalf("expected the first 100 bytes of the body, got %q", records[0].ResponseBody)
+	}
+}
@@ -40,7 +40,10 @@ import (
 	"time"
 
 	md5simd "github.com/minio/md5-simd"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
 	"github.com/minio/minio-go/v7/pkg/s3utils"
+	"golang.org/x/net/http/httpguts"
 )
 
 func trimEtag(etag string) string {
@@ -48,17 +51,34 @@ func trimEtag(etag string) string {
 	return strings.TrimSuffix(etag, "\"")
 }
 
-var expirationRegex = regexp.MustCompile(`expiry-date="(.*?)", rule-id="(.*?)"`)
+var expirationRegex = regexp.MustCompile(`(expiry-date|rule-id)\s*=\s*"(.*?)"`)
 
+// amzExpirationToExpiryDateRuleID parses the x-amz-expiration header
+// of the form expiry-date="Fri, 23 Dec 2012 00:00:00 GMT", rule-id="id"
+// into the expiry date and the rule ID, which AWS S3 URL encodes. Zero
+// values are returned when the expiry date is missing or invalid.
 func amzExpirationToExpiryDateRuleID(expiration string) (time.Time, string) {
-	if matches := expirationRegex.FindStringSubmatch(expiration); len(matches) == 3 {
-		expTime, err := parseRFC7231Time(matches[1])
-		if err != nil {
-			return time.Time{}, ""
+	var expTime time.Time
+	var ruleID string
+	for _, matches := range expirationRegex.FindAllStringSubmatch(expiration, -1) {
+		switch matches[1] {
+		case "expiry-date":
+			t, err := parseRFC7231Time(matches[2])
+			if err != nil {
+				return time.Time{}, ""
+			}
+			expTime = t
+		case "rule-id":
+			ruleID = matches[2]
+			if id, err := url.PathUnescape(ruleID); err == nil {
+				ruleID = id
+			}
 		}
-		return expTime, matches[2]
 	}
-	return time.Time{}, ""
+	if expTime.IsZero() {
+		return time.Time{}, ""
+	}
+	return expTime, ruleID
 }
 
 var restoreRegex = regexp.MustCompile(`ongoing-request="(.*?)"(, expiry-date="(.*?)")?`)
@@ -180,14 +200,27 @@ func isValidEndpointURL(endpointURL url.URL) error {
 	return nil
 }
 
-// Verify if input expires value is valid.
-func isValidExpiry(expires time.Duration) error {
-	expireSeconds := int64(expires / time.Second)
-	if expireSeconds < 1 {
-		return errInvalidArgument("Expires cannot be lesser than 1 second.")
+// maxPresignExpiry is the longest validity of a signature v4 presigned URL.
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// Verify if input expires value is valid, presigned URLs are valid for at
+// least a second and for at most 7 days with signature v4. Signature v2
+// has no upper limit although servers may refuse long lived URLs.
+func isValidExpiry(expires time.Duration, signerType credentials.SignatureType) error {
+	if expires < time.Second {
+		return errInvalidArgument(fmt.Sprintf("Expires must be at least 1s, got %s.", expires))
 	}
-	if expireSeconds > 604800 {
-		return errInvalidArgument("Expires cannot be greater than 7 days.")
+	if !signerType.IsV2() && expires > maxPresignExpiry {
+		return errInvalidArgument(fmt.Sprintf("Expires must be between 1s and 168h for signature v4, got %s.", expires))
+	}
+	return nil
+}
+
+// isValidPresignTime - verify if the signing time of a presigned URL is
+// within the allowed range, it cannot be more than 7 days in the future.
+func isValidPresignTime(signTime time.Time) error {
+	if signTime.After(time.Now().Add(7 * 24 * time.Hour)) {
+		return errInvalidArgument("Signing time cannot be more than 7 days in the future.")
 	}
 	return nil
 }
@@ -252,6 +285,25 @@ func parseRFC7231Time(lastModified string) (time.Time, error) {
 	return parseTime(lastModified, rfc822TimeFormat, rfc822TimeFormatSingleDigitDay, rfc822TimeFormatSingleDigitDayTwoDigitYear)
 }
 
+// contentRangeSize returns the full object size from a Content-Range
+// header value of the form "bytes start-end/size", false is returned
+// when the header is missing or the size is unknown ("*").
+func contentRangeSize(contentRange string) (int64, bool) {
+	rangeSpec, ok := strings.CutPrefix(contentRange, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	_, sizeStr, ok := strings.Cut(rangeSpec, "/")
+	if !ok {
+		return 0, false
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(sizeStr), 10, 64)
+	if err != nil || size < 0 {
+		return 0, false
+	}
+	return size, true
+}
+
 // ToObjectInfo converts http header values into ObjectInfo type,
 // extracts metadata and fills in all the necessary fields in ObjectInfo.
 func ToObjectInfo(bucketName, objectName string, h http.Header) (ObjectInfo, error) {
@@ -278,6 +330,23 @@ func ToObjectInfo(bucketName, objectName string, h http.Header) (ObjectInfo, err
 		}
 	}
 
+	// Ranged responses only transfer part of the object, Content-Range
+	// carries the full object size, e.g. "bytes 0-99/1000".
+	if totalSize, ok := contentRangeSize(h.Get("Content-Range")); ok {
+		size = totalSize
+	}
+
+	// Parse the logical size of encrypted or compressed objects if any,
+	// defaults to the transfer size. Invalid values are skipped, the
+	// unencrypted content length is user metadata anyone can set.
+	actualSize := size
+	for _, key := range []string{amzObjectSize, minIOActualObjectSize, amzMetaUnencryptedContentLength} {
+		if n, err := strconv.ParseInt(h.Get(key), 10, 64); err == nil && n >= 0 {
+			actualSize = n
+			break
+		}
+	}
+
 	// Parse Last-Modified has http time format.
 	mtime, err := parseRFC7231Time(h.Get("Last-Modified"))
 	if err != nil {
@@ -319,7 +388,7 @@ func ToObjectInfo(bucketName, objectName string, h http.Header) (ObjectInfo, err
 	userMetadata := make(map[string]string)
 	for k, v := range metadata {
 		if strings.HasPrefix(k, "X-Amz-Meta-") {
-			userMetadata[strings.TrimPrefix(k, "X-Amz-Meta-")] = v[0]
+			userMetadata[userMetadataKey(k)] = v[0]
 		}
 	}
 	userTags := s3utils.TagDecode(h.Get(amzTaggingHeader))
@@ -338,6 +407,9 @@ func ToObjectInfo(bucketName, objectName string, h http.Header) (ObjectInfo, err
 				Region:     h.Get("x-amz-bucket-region"),
 			}
 		}
+	} else {
+		// MinIO may return the tags without their count.
+		tagCount = len(userTags)
 	}
 
 	// Nil if not found
@@ -360,12 +432,16 @@ func ToObjectInfo(bucketName, objectName string, h http.Header) (ObjectInfo, err
 		ETag:              etag,
 		Key:               objectName,
 		Size:              size,
+		ActualSize:        actualSize,
 		LastModified:      mtime,
 		ContentType:       contentType,
 		Expires:           expiry,
 		VersionID:         h.Get(amzVersionID),
 		IsDeleteMarker:    deleteMarker,
 		ReplicationStatus: h.Get(amzReplicationStatus),
+		ReplicationReady:  h.Get(minioTgtReplicationReady) == "true",
+		RequestCharged:    h.Get(amzRequestCharged) == requester,
+		StorageClass:      h.Get(amzStorageClass),
 		Expiration:        expTime,
 		ExpirationRuleID:  ruleID,
 		// Extract only the relevant header keys describing the object.
@@ -383,6 +459,9 @@ func ToObjectInfo(bucketName, objectName string, h http.Header) (ObjectInfo, err
 		ChecksumSHA1:      h.Get(ChecksumSHA1.Key()),
 		ChecksumSHA256:    h.Get(ChecksumSHA256.Key()),
 		ChecksumCRC64NVME: h.Get(ChecksumCRC64NVME.Key()),
+
+		ServerSideEncryption: h.Get(encrypt.SseGenericHeader),
+		SSEKMSKeyID:          h.Get(encrypt.SseKmsKeyID),
 	}, nil
 }
 
@@ -504,6 +583,39 @@ func isSSEHeader(headerKey string) bool {
 	return sseHeaders[strings.ToLower(headerKey)]
 }
 
+// hasSSECKey returns true if the headers carry an SSE-C key of the
+// object or of the copy source.
+func hasSSECKey(h http.Header) bool {
+	return h.Get(encrypt.SseCustomerKey) != "" || h.Get(encrypt.SseCopyCustomerKey) != ""
+}
+
+// reservedHeaders is list of request headers interpreted by the server
+// which cannot be used as user defined metadata names.
+var reservedHeaders = map[string]bool{
+	"x-amz-tagging":                                   true,
+	"x-amz-tagging-directive":                         true,
+	"x-amz-object-lock-legal-hold":                    true,
+	"x-amz-bypass-governance-retention":               true,
+	"x-amz-copy-source":                               true,
+	"x-amz-content-sha256":                            true,
+	"x-amz-date":                                      true,
+	"x-amz-security-token":                            true,
+	"x-amz-decoded-content-length":                    true,
+	"x-amz-trailer":                                   true,
+	"x-amz-request-payer":                             true,
+	"x-amz-expected-bucket-owner":                     true,
+	"x-amz-version-id":                                true,
+	"x-amz-sdk-checksum-algorithm":                    true,
+	"x-amz-server-side-encryption-bucket-key-enabled": true,
+	// Add more reserved headers here.
+	// Must be lower case.
+}
+
+// isReservedHeader returns true if header is interpreted by the server.
+func isReservedHeader(headerKey string) bool {
+	return reservedHeaders[strings.ToLower(headerKey)]
+}
+
 // isAmzHeader returns true if header is a x-amz-meta-* or x-amz-acl header.
 func isAmzHeader(headerKey string) bool {
 	key := strings.ToLower(headerKey)
@@ -511,6 +623,58 @@ func isAmzHeader(headerKey string) bool {
 	return strings.HasPrefix(key, "x-amz-meta-") || strings.HasPrefix(key, "x-amz-grant-") || key == "x-amz-acl" || isSSEHeader(headerKey) || strings.HasPrefix(key, "x-amz-checksum-")
 }
 
+// userMetadataHeaderKey returns the header name under which a
+// UserMetadata entry is sent. Keys are accepted with or without the
+// `x-amz-meta-` prefix and the prefix is always emitted exactly once.
+// ACL, grant, checksum, standard, storage class and MinIO headers are
+// sent unchanged.
+func userMetadataHeaderKey(key string) string {
+	if strings.HasPrefix(strings.ToLower(key), "x-amz-meta-") {
+		return "x-amz-meta-" + key[len("x-amz-meta-"):]
+	}
+	if isAmzHeader(key) || isStandardHeader(key) || isStorageClassHeader(key) || isMinioHeader(key) {
+		return key
+	}
+	return "x-amz-meta-" + key
+}
+
+// userMetadataKey returns the key of a user metadata entry in
+// ObjectInfo.UserMetadata, without the `x-amz-meta-` prefix and
+// in canonical header form.
+func userMetadataKey(key string) string {
+	if strings.HasPrefix(strings.ToLower(key), "x-amz-meta-") {
+		key = key[len("x-amz-meta-"):]
+	}
+	return http.CanonicalHeaderKey(key)
+}
+
+// validateUserMetadata rejects user metadata names which are reserved
+// headers, or which map to the same header once the `x-amz-meta-`
+// prefix is normalized.
+func validateUserMetadata(userMeta map[string]string) error {
+	seen := make(map[string]string, len(userMeta))
+	for k, v := range userMeta {
+		if !httpguts.ValidHeaderFieldName(k) {
+			return errInvalidArgument(k + " unsupported user defined metadata name")
+		}
+		if isReservedHeader(k) {
+			return errInvalidArgument(k + " is a reserved header and cannot be used as user defined metadata name")
+		}
+		header := http.CanonicalHeaderKey(userMetadataHeaderKey(k))
+		if header == "X-Amz-Meta-" {
+			return errInvalidArgument("user defined metadata name cannot be empty")
+		}
+		if prev, ok := seen[header]; ok {
+			return errInvalidArgument("user defined metadata names " + prev + " and " + k + " refer to the same header")
+		}
+		seen[header] = k
+		if !httpguts.ValidHeaderFieldValue(v) {
+			return errInvalidArgument(v + " unsupported user defined metadata value")
+		}
+	}
+	return nil
+}
+
 // isMinioHeader returns true if header is x-minio- header.
 func isMinioHeader(headerKey string) bool {
 	return strings.HasPrefix(strings.ToLower(headerKey), "x-minio-")
@@ -21,10 +21,12 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"net/url"
 	"testing"
 	"time"
 
+	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/minio/minio-go/v7/pkg/s3utils"
 )
 
@@ -260,26 +262,53 @@ func TestDefaultBucketLocation(t *testing.T) {
 	}
 }
 
+func TestAmzExpirationToExpiryDateRuleID(t *testing.T) {
+	expiry := time.Date(2012, time.December, 23, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		header string
+		date   time.Time
+		ruleID string
+	}{
+		{`expiry-date="Fri, 23 Dec 2012 00:00:00 GMT", rule-id="picture-deletion-rule"`, expiry, "picture-deletion-rule"},
+		{`expiry-date="Fri, 23 Dec 2012 00:00:00 GMT",rule-id="delete%20logs"`, expiry, "delete logs"},
+		{`rule-id="reversed", expiry-date="Fri, 23 Dec 2012 00:00:00 GMT"`, expiry, "reversed"},
+		{`expiry-date="Fri, 23 Dec 2012 00:00:00 GMT"`, expiry, ""},
+		{`rule-id="no-date"`, time.Time{}, ""},
+		{`expiry-date="tomorrow", rule-id="invalid-date"`, time.Time{}, ""},
+		{"", time.Time{}, ""},
+	}
+	for i, testCase := range testCases {
+		date, ruleID := amzExpirationToExpiryDateRuleID(testCase.header)
+		if !date.Equal(testCase.date) || ruleID != testCase.ruleID {
+			t.Errorf("Test %d: expected %s %q, got %s %q", i+1, testCase.date, testCase.ruleID, date, ruleID)
+		}
+	}
+}
+
 // Tests validate the expiry time validator.
 func TestIsValidExpiry(t *testing.T) {
 	testCases := []struct {
 		// Input.
-		duration time.Duration
+		duration   time.Duration
+		signerType credentials.SignatureType
 		// Expected result.
 		err error
 		// Flag to indicate whether the test should pass.
 		shouldPass bool
 	}{
-		{100 * time.Millisecond, errInvalidArgument("Expires cannot be lesser than 1 second."), false},
-		{604801 * time.Second, errInvalidArgument("Expires cannot be greater than 7 days."), false},
-		{0 * time.Second, errInvalidArgument("Expires cannot be lesser than 1 second."), false},
-		{1 * time.Second, nil, true},
-		{10000 * time.Second, nil, true},
-		{999 * time.Second, nil, true},
+		{100 * time.Millisecond, credentials.SignatureV4, errInvalidArgument("Expires must be at least 1s, got 100ms."), false},
+		{604801 * time.Second, credentials.SignatureV4, errInvalidArgument("Expires must be between 1s and 168h for signature v4, got 168h0m1s."), false},
+		{0 * time.Second, credentials.SignatureV4, errInvalidArgument("Expires must be at least 1s, got 0s."), false},
+		{-time.Hour, credentials.SignatureV4, errInvalidArgument("Expires must be at least 1s, got -1h0m0s."), false},
+		{1 * time.Second, credentials.SignatureV4, nil, true},
+		{10000 * time.Second, credentials.SignatureV4, nil, true},
+		{999 * time.Second, credentials.SignatureV4, nil, true},
+		{604801 * time.Second, credentials.SignatureV2, nil, true},
+		{0 * time.Second, credentials.SignatureV2, errInvalidArgument("Expires must be at least 1s, got 0s."), false},
 	}
 
 	for i, testCase := range testCases {
-		err := isValidExpiry(testCase.duration)
+		err := isValidExpiry(testCase.duration, testCase.signerType)
 		if err != nil && testCase.shouldPass {
 			t.Errorf("Test %d: Expected to pass, but failed with: <ERROR> %s", i+1, err.Error())
 		}
@@ -470,3 +499,43 @@ func TestFullObjectChecksum64(t *testing.T) {
 		})
 	}
 }
+
+// Tests the logical size parsed from object response headers.
+func TestToObjectInfoActualSize(t *testing.T) {
+	testCases := []struct {
+		headers    map[string]string
+		size       int64
+		actualSize int64
+		shouldPass bool
+	}{
+		{map[string]string{"Content-Length": "1024"}, 1024, 1024, true},
+		// Client side encrypted object, plaintext size stored as metadata.
+		{map[string]string{"Content-Length": "1040", "X-Amz-Meta-X-Amz-Unencrypted-Content-Length": "1024"}, 1040, 1024, true},
+		{map[string]string{"Content-Length": "300", "X-Minio-Actual-Object-Size": "1024"}, 300, 1024, true},
+		{map[string]string{"Content-Length": "1040", "X-Amz-Object-Size": "1000", "X-Amz-Meta-X-Amz-Unencrypted-Content-Length": "1024"}, 1040, 1000, true},
+		// Invalid sizes are ignored in favor of the next header or the transfer size.
+		{map[string]string{"Content-Length": "1040", "X-Amz-Meta-X-Amz-Unencrypted-Content-Length": "abc"}, 1040, 1040, true},
+		{map[string]string{"Content-Length": "1040", "X-Amz-Object-Size": "-1"}, 1040, 1040, true},
+		{map[string]string{"Content-Length": "1040", "X-Amz-Object-Size": "1e3", "X-Minio-Actual-Object-Size": "1000"}, 1040, 1000, true},
+	}
+	for i, testCase := range testCases {
+		h := make(http.Header)
+		h.Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		for k, v := range testCase.headers {
+			h.Set(k, v)
+		}
+		objInfo, err := ToObjectInfo("bucket", "object", h)
+		if testCase.shouldPass != (err == nil) {
+			t.Fatalf("Test %d: expected shouldPass %t, got error %v", i+1, testCase.shouldPass, err)
+		}
+		if err != nil {
+			continue
+		}
+		if objInfo.Size != testCase.size {
+			t.Errorf("Test %d: expected size %d, got %d", i+1, testCase.size, objInfo.Size)
+		}
+		if objInfo.ActualSize != testCase.actualSize {
+			t.Errorf("Test %d: expected actual size %d, got %d", i+1, testCase.actualSize, objInfo.ActualSize)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultWorkers is the number of requests sent concurrently by calls
+// working on many buckets or objects at once.
+const defaultWorkers = 4
+
+// workerGroup runs functions on a bounded number of goroutines, the
+// first error cancels the context of the group and is kept for Wait.
+type workerGroup struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	slots  chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+}
+
+// newWorkerGroup returns a group running at most n functions at once,
+// defaultWorkers when n is not positive, along with its context.
+func newWorkerGroup(ctx context.Context, n int) (*workerGroup, context.Context) {
+	if n <= 0 {
+		n = defaultWorkers
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &workerGroup{
+		ctx:    ctx,
+		cancel: cancel,
+		slots:  make(chan struct{}, n),
+	}, ctx
+}
+
+// Go runs fn once a worker is free, fn is skipped when the context of
+// the group is done first.
+func (g *workerGroup) Go(fn func() error) {
+	if g.ctx.Err() != nil {
+		return
+	}
+	select {
+	case g.slots <- struct{}{}:
+	case <-g.ctx.Done():
+		return
+	}
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			<-g.slots
+			g.wg.Done()
+		}()
+		if err := fn(); err != nil {
+			g.setErr(err)
+		}
+	}()
+}
+
+// setErr keeps err if it is the first error and cancels the group.
+func (g *workerGroup) setErr(err error) {
+	g.once.Do(func() {
+		g.err = err
+		g.cancel()
+	})
+}
+
+// Wait waits for all running functions and returns the first error.
+func (g *workerGroup) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
@@ -0,0 +1,72 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2015-2024 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerGroup(t *testing.T) {
+	var running, maxRunning, calls int32
+	g, _ := newWorkerGroup(context.Background(), 2)
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			atomic.AddInt32(&calls, 1)
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 10 || maxRunning != 2 {
+		t.Fatalf("Expected 10 calls with at most 2 running, got %d calls with %d running", calls, maxRunning)
+	}
+
+	// The first error cancels the group, later functions are skipped.
+	errFailed := errors.New("failed")
+	calls = 0
+	g, ctx := newWorkerGroup(context.Background(), 1)
+	g.Go(func() error {
+		atomic.AddInt32(&calls, 1)
+		return errFailed
+	})
+	<-ctx.Done()
+	g.Go(func() error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	if err := g.Wait(); !errors.Is(err, errFailed) {
+		t.Fatalf("Expected the first error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("Expected later functions to be skipped, got %d calls", calls)
+	}
+}